@@ -112,6 +112,12 @@ const AnalysisProgressTitle = "Analyzing Dependencies"
 //
 // Notifications of progress may be sent to the optional reporter.
 func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Package, reporter *progress.Tracker) ([]*Diagnostic, error) {
+	return s.AnalyzeWith(ctx, pkgs, func(a *settings.Analyzer) bool { return a.Enabled(s.Options()) }, reporter)
+}
+
+// AnalyzeWith is like Analyze, but applies the analyzers for which enabled
+// returns true instead of those enabled by the snapshot's options.
+func (s *Snapshot) AnalyzeWith(ctx context.Context, pkgs map[PackageID]*metadata.Package, enabled func(*settings.Analyzer) bool, reporter *progress.Tracker) ([]*Diagnostic, error) {
 	start := time.Now() // for progress reporting
 
 	var tagStr string // sorted comma-separated list of PackageIDs
@@ -133,7 +139,7 @@ func (s *Snapshot) Analyze(ctx context.Context, pkgs map[PackageID]*metadata.Pac
 		enabledAnalyzers []*analysis.Analyzer // enabled subset + transitive requirements
 	)
 	for _, a := range settings.AllAnalyzers {
-		if a.Enabled(s.Options()) {
+		if enabled(a) {
 			toSrc[a.Analyzer()] = a
 			enabledAnalyzers = append(enabledAnalyzers, a.Analyzer())
 		}
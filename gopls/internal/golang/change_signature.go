@@ -13,7 +13,10 @@ import (
 	"go/parser"
 	"go/token"
 	"go/types"
+	"maps"
 	"regexp"
+	"slices"
+	"strings"
 
 	goastutil "golang.org/x/tools/go/ast/astutil"
 	"golang.org/x/tools/gopls/internal/cache"
@@ -138,13 +141,36 @@ func removeParam(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 // [2, 0, 1], the resulting changed signature is Foo(c, a, b int). If newParams
 // omits an index of the original signature, that parameter is removed.
 //
+// To also add parameters, use changeSignature.
+//
 // This operation is a work in progress. Remaining TODO:
-//   - Handle adding parameters.
 //   - Handle adding/removing/reordering results.
 //   - Improve the extra newlines in output.
 //   - Stream type checking via ForEachPackage.
 //   - Avoid unnecessary additional type checking.
 func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range, newParams []int) ([]protocol.DocumentChange, error) {
+	params := make([]signatureParam, len(newParams))
+	for i, old := range newParams {
+		params[i] = signatureParam{oldIndex: old}
+	}
+	return changeSignature(ctx, snapshot, pkg, pgf, rng, params, nil)
+}
+
+// A signatureParam is a parameter of a changed signature: the parameter of
+// the original signature at oldIndex or, if field is set, a new parameter
+// such as "x int", for which existing calls pass the expression def.
+type signatureParam struct {
+	oldIndex   int
+	field, def string
+}
+
+// changeSignature is ChangeSignature, generalized to add parameters. The
+// default argument of a new parameter is resolved in the scope of the
+// declaring file, and moved to each call by the inliner.
+//
+// If hooks.skipped is set, calls that cannot be rewritten are reported to
+// it and left unchanged rather than failing the operation.
+func changeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, rng protocol.Range, newParams []signatureParam, hooks *inlineAllHooks) ([]protocol.DocumentChange, error) {
 	// Changes to our heuristics for whether we can remove a parameter must also
 	// be reflected in the canRemoveParameter helper.
 	if perrors, terrors := pkg.ParseErrors(), pkg.TypeErrors(); len(perrors) > 0 || len(terrors) > 0 {
@@ -174,11 +200,11 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		typ      types.Type
 	}
 
-	var newParamFields []flatField
+	var oldParamFields []flatField
 	for id, field := range astutil.FlatFields(info.decl.Type.Params) {
 		typ := pkg.TypesInfo().TypeOf(field.Type)
 		if typ == nil {
-			return nil, fmt.Errorf("missing field type for field #%d", len(newParamFields))
+			return nil, fmt.Errorf("missing field type for field #%d", len(oldParamFields))
 		}
 		field := flatField{
 			typeExpr: field.Type,
@@ -187,13 +213,43 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		if id != nil {
 			field.name = id.Name
 		}
-		newParamFields = append(newParamFields, field)
+		oldParamFields = append(oldParamFields, field)
 	}
 
-	// Select the new parameter fields.
-	newParamFields, ok := selectElements(newParamFields, newParams)
-	if !ok {
-		return nil, fmt.Errorf("failed to apply parameter transformation %v", newParams)
+	// Select the new parameter fields, and create the added ones.
+	var (
+		newParamFields []flatField
+		newImports     []*types.Package // of the declaring file, for added parameters
+	)
+	for _, param := range newParams {
+		if param.field == "" {
+			if param.oldIndex < 0 || param.oldIndex >= len(oldParamFields) {
+				return nil, fmt.Errorf("no parameter #%d: %s has %d parameters", param.oldIndex, info.decl.Name.Name, len(oldParamFields))
+			}
+			newParamFields = append(newParamFields, oldParamFields[param.oldIndex])
+			continue
+		}
+		name, typ, imports, err := checkAddedParam(pkg, pgf, info.decl, newParams, param)
+		if err != nil {
+			return nil, err
+		}
+		for _, imp := range imports {
+			if !slices.Contains(newImports, imp) {
+				newImports = append(newImports, imp)
+			}
+		}
+		// The type is spliced in as text: the new declaration is only ever
+		// printed, and parsed again.
+		newParamFields = append(newParamFields, flatField{
+			name:     name,
+			typeExpr: ast.NewIdent(types.TypeString(typ, typesinternal.FileQualifier(pgf.File, pkg.Types()))),
+			typ:      typ,
+		})
+	}
+	for i, f := range newParamFields {
+		if _, ok := f.typeExpr.(*ast.Ellipsis); ok && i < len(newParamFields)-1 {
+			return nil, fmt.Errorf("variadic parameter %s must be last", f.name)
+		}
 	}
 
 	// writeFields performs the regrouping of named fields.
@@ -251,8 +307,14 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		// a map rather than a slice, as not every old param need exist in
 		// newParams.
 		oldParams := make(map[int]int)
-		for new, old := range newParams {
-			oldParams[old] = new
+		for new, param := range newParams {
+			if param.field == "" {
+				oldParams[param.oldIndex] = new
+			} else {
+				// The argument of an added parameter is its default, spliced in
+				// as text like its type.
+				args[new] = ast.NewIdent(param.def)
+			}
 		}
 		blanks := 0
 		paramIndex := 0 // global param index.
@@ -295,6 +357,8 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		params:   params,
 		callArgs: args,
 		variadic: variadic,
+		imports:  newImports,
+		hooks:    hooks,
 	})
 	if err != nil {
 		return nil, err
@@ -319,6 +383,9 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 		if err != nil {
 			return nil, err
 		}
+		if src, err = addImports(src, newImports); err != nil {
+			return nil, bug.Errorf("adding imports to the declaring file: %v", err)
+		}
 		newContent[pgf.URI] = src
 	}
 
@@ -345,6 +412,210 @@ func ChangeSignature(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.P
 	return changes, nil
 }
 
+// checkAddedParam checks the new parameter param of decl, one of params,
+// declared in pgf. It returns its name and type, and the packages that the
+// type or the default refer to and pgf does not import yet.
+func checkAddedParam(pkg *cache.Package, pgf *parsego.File, decl *ast.FuncDecl, params []signatureParam, param signatureParam) (string, types.Type, []*types.Package, error) {
+	expr, err := parser.ParseExpr("func(" + param.field + ")")
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid parameter %q: %v", param.field, err)
+	}
+	ftype, ok := expr.(*ast.FuncType)
+	if !ok || len(ftype.Params.List) != 1 || len(ftype.Params.List[0].Names) != 1 {
+		return "", nil, nil, fmt.Errorf("invalid parameter %q: want a name and a type, such as \"x int\"", param.field)
+	}
+	field := ftype.Params.List[0]
+	name := field.Names[0].Name
+	if _, ok := field.Type.(*ast.Ellipsis); ok {
+		return "", nil, nil, fmt.Errorf("new parameter %s cannot be variadic", name)
+	}
+
+	// The name must not change the meaning of any name in decl.
+	if name != "_" {
+		used := false
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if id, ok := n.(*ast.Ident); ok && id.Name == name {
+				used = true
+			}
+			return !used
+		})
+		if used {
+			return "", nil, nil, fmt.Errorf("new parameter name %s is already used in %s", name, decl.Name.Name)
+		}
+		for _, other := range params {
+			if f := strings.Fields(other.field); other != param && len(f) > 0 && f[0] == name {
+				return "", nil, nil, fmt.Errorf("two new parameters are named %s", name)
+			}
+		}
+	}
+
+	// The type and the default are resolved in the file scope, as they
+	// are in the delegating wrapper (see rewriteCalls), and in the
+	// packages the file is to import for them.
+	var def ast.Expr
+	if param.def != "" {
+		if def, err = parser.ParseExpr(param.def); err != nil {
+			return "", nil, nil, fmt.Errorf("invalid default %q of new parameter %s: %v", param.def, name, err)
+		}
+	}
+	scopePkg, pos, imports, err := paramScope(pkg, pgf, decl, field.Type, def)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("new parameter %s: %v", name, err)
+	}
+	fset := pkg.FileSet()
+	tv, err := types.Eval(fset, scopePkg, pos, types.ExprString(field.Type))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid type of new parameter %s: %v", name, typeErrorMsg(err))
+	}
+	if !tv.IsType() {
+		return "", nil, nil, fmt.Errorf("invalid type of new parameter %s: %s is not a type", name, types.ExprString(field.Type))
+	}
+	if def == nil {
+		return "", nil, nil, fmt.Errorf("new parameter %s needs a default argument for existing calls", name)
+	}
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	if err := types.CheckExpr(fset, scopePkg, pos, def, info); err != nil {
+		return "", nil, nil, fmt.Errorf("invalid default %q of new parameter %s: %v", param.def, name, typeErrorMsg(err))
+	}
+	if dv := info.Types[def]; !dv.IsValue() || !types.AssignableTo(dv.Type, tv.Type) {
+		return "", nil, nil, fmt.Errorf("default %q of new parameter %s is not a value of type %s", param.def, name, tv.Type)
+	}
+	// In the wrapper, the parameters of decl shadow the names of its file.
+	for _, obj := range info.Uses {
+		if _, ok := obj.(*types.PkgName); !ok && obj.Pkg() != pkg.Types() && obj.Pkg() != nil {
+			continue // a qualified identifier
+		}
+		if decl.Recv != nil && slices.ContainsFunc(decl.Recv.List, hasName(obj.Name())) ||
+			slices.ContainsFunc(decl.Type.Params.List, hasName(obj.Name())) {
+			return "", nil, nil, fmt.Errorf("default %q of new parameter %s refers to %s, which %s declares as a parameter", param.def, name, obj.Name(), decl.Name.Name)
+		}
+	}
+	return name, tv.Type, imports, nil
+}
+
+// paramScope returns the package and position at which to type-check
+// exprs, the type and default of a new parameter of decl, declared in pgf,
+// and the packages that exprs refer to and pgf does not import.
+//
+// That is the file scope at decl, unless exprs refer to a package the file
+// does not import: such a package is looked up by name among the
+// dependencies of pkg, the nearest first, and exprs are checked in a
+// package scope that holds the file scope and that package too.
+func paramScope(pkg *cache.Package, pgf *parsego.File, decl *ast.FuncDecl, exprs ...ast.Expr) (*types.Package, token.Pos, []*types.Package, error) {
+	fileScope := pkg.TypesInfo().Scopes[pgf.File]
+	if fileScope == nil {
+		return nil, token.NoPos, nil, bug.Errorf("no scope for %s", pgf.URI)
+	}
+	var missing []string // names of packages not imported by the file
+	for _, expr := range exprs {
+		if expr == nil {
+			continue
+		}
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if id, ok := sel.X.(*ast.Ident); ok && !slices.Contains(missing, id.Name) {
+					if _, obj := fileScope.LookupParent(id.Name, token.NoPos); obj == nil {
+						missing = append(missing, id.Name)
+					}
+				}
+			}
+			return true
+		})
+	}
+	if len(missing) == 0 {
+		return pkg.Types(), decl.Pos(), nil, nil
+	}
+
+	// Search the dependencies breadth first.
+	found := make(map[string][]*types.Package)
+	seen := make(map[*types.Package]bool)
+	for level := pkg.Types().Imports(); len(level) > 0 && len(found) < len(missing); {
+		var next []*types.Package
+		nearest := make(map[string][]*types.Package)
+		for _, dep := range level {
+			if seen[dep] {
+				continue
+			}
+			seen[dep] = true
+			if _, ok := found[dep.Name()]; !ok && slices.Contains(missing, dep.Name()) {
+				nearest[dep.Name()] = append(nearest[dep.Name()], dep)
+			}
+			next = append(next, dep.Imports()...)
+		}
+		maps.Copy(found, nearest)
+		level = next
+	}
+
+	scopePkg := types.NewPackage(pkg.Types().Path(), pkg.Types().Name())
+	for _, scope := range []*types.Scope{fileScope, pkg.Types().Scope()} {
+		for _, name := range scope.Names() {
+			scopePkg.Scope().Insert(scope.Lookup(name))
+		}
+	}
+	var imports []*types.Package
+	for _, name := range missing {
+		switch deps := found[name]; len(deps) {
+		case 0:
+			return nil, token.NoPos, nil, fmt.Errorf("undefined: %s (not a package that %s depends on)", name, pkg.Types().Path())
+		case 1:
+			imports = append(imports, deps[0])
+			scopePkg.Scope().Insert(types.NewPkgName(token.NoPos, scopePkg, name, deps[0]))
+		default:
+			var paths []string
+			for _, dep := range deps {
+				paths = append(paths, dep.Path())
+			}
+			return nil, token.NoPos, nil, fmt.Errorf("ambiguous package name %s: import it in %s first (candidates: %s)", name, pgf.URI.Path(), strings.Join(paths, ", "))
+		}
+	}
+	return scopePkg, token.NoPos, imports, nil
+}
+
+// addImports returns src, the content of a Go file, importing pkgs too.
+func addImports(src []byte, pkgs []*types.Package) ([]byte, error) {
+	if len(pkgs) == 0 {
+		return src, nil
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	added := false
+	for _, pkg := range pkgs {
+		if goastutil.AddImport(fset, file, pkg.Path()) {
+			added = true
+		}
+	}
+	if !added {
+		return src, nil
+	}
+	var out bytes.Buffer
+	if err := format.Node(&out, fset, file); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// typeErrorMsg returns the message of a type error in an expression that
+// is not part of any file, without its meaningless position.
+func typeErrorMsg(err error) string {
+	if terr, ok := err.(types.Error); ok {
+		return terr.Msg
+	}
+	return err.Error()
+}
+
+// hasName returns a function that reports whether a field declares name.
+func hasName(name string) func(*ast.Field) bool {
+	return func(field *ast.Field) bool {
+		return slices.ContainsFunc(field.Names, func(id *ast.Ident) bool { return id.Name == name })
+	}
+}
+
 // rewriteSignature rewrites the signature of the declIdx'th declaration in src
 // to use the signature of newDecl (described by fset).
 //
@@ -484,6 +755,8 @@ type signatureRewrite struct {
 	params            *ast.FieldList
 	callArgs          []ast.Expr
 	variadic          bool
+	imports           []*types.Package // to add to pgf, for the new declaration
+	hooks             *inlineAllHooks
 }
 
 // rewriteCalls returns the document changes required to rewrite the
@@ -620,6 +893,9 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 		// by returning the modified AST from replaceDecl. Investigate if that is
 		// accurate.
 		modifiedSrc = append(modifiedSrc, []byte("\n\n"+FormatNode(fset, wrapper))...)
+		if modifiedSrc, err = addImports(modifiedSrc, rw.imports); err != nil {
+			return nil, bug.Errorf("adding imports to the declaring file: %v", err)
+		}
 		modifiedFile, err = parser.ParseFile(rw.pkg.FileSet(), rw.pgf.URI.Path(), modifiedSrc, parser.ParseComments|parser.SkipObjectResolution)
 		if err != nil {
 			return nil, err
@@ -644,7 +920,7 @@ func rewriteCalls(ctx context.Context, rw signatureRewrite) (map[protocol.Docume
 		Logf:          logf,
 		IgnoreEffects: true,
 	}
-	return inlineAllCalls(ctx, rw.snapshot, rw.pkg, rw.pgf, rw.origDecl, calleeInfo, post, opts)
+	return inlineAllCalls(ctx, rw.snapshot, rw.pkg, rw.pgf, rw.origDecl, calleeInfo, post, rw.hooks, opts)
 }
 
 // reTypeCheck re-type checks orig with new file contents defined by fileMask.
@@ -754,22 +1030,6 @@ func reTypeCheck(logf func(string, ...any), orig *cache.Package, fileMask map[pr
 // TODO(golang/go#63472): this looks wrong with the new Go version syntax.
 var goVersionRx = regexp.MustCompile(`^go([1-9][0-9]*)\.(0|[1-9][0-9]*)$`)
 
-// selectElements returns a new array of elements of s indicated by the
-// provided list of indices. It returns false if any index was out of bounds.
-//
-// For example, given the slice []string{"a", "b", "c", "d"}, the
-// indices []int{3, 0, 1} results in the slice []string{"d", "a", "b"}.
-func selectElements[T any](s []T, indices []int) ([]T, bool) {
-	res := make([]T, len(indices))
-	for i, index := range indices {
-		if index < 0 || index >= len(s) {
-			return nil, false
-		}
-		res[i] = s[index]
-	}
-	return res, true
-}
-
 // replaceFileDecl replaces old with new in the file described by pgf.
 //
 // TODO(rfindley): generalize, and combine with rewriteSignature.
@@ -5,6 +5,7 @@
 package golang
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"go/ast"
@@ -23,6 +24,12 @@ import (
 	"golang.org/x/tools/internal/refactor/inline"
 )
 
+// inlineAllHooks observes the calls of inlineAllCalls.
+type inlineAllHooks struct {
+	inlined func(*inline.Result)           // called with the result of each inlining
+	skipped func(protocol.Location, error) // called with each call that is not inlined, and why
+}
+
 // inlineAllCalls inlines all calls to the original function declaration
 // described by callee, returning the resulting modified file content.
 //
@@ -37,6 +44,10 @@ import (
 // rewriting. The delegated function has a fake name that doesn't exist in the
 // snapshot, and so we can't re-type check until we replace this fake name.
 //
+// If hooks is non-nil, it observes the inlining of each call and, with
+// hooks.skipped, calls that cannot be inlined are left as they are instead
+// of failing the whole operation.
+//
 // TODO(rfindley): this only works because removing a parameter is a very
 // narrow operation. A better solution would be to allow for ad-hoc snapshots
 // that expose the full machinery of real snapshots: minimal invalidation,
@@ -47,7 +58,7 @@ import (
 //
 // The code below notes where are assumptions are made that only hold true in
 // the case of parameter removal (annotated with 'Assumption:')
-func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, origDecl *ast.FuncDecl, callee *inline.Callee, post func([]byte) []byte, opts *inline.Options) (_ map[protocol.DocumentURI][]byte, inlineErr error) {
+func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, origDecl *ast.FuncDecl, callee *inline.Callee, post func([]byte) []byte, hooks *inlineAllHooks, opts *inline.Options) (_ map[protocol.DocumentURI][]byte, inlineErr error) {
 	// Collect references.
 	var refs []protocol.Location
 	{
@@ -146,17 +157,23 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 		name, _ = path[0].(*ast.Ident)
 
 		// TODO(rfindley): handle method expressions correctly.
-		if _, ok := path[1].(*ast.SelectorExpr); ok {
+		fun := path[0]
+		if sel, ok := path[1].(*ast.SelectorExpr); ok {
+			fun = sel
 			call, _ = path[2].(*ast.CallExpr)
 		} else {
 			call, _ = path[1].(*ast.CallExpr)
 		}
-		if name == nil || call == nil {
+		if name == nil || call == nil || ast.Unparen(call.Fun) != fun {
 			// TODO(rfindley): handle this case with eta-abstraction:
 			// a reference to the target function f in a non-call position
 			//    use(f)
 			// is replaced by
 			//    use(func(...) { f(...) })
+			if hooks != nil && hooks.skipped != nil {
+				hooks.skipped(ref, fmt.Errorf("not a call: the function is used as a value"))
+				continue
+			}
 			return nil, fmt.Errorf("cannot inline: found non-call function reference %v", ref)
 		}
 
@@ -170,10 +187,16 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 		}
 
 		if hasTypeErrors {
+			if hooks != nil && hooks.skipped != nil {
+				hooks.skipped(ref, fmt.Errorf("the call has type errors"))
+			}
 			continue
 		}
 
 		if typeutil.StaticCallee(refpkg.TypesInfo(), call) == nil {
+			if hooks != nil && hooks.skipped != nil {
+				hooks.skipped(ref, fmt.Errorf("not a static call"))
+			}
 			continue // dynamic call
 		}
 
@@ -224,6 +247,9 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			}
 		}
 
+		// orig are the calls as they are in the original file; each
+		// iteration below inlines or skips the next one.
+		orig, next := calls, 0
 		currentCall := 0
 		for currentCall < len(calls) {
 			caller := &inline.Caller{
@@ -235,9 +261,23 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 				CountUses: nil, // TODO(adonovan): opt: amortize across callInfo.pkg
 			}
 			res, err := inline.Inline(caller, callee, opts)
+			origCall := orig[next]
+			next++
 			if err != nil {
+				if hooks != nil && hooks.skipped != nil {
+					loc, lerr := callInfo.pgf.NodeLocation(origCall)
+					if lerr != nil {
+						return nil, lerr
+					}
+					hooks.skipped(loc, err)
+					currentCall++
+					continue
+				}
 				return nil, fmt.Errorf("inlining failed: %v", err)
 			}
+			if hooks != nil && hooks.inlined != nil {
+				hooks.inlined(res)
+			}
 
 			// applyEdits transforms content by applying the specified edits
 			// (whose positions are defined by fset), reformatting the file, and
@@ -344,7 +384,9 @@ func inlineAllCalls(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Pa
 			}
 		}
 
-		result[callInfo.pgf.URI] = content
+		if !bytes.Equal(content, callInfo.pgf.Src) { // all calls may be skipped
+			result[callInfo.pgf.URI] = content
+		}
 	}
 	return result, nil
 }
@@ -0,0 +1,212 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// This file adds tests for the bridge: a new table-driven test from the
+// "Add test for FUNC" command, or, if the function already has one, a
+// case in its table.
+
+// LLMAddTest returns the changes that add a test of the function named
+// at rng of fh. If the package already has a test of it, named as
+// AddTestForFunc names tests, the changes add the test case testCase, a
+// table element such as `{name: "empty", in: ""}`, or else a skeleton
+// case, to the first table of test cases in the test.
+func LLMAddTest(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, testCase string) ([]protocol.DocumentChange, *api.OAddTestResult, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := identObject(pkg, pgf, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s is not a function or method", obj.Name())
+	}
+	declPkg, declPGF, declPos, err := NarrowestDeclaringPackage(ctx, snapshot, pkg, fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	name, err := testName(fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &api.OAddTestResult{Function: fn.Name(), Test: name}
+
+	// Add a case to the existing test, if any.
+	for _, uri := range testFiles(snapshot, declPkg) {
+		testPkg, testPGF, err := NarrowestPackageForFile(ctx, snapshot, uri)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, decl := range testPGF.File.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Recv == nil && decl.Name.Name == name {
+				result.TestFile, result.AddedCase = uri.Path(), true
+				changes, err := addTestCase(ctx, snapshot, testPkg, testPGF, decl, testCase)
+				if err != nil {
+					return nil, nil, err
+				}
+				return changes, result, nil
+			}
+		}
+	}
+	if testCase != "" {
+		return nil, nil, fmt.Errorf("there is no %s to add the test case to; omit test_case to create the test", name)
+	}
+
+	loc, err := declPGF.PosLocation(declPos, declPos)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, show, err := AddTestForFunc(ctx, snapshot, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.TestFile = show.URI.Path()
+	return changes, result, nil
+}
+
+// testFiles returns the _test.go files of the in-package and external
+// tests of pkg, in order.
+func testFiles(snapshot *cache.Snapshot, pkg *cache.Package) []protocol.DocumentURI {
+	path := pkg.Metadata().PkgPath
+	if pkg.Metadata().ForTest != "" {
+		path = pkg.Metadata().ForTest
+	}
+	var uris []protocol.DocumentURI
+	for _, mp := range snapshot.MetadataGraph().Packages {
+		if mp.ForTest != path {
+			continue
+		}
+		for _, uri := range mp.CompiledGoFiles {
+			if strings.HasSuffix(uri.Path(), "_test.go") && !slices.Contains(uris, uri) {
+				uris = append(uris, uri)
+			}
+		}
+	}
+	slices.Sort(uris)
+	return uris
+}
+
+// addTestCase returns the changes that add testCase, or a case with the
+// zero value of each field, to the first slice or map of structs that
+// test declares.
+func addTestCase(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, test *ast.FuncDecl, testCase string) ([]protocol.DocumentChange, error) {
+	var (
+		table  *ast.CompositeLit
+		key    types.Type // of a map table
+		fields *types.Struct
+	)
+	ast.Inspect(test.Body, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if table != nil || !ok {
+			return table == nil
+		}
+		var k, elem types.Type
+		switch t := pkg.TypesInfo().TypeOf(lit).(type) {
+		case *types.Slice:
+			elem = t.Elem()
+		case *types.Map:
+			k, elem = t.Key(), t.Elem()
+		default:
+			return true
+		}
+		if st, ok := elem.Underlying().(*types.Struct); ok {
+			table, key, fields = lit, k, st
+			return false
+		}
+		return true
+	})
+	if table == nil {
+		pos := safetoken.StartPosition(pkg.FileSet(), test.Pos())
+		return nil, fmt.Errorf("%s (%s:%d) has no table of test cases to add to", test.Name.Name, pgf.URI.Path(), pos.Line)
+	}
+
+	elem := strings.TrimSuffix(strings.TrimSpace(testCase), ",")
+	if elem == "" {
+		qual := typesinternal.FileQualifier(pgf.File, pkg.Types())
+		zero := func(name string, t types.Type) string {
+			if name == "name" && types.Identical(t, types.Typ[types.String]) {
+				return `"TODO"`
+			}
+			s, _ := typesinternal.ZeroString(t, qual)
+			return s
+		}
+		var values []string
+		for f := range fields.Fields() {
+			values = append(values, f.Name()+": "+zero(f.Name(), f.Type()))
+		}
+		elem = "{" + strings.Join(values, ", ") + "}"
+		if key != nil {
+			elem = zero("name", key) + ": " + elem
+		}
+	}
+
+	// Insert the case on a line of its own before the closing brace of
+	// the table, or after the last case of a table on one line.
+	src := pgf.Src
+	rbrace, err := safetoken.Offset(pgf.Tok, table.Rbrace)
+	if err != nil {
+		return nil, err
+	}
+	lineStart := bytes.LastIndexByte(src[:rbrace], '\n') + 1
+	var insert string
+	if indent := src[lineStart:rbrace]; len(bytes.TrimSpace(indent)) == 0 {
+		prefix := string(indent) + "\t"
+		rbrace = lineStart
+		insert = prefix + strings.ReplaceAll(elem, "\n", "\n"+prefix) + ",\n"
+	} else if len(table.Elts) > 0 {
+		last, err := safetoken.Offset(pgf.Tok, table.Elts[len(table.Elts)-1].End())
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Contains(src[last:rbrace], []byte(",")) {
+			insert = " " + elem
+		} else {
+			insert = ", " + elem
+		}
+	} else {
+		insert = elem
+	}
+	var buf bytes.Buffer
+	buf.Write(src[:rbrace])
+	buf.WriteString(insert)
+	buf.Write(src[rbrace:])
+	if _, err := parser.ParseFile(token.NewFileSet(), pgf.URI.Path(), buf.Bytes(), parser.SkipObjectResolution); err != nil {
+		return nil, fmt.Errorf("the test case %q is not a valid table element: %v", elem, err)
+	}
+
+	fh, err := snapshot.ReadFile(ctx, pgf.URI)
+	if err != nil {
+		return nil, err
+	}
+	edits, err := protocol.EditsFromDiffEdits(pgf.Mapper, diff.Bytes(src, buf.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	return []protocol.DocumentChange{protocol.DocumentChangeEdit(fh, edits)}, nil
+}
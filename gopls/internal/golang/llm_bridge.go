@@ -2,6 +2,7 @@ package golang
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"go/ast"
@@ -9,7 +10,10 @@ import (
 	"go/printer"
 	"go/token"
 	"go/types"
+	"slices"
 	"strings"
+	"sync"
+	"unicode"
 
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/metadata"
@@ -39,6 +43,9 @@ type ResolveNodeResult struct {
 	EnclosingFunc string
 	// IsDefinition indicates whether this node is a definition (not just a reference)
 	IsDefinition bool
+	// Warning is set when the resolved symbol disagrees with the locator's
+	// SignatureSnippet.
+	Warning *api.ResolutionWarning
 }
 
 // SourceContext provides rich context about a symbol, suitable for LLM consumption.
@@ -54,6 +61,9 @@ type SourceContext struct {
 	// Symbol name (e.g., "Start")
 	Symbol string `json:"symbol"`
 
+	// PackagePath is the path of the declaring package, if known.
+	PackagePath string `json:"package_path,omitempty"`
+
 	// Kind helps the LLM distinguish between "struct", "interface", "method", "func".
 	// This is cheaply available from types.Object.
 	Kind string `json:"kind"`
@@ -78,14 +88,27 @@ type SourceContext struct {
 //   - Optional kind filtering (function, method, struct, etc.)
 //   - Fuzzy line hint matching (for disambiguation)
 //
+// If the locator does not resolve to exactly one symbol, the error is an
+// *api.ResolutionError carrying the candidates or near misses to retry with.
+//
 // This function bypasses the LSP protocol layer entirely and works directly
 // with gopls internal types.
 func ResolveNode(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, locator api.SymbolLocator) (*ResolveNodeResult, error) {
 	// Get the package and parse tree for the context file
-	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if _, err := fh.Content(); err != nil {
+		return nil, fileNotInWorkspaceError(fh, locator)
+	}
+	mps, err := snapshot.MetadataForFile(ctx, fh.URI(), true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get package for %s: %w", locator.ContextFile, err)
 	}
+	if len(mps) == 0 {
+		return nil, fileNotInWorkspaceError(fh, locator)
+	}
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, packageNotLoadedError(locator, mps[0], err)
+	}
 
 	// Build a cursor for efficient AST traversal
 	info := pkg.TypesInfo()
@@ -94,6 +117,7 @@ func ResolveNode(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 	scopeStack := []scopeFrame{{node: pgf.File, enclosingFunc: ""}}
 
 	var candidates []ResolveNodeResult
+	var rejected []ResolveNodeResult // name matched, filters did not
 	var bestCandidate *ResolveNodeResult
 
 	// Walk the AST to find matching symbols
@@ -127,12 +151,6 @@ func ResolveNode(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 		// Extract parent scope and kind information
 		parentInfo := extractNodeParentAndKind(ident, obj, scopeStack)
 
-		// Apply filters
-		if !matchesLocatorFilters(locator, parentInfo.parent, parentInfo.kind).passed {
-			return true
-		}
-
-		// Found a match!
 		candidate := ResolveNodeResult{
 			Node:          n,
 			Pos:           ident.Pos(),
@@ -141,6 +159,14 @@ func ResolveNode(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 			IsDefinition:  isDef,
 		}
 
+		// Apply filters
+		if !matchesLocatorFilters(locator, parentInfo.parent, parentInfo.kind).passed {
+			rejected = append(rejected, candidate)
+			return true
+		}
+
+		// Found a match!
+
 		candidates = append(candidates, candidate)
 		bestCandidate = selectBestCandidate(bestCandidate, &candidate, pgf, locator, isDef)
 
@@ -149,10 +175,15 @@ func ResolveNode(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 
 	if bestCandidate == nil {
 		if len(candidates) == 0 {
-			return nil, fmt.Errorf("symbol '%s' not found in file", locator.SymbolName)
+			return nil, notFoundError(ctx, snapshot, pkg, pgf, locator, rejected)
 		}
 		bestCandidate = &candidates[0]
 	}
+	if distinct := ambiguousCandidates(candidates, locator); distinct != nil {
+		return nil, ambiguousError(pkg, locator, distinct)
+	}
+
+	bestCandidate.Warning = verifySignatureSnippet(locator, bestCandidate.Object)
 
 	return bestCandidate, nil
 }
@@ -167,11 +198,11 @@ func ResolveNode(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle,
 //   - Find all implementations of an interface method
 //   - Discover type hierarchies in the codebase
 //
-// Limitations:
-//   - Only finds implementations of interfaces defined in the codebase
-//   - Does NOT work with standard library interfaces (io.Reader, error, fmt.Stringer, etc.)
-//   - May not detect method promotion from embedded structs
-//   - Requires the interface to be defined in the workspace (not vendored/external)
+// The interface may be declared anywhere, including the standard library
+// (io.Reader, error, fmt.Stringer, etc.) and dependencies; its
+// implementations are searched for in the workspace. Conversely, the
+// interfaces a type implements are reported wherever they are declared,
+// if they can be imported from the workspace.
 //
 // Example - Find implementations of Writer interface:
 //
@@ -310,6 +341,9 @@ type candidateScore struct {
 	candidate    *ResolveNodeResult
 	confidence   float64
 	isDefinition bool
+	// similarity is how closely the candidate's signature matches the
+	// locator's SignatureSnippet (0 when no snippet is given).
+	similarity float64
 }
 
 // scoreCandidate calculates a score for a candidate based on the locator's preferences.
@@ -328,24 +362,43 @@ func scoreCandidate(candidate ResolveNodeResult, pgf *parsego.File, locator api.
 		score.confidence = 0.5 // Default confidence when no line hint
 	}
 
+	if locator.SignatureSnippet != "" {
+		score.similarity = signatureSimilarity(locator.SignatureSnippet, candidateSignature(candidate.Object))
+	}
+
 	return score
 }
 
+// similarityEpsilon is the smallest snippet similarity difference that
+// decides between two candidates.
+const similarityEpsilon = 0.01
+
 // selectBestCandidate selects the best candidate from the current best and a new candidate.
+//
+// A SignatureSnippet identifies the symbol, so snippet similarity is compared
+// first; the line hint then picks among occurrences of equally similar symbols.
 func selectBestCandidate(current, new *ResolveNodeResult, pgf *parsego.File, locator api.SymbolLocator, newIsDef bool) *ResolveNodeResult {
 	if current == nil {
 		return new
 	}
 
-	if locator.LineHint > 0 {
-		// Use line hint to pick the best match
+	if locator.LineHint > 0 || locator.SignatureSnippet != "" {
 		newScore := scoreCandidate(*new, pgf, locator)
 		currentScore := scoreCandidate(*current, pgf, locator)
 
-		if newScore.confidence > currentScore.confidence {
+		if d := newScore.similarity - currentScore.similarity; d > similarityEpsilon {
 			return new
+		} else if d < -similarityEpsilon {
+			return current
+		}
+
+		if locator.LineHint > 0 {
+			// Use line hint to pick the best match
+			if newScore.confidence > currentScore.confidence {
+				return new
+			}
+			return current
 		}
-		return current
 	}
 
 	// No line hint, prefer definitions over references
@@ -356,6 +409,131 @@ func selectBestCandidate(current, new *ResolveNodeResult, pgf *parsego.File, loc
 	return current
 }
 
+// ===== SignatureSnippet verification =====
+
+// signatureMismatchThreshold is the snippet similarity below which the
+// resolved symbol is reported as disagreeing with the SignatureSnippet.
+const signatureMismatchThreshold = 0.5
+
+// verifySignatureSnippet compares the locator's SignatureSnippet against the
+// signature of obj and returns a warning if they do not look alike.
+// It returns nil if there is no snippet or no object to compare.
+func verifySignatureSnippet(locator api.SymbolLocator, obj types.Object) *api.ResolutionWarning {
+	if locator.SignatureSnippet == "" || obj == nil {
+		return nil
+	}
+	actual := candidateSignature(obj)
+	sim := signatureSimilarity(locator.SignatureSnippet, actual)
+	if sim >= signatureMismatchThreshold {
+		return nil
+	}
+	return &api.ResolutionWarning{
+		Code:            api.WarningSignatureMismatch,
+		Message:         fmt.Sprintf("resolved symbol '%s' does not match signature_snippet; it may not be the symbol you meant", obj.Name()),
+		ExpectedSnippet: locator.SignatureSnippet,
+		ActualSignature: actual,
+		Similarity:      sim,
+	}
+}
+
+// candidateSignature renders the signature of obj for comparison with a
+// SignatureSnippet. Names from obj's own package are unqualified and other
+// packages are qualified by name, as they would be written in source.
+// Type declarations are rendered without their full underlying type, so that
+// a short snippet such as "type Server struct" is not swamped by fields.
+func candidateSignature(obj types.Object) string {
+	if obj == nil {
+		return ""
+	}
+	qual := func(p *types.Package) string {
+		if p == obj.Pkg() {
+			return ""
+		}
+		return p.Name()
+	}
+	if tn, ok := obj.(*types.TypeName); ok {
+		var under string
+		switch u := tn.Type().Underlying().(type) {
+		case *types.Struct:
+			under = "struct"
+		case *types.Interface:
+			under = "interface"
+		default:
+			under = types.TypeString(u, qual)
+		}
+		if tn.IsAlias() {
+			return fmt.Sprintf("type %s = %s", tn.Name(), under)
+		}
+		return fmt.Sprintf("type %s %s", tn.Name(), under)
+	}
+	return types.ObjectString(obj, qual)
+}
+
+// signatureTokens returns the identifiers in s, excluding Go keywords.
+// Punctuation and keywords such as "func" carry almost no identity, so only
+// names (of the symbol, receiver, parameters, and types) are compared.
+func signatureTokens(s string) []string {
+	var tokens []string
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			if word := s[start:end]; !token.Lookup(word).IsKeyword() {
+				tokens = append(tokens, word)
+			}
+			start = -1
+		}
+	}
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) || (start >= 0 && unicode.IsDigit(r)) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+	}
+	flush(len(s))
+	return tokens
+}
+
+// signatureSimilarity scores how well the snippet matches a candidate
+// signature, from 0 (nothing in common) to 1 (same names).
+//
+// It is the recall-weighted F2 measure over the identifier multisets: a
+// snippet that names only part of the signature (e.g. omits the results)
+// still scores well, while a snippet naming a different receiver or
+// parameter types scores poorly. An empty snippet matches everything.
+func signatureSimilarity(snippet, signature string) float64 {
+	want := signatureTokens(snippet)
+	if len(want) == 0 {
+		return 1
+	}
+	got := signatureTokens(signature)
+	if len(got) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(got))
+	for _, t := range got {
+		counts[t]++
+	}
+	common := 0
+	for _, t := range want {
+		if counts[t] > 0 {
+			counts[t]--
+			common++
+		}
+	}
+	if common == 0 {
+		return 0
+	}
+
+	recall := float64(common) / float64(len(want))
+	precision := float64(common) / float64(len(got))
+	const beta2 = 4 // beta = 2
+	return (1 + beta2) * precision * recall / (beta2*precision + recall)
+}
+
 // Helper functions
 
 // extractSelector extracts the base identifier from a selector expression.
@@ -947,7 +1125,8 @@ func ConvertLSPSymbolKind(kind protocol.SymbolKind) api.SymbolKind {
 
 // ===== LLMRename - Semantic Bridge for Rename Operations =====
 
-// LLMRename performs a dry-run rename operation and returns both unified diff and line changes.
+// LLMRename performs a dry-run rename operation and returns both unified diff and line changes,
+// along with any warning about the symbol resolution.
 //
 // This is a high-level function that:
 // 1. Uses ResolveNode to find the symbol position
@@ -955,27 +1134,66 @@ func ConvertLSPSymbolKind(kind protocol.SymbolKind) api.SymbolKind {
 // 3. Returns both a unified diff format and LLM-friendly line changes
 //
 // This bypasses the LSP protocol layer and works directly with gopls internals.
-func LLMRename(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator, newName string) (string, []api.RenameChange, error) {
+func LLMRename(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator, newName string) (string, []api.RenameChange, *api.ResolutionWarning, error) {
+	changes, warning, err := LLMRenameChanges(ctx, snapshot, locator, newName)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	unifiedDiff, lineChanges, err := FormatRenameChanges(ctx, snapshot, changes)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return unifiedDiff, lineChanges, warning, nil
+}
+
+// FormatRenameChanges renders the document changes of a rename, computed
+// against the file contents of snapshot, as a unified diff and as
+// line-based changes.
+func FormatRenameChanges(ctx context.Context, snapshot *cache.Snapshot, changes []protocol.DocumentChange) (string, []api.RenameChange, error) {
+	// Convert changes to unified diff format
+	unifiedDiff, err := generateUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate unified diff: %w", err)
+	}
+
+	// Convert changes to LLM-friendly line-based format
+	lineChanges, err := generateLineChanges(ctx, snapshot, changes)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate line changes: %w", err)
+	}
+
+	return unifiedDiff, lineChanges, nil
+}
+
+// LLMRenameChanges resolves the symbol described by locator and computes the
+// document changes that rename it to newName, without applying them.
+//
+// The changes are computed against the file contents of snapshot; callers
+// that write them to disk must make sure those contents are still current.
+// The returned warning, if non-nil, reports that the resolved symbol
+// disagrees with the locator's SignatureSnippet.
+func LLMRenameChanges(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator, newName string) ([]protocol.DocumentChange, *api.ResolutionWarning, error) {
 	// First, resolve the node to get the position
 	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(locator.ContextFile))
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to read file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
 	result, err := ResolveNode(ctx, snapshot, fh, locator)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 
 	// Convert token.Pos to protocol.Position
 	pkg, _, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get package: %w", err)
+		return nil, nil, fmt.Errorf("failed to get package: %w", err)
 	}
 
 	posn := pkg.FileSet().Position(result.Pos)
 	if !posn.IsValid() {
-		return "", nil, fmt.Errorf("invalid position for symbol '%s'", locator.SymbolName)
+		return nil, nil, fmt.Errorf("invalid position for symbol '%s'", locator.SymbolName)
 	}
 
 	position := protocol.Position{
@@ -986,22 +1204,9 @@ func LLMRename(ctx context.Context, snapshot *cache.Snapshot, locator api.Symbol
 	// Call the internal Rename function
 	changes, err := Rename(ctx, snapshot, fh, protocol.Range{Start: position, End: position}, newName)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to compute rename: %w", err)
+		return nil, nil, fmt.Errorf("failed to compute rename: %w", err)
 	}
-
-	// Convert changes to unified diff format
-	unifiedDiff, err := generateUnifiedDiff(ctx, snapshot, changes)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate unified diff: %w", err)
-	}
-
-	// Convert changes to LLM-friendly line-based format
-	lineChanges, err := generateLineChanges(ctx, snapshot, changes)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to generate line changes: %w", err)
-	}
-
-	return unifiedDiff, lineChanges, nil
+	return changes, result.Warning, nil
 }
 
 // ===== Symbol Resolution Infrastructure =====
@@ -1029,6 +1234,10 @@ type SymbolInfo struct {
 	// References contains all usage locations for this symbol.
 	// Populated when Options.FindReferences = true.
 	References []SourceContext `json:"references,omitempty"`
+
+	// Warning is set when the resolved symbol disagrees with the locator's
+	// SignatureSnippet.
+	Warning *api.ResolutionWarning `json:"warning,omitempty"`
 }
 
 // ResolveOptions controls what information is fetched during symbol resolution.
@@ -1058,6 +1267,19 @@ type ResolveOptions struct {
 	// Only used when FindImplementations = true.
 	// Default: false
 	IncludeDocs bool `json:"include_docs"`
+
+	// IncludeDependencies also reports the types outside the workspace that
+	// implement an interface. The interfaces that a type implements are
+	// reported wherever they are declared.
+	// Only used when FindImplementations = true.
+	// Default: false
+	IncludeDependencies bool `json:"include_dependencies"`
+
+	// ExcludeTrivial leaves out the interfaces that almost every type
+	// implements by accident, such as error and fmt.Stringer.
+	// Only used when FindImplementations = true.
+	// Default: false
+	ExcludeTrivial bool `json:"exclude_trivial"`
 }
 
 // ResolveSymbol is the unified entry point for all symbol-based operations.
@@ -1108,6 +1330,7 @@ func ResolveSymbol(ctx context.Context, snapshot *cache.Snapshot, locator api.Sy
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve symbol: %w", err)
 	}
+	info.Warning = result.Warning
 
 	if result.Object == nil {
 		return nil, fmt.Errorf("symbol '%s' has no type information", locator.SymbolName)
@@ -1172,19 +1395,51 @@ func ResolveSymbol(ctx context.Context, snapshot *cache.Snapshot, locator api.Sy
 		// Use the internal implementations logic
 		const relation = methodsets.TypeRelation(0) // infer direction
 
-		err = implementationsMsets(ctx, snapshot, pkg, cur, relation, func(_ metadata.PackagePath, _ string, _ bool, loc protocol.Location) {
+		// For an interface, the search covers the forward transitive
+		// closure of the workspace, standard library included; keep to the
+		// workspace unless asked otherwise.
+		workspace := make(map[metadata.PackagePath]bool)
+		if mps, err := snapshot.WorkspaceMetadata(ctx); err == nil {
+			for _, mp := range mps {
+				workspace[mp.PkgPath] = true
+			}
+		}
+		subtypes := isInterfaceQuery(result.Object)
+
+		var mu sync.Mutex // yield is called concurrently
+		add := func(pkgPath metadata.PackagePath, srcCtx SourceContext) {
+			srcCtx.PackagePath = string(pkgPath)
+			mu.Lock()
+			info.Implementations = append(info.Implementations, srcCtx)
+			mu.Unlock()
+		}
+		err = implementationsMsets(ctx, snapshot, pkg, cur, relation, func(pkgPath metadata.PackagePath, typeName string, abstract bool, loc protocol.Location) {
+			if snapshot.IsBuiltin(loc.URI) {
+				pkgPath = "" // a predeclared type, e.g. error
+			} else if !workspace[pkgPath] {
+				if subtypes && !options.IncludeDependencies {
+					return
+				}
+				if !importableFrom(pkgPath, typeName) {
+					return // e.g. fmt.ss or internal/poll.FD
+				}
+			}
+			if options.ExcludeTrivial && abstract && isTrivialInterface(pkgPath, typeName) {
+				return
+			}
+
 			// Get symbol information from the package containing the implementation
 			implPkg, implPgf, err := NarrowestPackageForFile(ctx, snapshot, loc.URI)
 			if err != nil {
 				// Fallback to minimal source context
-				info.Implementations = append(info.Implementations, sourceContextFromLocation(snapshot, loc))
+				add(pkgPath, sourceContextFromLocation(snapshot, loc))
 				return
 			}
 
 			// Find the identifier at the implementation location
 			implIdent := findIdentifierAtPos(implPgf, loc.Range.Start.Line, loc.Range.Start.Character)
 			if implIdent == nil {
-				info.Implementations = append(info.Implementations, sourceContextFromLocation(snapshot, loc))
+				add(pkgPath, sourceContextFromLocation(snapshot, loc))
 				return
 			}
 
@@ -1202,7 +1457,12 @@ func ResolveSymbol(ctx context.Context, snapshot *cache.Snapshot, locator api.Sy
 			// implementations in dependency packages resolve correctly.
 			if implObj != nil && implNode != nil {
 				srcCtx := buildSourceContext(implPkg.FileSet(), implObj, implNode)
-				info.Implementations = append(info.Implementations, srcCtx)
+				if pkgPath == "" && types.Universe.Lookup(implObj.Name()) != nil {
+					// The declaration in builtin.go stands for the
+					// predeclared type.
+					srcCtx.Signature = formatObjectString(types.Universe.Lookup(implObj.Name()))
+				}
+				add(pkgPath, srcCtx)
 			} else {
 				// Fallback to minimal source context
 				srcCtx := sourceContextFromLocation(snapshot, loc)
@@ -1210,13 +1470,23 @@ func ResolveSymbol(ctx context.Context, snapshot *cache.Snapshot, locator api.Sy
 				if implObj != nil {
 					srcCtx.Signature = formatObjectString(implObj)
 				}
-				info.Implementations = append(info.Implementations, srcCtx)
+				add(pkgPath, srcCtx)
 			}
 		})
 		if err != nil {
 			return info, fmt.Errorf("failed to find implementations: %w", err)
 		}
 
+		// The search runs in parallel; report workspace results first,
+		// then in file order.
+		slices.SortFunc(info.Implementations, func(a, b SourceContext) int {
+			wa, wb := workspace[metadata.PackagePath(a.PackagePath)], workspace[metadata.PackagePath(b.PackagePath)]
+			if wa != wb {
+				return cond(wa, -1, 1)
+			}
+			return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.StartLine, b.StartLine))
+		})
+
 		// Filter implementations if bodies/docs were not requested
 		if !options.IncludeBodies || !options.IncludeDocs {
 			filtered := make([]SourceContext, 0, len(info.Implementations))
@@ -1785,6 +2055,9 @@ func FormatSymbolSummary(sym *api.Symbol) string {
 	if sym.Kind != "" {
 		parts = append(parts, fmt.Sprintf("**Kind**: %s", sym.Kind))
 	}
+	if sym.PackagePath != "" {
+		parts = append(parts, fmt.Sprintf("**Package**: `%s`", sym.PackagePath))
+	}
 	if sym.Receiver != "" {
 		parts = append(parts, fmt.Sprintf("**Receiver**: `%s`", sym.Receiver))
 	}
@@ -1,7 +1,9 @@
 package golang
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"go/ast"
 	"go/parser"
 	"go/token"
@@ -571,8 +573,9 @@ func TestResolveNodeWithLocator(t *testing.T) {
 			name:        "generic method",
 			testdataDir: "generic_types",
 			locator: api.SymbolLocator{
-				SymbolName: "Put",
-				Kind:       "method",
+				SymbolName:  "Put",
+				ParentScope: "Container",
+				Kind:        "method",
 			},
 			wantName:  "Put",
 			wantFound: true,
@@ -606,8 +609,9 @@ func TestResolveNodeWithLocator(t *testing.T) {
 			name:        "multiple type parameters",
 			testdataDir: "multiple_type_parameters",
 			locator: api.SymbolLocator{
-				SymbolName: "Get",
-				Kind:       "method",
+				SymbolName:  "Get",
+				ParentScope: "Mapper",
+				Kind:        "method",
 			},
 			wantName:  "Get",
 			wantFound: true,
@@ -712,8 +716,9 @@ func TestResolveNodeWithLocator(t *testing.T) {
 			name:        "complex generics with constraints",
 			testdataDir: "complex_generics_with_constraints",
 			locator: api.SymbolLocator{
-				SymbolName: "Compare",
-				Kind:       "method",
+				SymbolName:  "Compare",
+				ParentScope: "Comparable",
+				Kind:        "method",
 			},
 			wantName:  "Compare",
 			wantFound: true,
@@ -1018,7 +1023,7 @@ func (c *Client) Start() {
 				Kind:        "method",
 				ContextFile: mainPath,
 			},
-			wantFound: true, // Should find one of them (first match)
+			wantFound: false, // Reported as AMBIGUOUS; see TestResolveNode_ResolutionErrors
 		},
 	}
 
@@ -1065,6 +1070,246 @@ func (c *Client) Start() {
 	}
 }
 
+// TestResolveNode_ResolutionErrors tests the structured errors returned when
+// a locator does not resolve to exactly one symbol.
+func TestResolveNode_ResolutionErrors(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	files := map[string][]byte{
+		"go.mod": []byte("module example.com\ngo 1.21\n"),
+		"main.go": []byte(`package main
+
+type Server struct{}
+
+func (s *Server) Start() {}
+
+type Client struct{}
+
+func (c *Client) Start() {}
+
+func main() {
+	counter := 0
+	_ = counter
+}
+`),
+		"util.go": []byte(`package main
+
+func Helper() {}
+`),
+	}
+
+	fix := setupLLMTest(t, files)
+	defer fix.cleanup()
+
+	resolve := func(t *testing.T, locator api.SymbolLocator) *api.ResolutionError {
+		t.Helper()
+		if locator.ContextFile == "" {
+			locator.ContextFile = fix.mainPath
+		}
+		fh, err := fix.snapshot.ReadFile(fix.ctx, protocol.URIFromPath(locator.ContextFile))
+		if err != nil {
+			t.Fatalf("failed to read file: %v", err)
+		}
+		_, err = ResolveNode(fix.ctx, fix.snapshot, fh, locator)
+		var rerr *api.ResolutionError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("ResolveNode() error = %v, want *api.ResolutionError", err)
+		}
+		return rerr
+	}
+
+	hasSymbol := func(syms []api.Symbol, name, scope, file string) bool {
+		for _, sym := range syms {
+			if sym.Name == name && cmp.Or(sym.Receiver, sym.Parent) == scope && filepath.Base(sym.FilePath) == file {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		rerr := resolve(t, api.SymbolLocator{SymbolName: "Start"})
+		if rerr.Code != api.ErrAmbiguous {
+			t.Fatalf("Code = %s, want %s", rerr.Code, api.ErrAmbiguous)
+		}
+		if len(rerr.Candidates) != 2 ||
+			!hasSymbol(rerr.Candidates, "Start", "*Server", "main.go") ||
+			!hasSymbol(rerr.Candidates, "Start", "*Client", "main.go") {
+			t.Errorf("Candidates = %+v, want Server.Start and Client.Start", rerr.Candidates)
+		}
+	})
+
+	t.Run("line hint disambiguates", func(t *testing.T) {
+		fh, err := fix.snapshot.ReadFile(fix.ctx, protocol.URIFromPath(fix.mainPath))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ResolveNode(fix.ctx, fix.snapshot, fh, api.SymbolLocator{SymbolName: "Start", ContextFile: fix.mainPath, LineHint: 9}); err != nil {
+			t.Errorf("ResolveNode() with line hint failed: %v", err)
+		}
+	})
+
+	t.Run("misspelled name", func(t *testing.T) {
+		rerr := resolve(t, api.SymbolLocator{SymbolName: "Strat"})
+		if rerr.Code != api.ErrNotFound {
+			t.Fatalf("Code = %s, want %s", rerr.Code, api.ErrNotFound)
+		}
+		if !hasSymbol(rerr.Suggestions, "Start", "Server", "main.go") || !hasSymbol(rerr.Suggestions, "Start", "Client", "main.go") {
+			t.Errorf("Suggestions = %+v, want both Start methods", rerr.Suggestions)
+		}
+	})
+
+	t.Run("misspelled local", func(t *testing.T) {
+		rerr := resolve(t, api.SymbolLocator{SymbolName: "countr"})
+		if !hasSymbol(rerr.Suggestions, "counter", "main", "main.go") {
+			t.Errorf("Suggestions = %+v, want local counter in main", rerr.Suggestions)
+		}
+	})
+
+	t.Run("declared in another file", func(t *testing.T) {
+		rerr := resolve(t, api.SymbolLocator{SymbolName: "Helper"})
+		if rerr.Code != api.ErrNotFound {
+			t.Fatalf("Code = %s, want %s", rerr.Code, api.ErrNotFound)
+		}
+		if len(rerr.Suggestions) == 0 || !hasSymbol(rerr.Suggestions[:1], "Helper", "", "util.go") {
+			t.Errorf("Suggestions = %+v, want util.go Helper first", rerr.Suggestions)
+		}
+	})
+
+	t.Run("excluded by filters", func(t *testing.T) {
+		rerr := resolve(t, api.SymbolLocator{SymbolName: "Start", ParentScope: "Worker"})
+		if rerr.Code != api.ErrNotFound {
+			t.Fatalf("Code = %s, want %s", rerr.Code, api.ErrNotFound)
+		}
+		if !strings.Contains(rerr.Message, "parent_scope=Worker") {
+			t.Errorf("Message = %q, want it to name the parent_scope filter", rerr.Message)
+		}
+		if !hasSymbol(rerr.Suggestions, "Start", "*Server", "main.go") || !hasSymbol(rerr.Suggestions, "Start", "*Client", "main.go") {
+			t.Errorf("Suggestions = %+v, want both Start methods", rerr.Suggestions)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		missing := fix.sandbox.Workdir.AbsPath("missing.go")
+		rerr := resolve(t, api.SymbolLocator{SymbolName: "Start", ContextFile: missing})
+		if rerr.Code != api.ErrFileNotInWorkspace {
+			t.Errorf("Code = %s, want %s", rerr.Code, api.ErrFileNotInWorkspace)
+		}
+	})
+}
+
+// TestResolveQualifiedName tests resolution of qualified names to context-file locators.
+func TestResolveQualifiedName(t *testing.T) {
+	testenv.NeedsGoPackages(t)
+
+	files := map[string][]byte{
+		"go.mod": []byte("module example.com\ngo 1.21\n"),
+		"main.go": []byte(`package main
+
+import "example.com/store"
+
+func main() { _ = store.Open() }
+`),
+		"store/store.go": []byte(`package store
+
+type base struct{}
+
+func (base) Close() error { return nil }
+
+type DB struct {
+	base
+	Path string
+}
+
+func Open() *DB { return &DB{} }
+
+func (db *DB) Query(q string) error { return nil }
+`),
+	}
+
+	fix := setupLLMTest(t, files)
+	defer fix.cleanup()
+	storePath := fix.sandbox.Workdir.AbsPath("store/store.go")
+
+	tests := []struct {
+		name     string
+		input    string
+		want     api.SymbolLocator
+		wantCode api.ResolutionErrorCode
+	}{
+		{
+			name:  "function",
+			input: "example.com/store.Open",
+			want:  api.SymbolLocator{SymbolName: "Open", ContextFile: storePath, LineHint: 12},
+		},
+		{
+			name:  "pointer method",
+			input: "example.com/store.(*DB).Query",
+			want:  api.SymbolLocator{SymbolName: "Query", ContextFile: storePath, ParentScope: "*DB", LineHint: 14},
+		},
+		{
+			name:  "field",
+			input: "example.com/store.DB.Path",
+			want:  api.SymbolLocator{SymbolName: "Path", ContextFile: storePath, ParentScope: "DB", LineHint: 9},
+		},
+		{
+			name:  "promoted method",
+			input: "example.com/store.(*DB).Close",
+			want:  api.SymbolLocator{SymbolName: "Close", ContextFile: storePath, ParentScope: "*base", LineHint: 5},
+		},
+		{
+			name:     "misspelled method",
+			input:    "example.com/store.(*DB).Qeury",
+			wantCode: api.ErrNotFound,
+		},
+		{
+			name:     "unknown package",
+			input:    "example.com/nosuch.Open",
+			wantCode: api.ErrPackageNotLoaded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveQualifiedName(fix.ctx, fix.snapshot, api.SymbolLocator{QualifiedName: tt.input})
+			if tt.wantCode != "" {
+				var rerr *api.ResolutionError
+				if !errors.As(err, &rerr) || rerr.Code != tt.wantCode {
+					t.Fatalf("ResolveQualifiedName(%q) error = %v, want %s", tt.input, err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveQualifiedName(%q) failed: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveQualifiedName(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+
+			// The result must resolve like any context-file locator.
+			fh, err := fix.snapshot.ReadFile(fix.ctx, protocol.URIFromPath(got.ContextFile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			node, err := ResolveNode(fix.ctx, fix.snapshot, fh, got)
+			if err != nil {
+				t.Fatalf("ResolveNode(%+v) failed: %v", got, err)
+			}
+			if !node.IsDefinition || node.Object.Name() != tt.want.SymbolName {
+				t.Errorf("ResolveNode(%+v) = %v (definition: %v), want the declaration", got, node.Object, node.IsDefinition)
+			}
+		})
+	}
+
+	t.Run("misspelled method suggestion", func(t *testing.T) {
+		_, err := ResolveQualifiedName(fix.ctx, fix.snapshot, api.SymbolLocator{QualifiedName: "example.com/store.(*DB).Qeury"})
+		var rerr *api.ResolutionError
+		if !errors.As(err, &rerr) || len(rerr.Suggestions) == 0 || rerr.Suggestions[0].Name != "Query" {
+			t.Errorf("ResolveQualifiedName() error = %+v, want a Query suggestion", err)
+		}
+	})
+}
+
 // ===== Regression Tests for Bug Fixes =====
 
 // TestParentScopeNormalization tests the parent scope matching fix
@@ -3,6 +3,8 @@ package golang
 import (
 	"go/ast"
 	"go/token"
+	"go/types"
+	"slices"
 	"testing"
 
 	"golang.org/x/tools/gopls/mcpbridge/api"
@@ -287,3 +289,174 @@ func TestScopeFrame(t *testing.T) {
 		t.Errorf("node name = %q, want %q", ident.Name, "test")
 	}
 }
+
+// snippetTestObjects returns methods Server.Start(ctx context.Context) error
+// and Client.Start() declared in package example.com/app.
+func snippetTestObjects() (serverStart, clientStart *types.Func) {
+	pkg := types.NewPackage("example.com/app", "app")
+	ctxPkg := types.NewPackage("context", "context")
+	ctxType := types.NewNamed(types.NewTypeName(token.NoPos, ctxPkg, "Context", nil), types.NewInterfaceType(nil, nil), nil)
+
+	method := func(recvName string, params *types.Tuple, results *types.Tuple) *types.Func {
+		named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, recvName, nil), types.NewStruct(nil, nil), nil)
+		recv := types.NewVar(token.NoPos, pkg, "", types.NewPointer(named))
+		sig := types.NewSignatureType(recv, nil, nil, params, results, false)
+		return types.NewFunc(token.NoPos, pkg, "Start", sig)
+	}
+	serverStart = method("Server",
+		types.NewTuple(types.NewVar(token.NoPos, pkg, "ctx", ctxType)),
+		types.NewTuple(types.NewVar(token.NoPos, pkg, "", types.Universe.Lookup("error").Type())))
+	clientStart = method("Client", nil, nil)
+	return serverStart, clientStart
+}
+
+// TestCandidateSignature tests the signature rendering used for snippet comparison
+func TestCandidateSignature(t *testing.T) {
+	serverStart, _ := snippetTestObjects()
+	if got, want := candidateSignature(serverStart), "func (*Server).Start(ctx context.Context) error"; got != want {
+		t.Errorf("candidateSignature(method) = %q, want %q", got, want)
+	}
+
+	pkg := types.NewPackage("example.com/app", "app")
+	fields := []*types.Var{types.NewField(token.NoPos, pkg, "addr", types.Typ[types.String], false)}
+	tn := types.NewTypeName(token.NoPos, pkg, "Config", nil)
+	types.NewNamed(tn, types.NewStruct(fields, nil), nil)
+	if got, want := candidateSignature(tn), "type Config struct"; got != want {
+		t.Errorf("candidateSignature(struct) = %q, want %q", got, want)
+	}
+
+	if got := candidateSignature(nil); got != "" {
+		t.Errorf("candidateSignature(nil) = %q, want empty", got)
+	}
+}
+
+// TestSignatureSimilarity tests the fuzzy token similarity between snippets and signatures
+func TestSignatureSimilarity(t *testing.T) {
+	const serverStart = "func (*Server).Start(ctx context.Context) error"
+
+	tests := []struct {
+		name      string
+		snippet   string
+		signature string
+		wantMatch bool // similarity >= signatureMismatchThreshold
+	}{
+		{"identical source form", "func (s *Server) Start(ctx context.Context) error", serverStart, true},
+		{"results omitted", "func (s *Server) Start(ctx context.Context)", serverStart, true},
+		{"name only", "Start", serverStart, true},
+		{"different receiver", "func (c *Client) Start()", serverStart, false},
+		{"different parameters", "func (s *Server) Start(addr string, port int)", serverStart, false},
+		{"unrelated", "func Parse(input []byte) (*AST, error)", serverStart, false},
+		{"short struct snippet", "type Config struct", "type Config struct", true},
+		{"keywords only snippet matches anything", "func ()", serverStart, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := signatureSimilarity(tt.snippet, tt.signature)
+			if sim < 0 || sim > 1 {
+				t.Fatalf("signatureSimilarity() = %v, want value in [0, 1]", sim)
+			}
+			if got := sim >= signatureMismatchThreshold; got != tt.wantMatch {
+				t.Errorf("signatureSimilarity(%q, %q) = %.2f, want match = %v", tt.snippet, tt.signature, sim, tt.wantMatch)
+			}
+		})
+	}
+}
+
+// TestSelectBestCandidateBySnippet tests that SignatureSnippet re-ranks candidates
+func TestSelectBestCandidateBySnippet(t *testing.T) {
+	serverStart, clientStart := snippetTestObjects()
+	server := &ResolveNodeResult{Pos: token.Pos(100), Object: serverStart, IsDefinition: true}
+	client := &ResolveNodeResult{Pos: token.Pos(200), Object: clientStart, IsDefinition: true}
+
+	locator := api.SymbolLocator{SymbolName: "Start", SignatureSnippet: "func (c *Client) Start()"}
+
+	if got := selectBestCandidate(server, client, nil, locator, true); got != client {
+		t.Errorf("selectBestCandidate() kept Server.Start, want Client.Start matching the snippet")
+	}
+	if got := selectBestCandidate(client, server, nil, locator, true); got != client {
+		t.Errorf("selectBestCandidate() switched to Server.Start, want Client.Start matching the snippet")
+	}
+}
+
+// TestVerifySignatureSnippet tests the structured mismatch warning
+func TestVerifySignatureSnippet(t *testing.T) {
+	serverStart, _ := snippetTestObjects()
+
+	if w := verifySignatureSnippet(api.SymbolLocator{}, serverStart); w != nil {
+		t.Errorf("verifySignatureSnippet() without snippet = %+v, want nil", w)
+	}
+	if w := verifySignatureSnippet(api.SymbolLocator{SignatureSnippet: "func (s *Server) Start(ctx context.Context)"}, serverStart); w != nil {
+		t.Errorf("verifySignatureSnippet() for matching snippet = %+v, want nil", w)
+	}
+
+	w := verifySignatureSnippet(api.SymbolLocator{SignatureSnippet: "func (c *Client) Start()"}, serverStart)
+	if w == nil {
+		t.Fatal("verifySignatureSnippet() for mismatching snippet = nil, want warning")
+	}
+	if w.Code != api.WarningSignatureMismatch {
+		t.Errorf("warning code = %q, want %q", w.Code, api.WarningSignatureMismatch)
+	}
+	if w.ActualSignature != "func (*Server).Start(ctx context.Context) error" {
+		t.Errorf("warning actual signature = %q", w.ActualSignature)
+	}
+	if w.ExpectedSnippet != "func (c *Client) Start()" {
+		t.Errorf("warning expected snippet = %q", w.ExpectedSnippet)
+	}
+}
+
+// TestParseQualifiedNames tests parsing of qualified names and stack trace frames
+func TestParseQualifiedNames(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []qualifiedName // longest package path first
+	}{
+		{
+			input: "net/http.ListenAndServe",
+			want:  []qualifiedName{{pkgPath: "net/http", name: "ListenAndServe"}},
+		},
+		{
+			input: "github.com/org/svc/internal/store.(*DB).Query",
+			want:  []qualifiedName{{pkgPath: "github.com/org/svc/internal/store", recv: "DB", name: "Query"}},
+		},
+		{
+			input: "example.com/app.Config.Timeout",
+			want: []qualifiedName{
+				{pkgPath: "example.com/app.Config", name: "Timeout"},
+				{pkgPath: "example.com/app", recv: "Config", name: "Timeout"},
+			},
+		},
+		{
+			input: "gopkg.in/yaml.v3.Unmarshal",
+			want: []qualifiedName{
+				{pkgPath: "gopkg.in/yaml.v3", name: "Unmarshal"},
+				{pkgPath: "gopkg.in/yaml", recv: "v3", name: "Unmarshal"},
+			},
+		},
+		{
+			input: "example.com/app/store.(*DB).Query(0xc000010000, {0x1, 0x2})",
+			want:  []qualifiedName{{pkgPath: "example.com/app/store", recv: "DB", name: "Query"}},
+		},
+		{
+			input: "example.com/app/worker.Run.func1.2",
+			want:  []qualifiedName{{pkgPath: "example.com/app/worker", name: "Run"}},
+		},
+		{
+			input: "example.com/app/cache.(*LRU[...]).Get",
+			want:  []qualifiedName{{pkgPath: "example.com/app/cache", recv: "LRU", name: "Get"}},
+		},
+		{
+			input: "fmt",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseQualifiedNames(tt.input)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseQualifiedNames(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
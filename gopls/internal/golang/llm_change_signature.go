@@ -0,0 +1,86 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// This file exposes changeSignature to the bridge, which, unlike the
+// editor command, can add parameters and keeps going past the calls it
+// cannot rewrite.
+
+// LLMChangeSignature returns the changes that give the function whose
+// name is at rng of fh (at its declaration or at a use) the parameters
+// params, and rewrite all its calls. It also returns the function's name
+// and the calls it left unchanged, with the reason for each.
+func LLMChangeSignature(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, params []api.SignatureParam) ([]protocol.DocumentChange, string, []api.SkippedCall, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, "", nil, err
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	cur, ok := pgf.Cursor().FindByPos(start, end)
+	if !ok {
+		return nil, "", nil, fmt.Errorf("no function at %s:%d", fh.URI().Path(), rng.Start.Line+1)
+	}
+	var fn *types.Func
+	if id, ok := cur.Node().(*ast.Ident); ok {
+		fn, _ = pkg.TypesInfo().ObjectOf(id).(*types.Func)
+	}
+	if fn == nil {
+		text, _ := pgf.NodeText(cur.Node())
+		return nil, "", nil, fmt.Errorf("%s is not a function or method", text)
+	}
+
+	// Change the signature at the declaration.
+	declPkg, declPGF, declPos, err := NarrowestDeclaringPackage(ctx, snapshot, pkg, fn)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	declRng, err := declPGF.PosRange(declPos, declPos)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	sigParams := make([]signatureParam, len(params))
+	for i, p := range params {
+		switch {
+		case p.OldIndex != nil && p.Field != "":
+			return nil, "", nil, fmt.Errorf("parameter #%d has both old_index and field", i)
+		case p.OldIndex != nil:
+			sigParams[i] = signatureParam{oldIndex: *p.OldIndex}
+		case p.Field != "":
+			sigParams[i] = signatureParam{field: p.Field, def: p.Default}
+		default:
+			return nil, "", nil, fmt.Errorf("parameter #%d needs either old_index or field", i)
+		}
+	}
+	var skipped []api.SkippedCall
+	hooks := &inlineAllHooks{
+		skipped: func(loc protocol.Location, err error) {
+			skipped = append(skipped, api.SkippedCall{
+				File:   loc.URI.Path(),
+				Line:   int(loc.Range.Start.Line) + 1,
+				Reason: err.Error(),
+			})
+		},
+	}
+	changes, err := changeSignature(ctx, snapshot, declPkg, declPGF, declRng, sigParams, hooks)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return changes, fn.Name(), skipped, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// This file runs the extract refactorings for the bridge directly, rather
+// than through code actions, so that a selection that cannot be extracted
+// gets an error that says why instead of an empty list of actions.
+
+// ExtractKinds are the kinds of extraction LLMExtract performs.
+var ExtractKinds = []string{"function", "method", "variable", "constant", "to-new-file"}
+
+// LLMExtract returns the changes that extract the selection rng of fh to a
+// new function, method, variable, constant, or file, according to kind.
+// For a function or method, it also returns the free variables of the
+// selection, which the extracted function takes as parameters.
+//
+// A constant expression extracted as a variable becomes a constant, as
+// with the refactor.extract.variable code action.
+func LLMExtract(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, kind string) ([]protocol.DocumentChange, []api.FreeVariable, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	info := pkg.TypesInfo()
+
+	var fix string
+	switch kind {
+	case "function", "method":
+		_, ok, methodOK, err := canExtractFunction(pgf.Cursor(), start, end)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot extract a %s: %w", kind, err)
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("cannot extract a %s: the selection is not a sequence of whole statements in a function body", kind)
+		}
+		fix = fixExtractFunction
+		if kind == "method" {
+			if !methodOK {
+				return nil, nil, fmt.Errorf("cannot extract a method: the selection is not in a method")
+			}
+			fix = fixExtractMethod
+		}
+
+	case "variable", "constant":
+		exprs, err := canExtractVariable(info, pgf.Cursor(), start, end, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot extract a %s: %w", kind, err)
+		}
+		if kind == "constant" && info.Types[exprs[0].Node().(ast.Expr)].Value == nil {
+			return nil, nil, fmt.Errorf("cannot extract a constant: the expression is not constant; extract a variable instead")
+		}
+		fix = fixExtractVariable
+
+	case "to-new-file":
+		if !canExtractToNewFile(pgf, start, end) {
+			return nil, nil, fmt.Errorf("cannot extract to a new file: the selection contains no whole top-level declaration")
+		}
+		changes, err := ExtractToNewFile(ctx, snapshot, fh, rng)
+		return changes, nil, err
+
+	default:
+		return nil, nil, fmt.Errorf("unknown extraction kind %q: want one of %s", kind, strings.Join(ExtractKinds, ", "))
+	}
+
+	changes, err := ApplyFix(ctx, fix, snapshot, fh, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fix == fixExtractVariable {
+		return changes, nil, nil
+	}
+
+	// The free local variables of the selection become parameters, except
+	// for the receiver of an extracted method.
+	var recv types.Object
+	if kind == "method" {
+		for _, decl := range pgf.File.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Pos() <= start && end <= fn.End() {
+				if fn.Recv != nil && len(fn.Recv.List) > 0 && len(fn.Recv.List[0].Names) > 0 {
+					recv = info.Defs[fn.Recv.List[0].Names[0]]
+				}
+			}
+		}
+	}
+	qual := typesinternal.FileQualifier(pgf.File, pkg.Types())
+	var params []api.FreeVariable
+	var seen []types.Object
+	for _, ref := range freeRefs(pkg.Types(), info, pgf.File, start, end) {
+		obj := ref.objects[0]
+		if _, ok := obj.(*types.Var); !ok || ref.scope != "local" || obj == recv || slices.Contains(seen, obj) {
+			continue
+		}
+		seen = append(seen, obj)
+		params = append(params, api.FreeVariable{Name: obj.Name(), Type: types.TypeString(obj.Type(), qual)})
+	}
+	return changes, params, nil
+}
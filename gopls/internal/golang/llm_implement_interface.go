@@ -0,0 +1,479 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	pathpkg "path"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/packagepath"
+	"golang.org/x/tools/internal/typesinternal"
+)
+
+// This file makes a type implement an interface for the bridge: either
+// by adding stubs for the missing methods to a concrete type, as the
+// refactor.rewrite.implementInterface code action does, or by generating
+// a fake of the interface that records its calls.
+
+// LLMImplementInterface returns the changes that add stubs of the methods
+// of the interface named at ifaceRng of ifaceFH that the concrete type
+// named at rng of fh lacks.
+func LLMImplementInterface(ctx context.Context, snapshot *cache.Snapshot, ifaceFH file.Handle, ifaceRng protocol.Range, fh file.Handle, rng protocol.Range) ([]protocol.DocumentChange, *api.OImplementInterfaceResult, error) {
+	iface, err := interfaceAt(ctx, snapshot, ifaceFH, ifaceRng)
+	if err != nil {
+		return nil, nil, err
+	}
+	ifaceStr := "error"
+	if iface.Pkg() != nil {
+		ifaceStr = iface.Pkg().Path() + "." + iface.Name()
+	}
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := identObject(pkg, pgf, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	tname, ok := obj.(*types.TypeName)
+	if !ok || types.IsInterface(tname.Type()) {
+		return nil, nil, fmt.Errorf("%s is not a concrete type", obj.Name())
+	}
+
+	// ImplementInterface wants a location in the declaration of the type.
+	_, declPGF, declPos, err := NarrowestDeclaringPackage(ctx, snapshot, pkg, tname)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := declPGF.PosLocation(declPos, declPos)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, err := ImplementInterface(ctx, snapshot, loc, ifaceStr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// As in stubmethods, a method is missing if the pointer type lacks it.
+	result := &api.OImplementInterfaceResult{
+		Interface: interfaceName(iface),
+		Type:      tname.Name(),
+	}
+	ptr := types.NewPointer(tname.Type())
+	for m := range iface.Type().Underlying().(*types.Interface).Methods() {
+		if obj, _, _ := types.LookupFieldOrMethod(ptr, false, m.Pkg(), m.Name()); obj == nil {
+			result.Methods = append(result.Methods, m.Name())
+		}
+	}
+	return changes, result, nil
+}
+
+// LLMGenerateFake returns the changes that declare, in the Go file at
+// path, a fake of the interface named at ifaceRng of ifaceFH: a struct
+// type, named name or else "Fake" and the name of the interface, that
+// records the calls of each method and calls the function in the
+// corresponding Func field, if any. The file is created if it does not
+// exist.
+func LLMGenerateFake(ctx context.Context, snapshot *cache.Snapshot, ifaceFH file.Handle, ifaceRng protocol.Range, path, name string) ([]protocol.DocumentChange, *api.OImplementInterfaceResult, error) {
+	iface, err := interfaceAt(ctx, snapshot, ifaceFH, ifaceRng)
+	if err != nil {
+		return nil, nil, err
+	}
+	if name == "" {
+		name = "Fake" + iface.Name()
+	}
+	if !token.IsIdentifier(name) {
+		return nil, nil, fmt.Errorf("invalid fake name %q", name)
+	}
+	if !strings.HasSuffix(path, ".go") {
+		return nil, nil, fmt.Errorf("%s is not a Go file", path)
+	}
+	methods := iface.Type().Underlying().(*types.Interface)
+	if methods.NumMethods() == 0 {
+		return nil, nil, fmt.Errorf("%s has no methods to fake", interfaceName(iface))
+	}
+
+	// Find the package of the file, or of the directory of a new file.
+	uri := protocol.URIFromPath(path)
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		src    []byte // content of the existing file, if any
+		mp     *metadata.Package
+		target *types.Package
+	)
+	if src, err = fh.Content(); err == nil {
+		pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, uri)
+		if err != nil {
+			return nil, nil, err
+		}
+		if pgf.Fixed() {
+			return nil, nil, fmt.Errorf("file contains parse errors: %s", path)
+		}
+		mp, target = pkg.Metadata(), pkg.Types()
+	} else if errors.Is(err, os.ErrNotExist) {
+		mp, err = packageInDir(ctx, snapshot, uri.DirPath())
+		if err != nil {
+			return nil, nil, err
+		}
+		if mp == nil {
+			return nil, nil, fmt.Errorf("no package in %s for the new file %s", uri.DirPath(), uri.Base())
+		}
+		pkgs, err := snapshot.TypeCheck(ctx, mp.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		target = pkgs[0].Types()
+	} else {
+		return nil, nil, err
+	}
+
+	// Reject fakes that would not compile, as ImplementInterface does.
+	if !iface.Exported() && iface.Pkg() != nil && iface.Pkg().Path() != target.Path() {
+		return nil, nil, fmt.Errorf("cannot fake %s outside package %s: the interface is unexported", interfaceName(iface), iface.Pkg().Name())
+	}
+	var fields []string
+	for m := range methods.Methods() {
+		fields = append(fields, m.Name()+"Func", m.Name()+"Calls")
+	}
+	extraPackages := make(map[string]bool) // paths of packages the fake refers to
+	if iface.Pkg() != nil {
+		extraPackages[iface.Pkg().Path()] = true
+	}
+	for m := range methods.Methods() {
+		switch {
+		case !m.Exported() && m.Pkg().Path() != target.Path():
+			return nil, nil, fmt.Errorf("cannot fake %s outside package %s: method %s is unexported", interfaceName(iface), m.Pkg().Name(), m.Name())
+		case m.Name() == "mu" || slices.Contains(fields, m.Name()):
+			return nil, nil, fmt.Errorf("method %s conflicts with a field of the fake", m.Name())
+		}
+		if obj := target.Scope().Lookup(name + m.Name() + "Call"); obj != nil {
+			return nil, nil, fmt.Errorf("%s is already declared in package %s", obj.Name(), target.Name())
+		}
+		_ = types.TypeString(m.Type(), func(p *types.Package) string {
+			extraPackages[p.Path()] = true
+			return ""
+		})
+	}
+	if obj := target.Scope().Lookup(name); obj != nil {
+		return nil, nil, fmt.Errorf("%s is already declared in package %s", name, target.Name())
+	}
+	dependingOnTarget := snapshot.MetadataGraph().ReverseReflexiveTransitiveClosure(mp.ID)
+	for path := range extraPackages {
+		if path == target.Path() {
+			continue
+		}
+		for _, dep := range snapshot.MetadataGraph().ForPackagePath[metadata.PackagePath(path)] {
+			if _, ok := dependingOnTarget[dep.ID]; ok {
+				return nil, nil, fmt.Errorf("the fake would import %s, which imports package %s: import cycle", path, target.Name())
+			}
+		}
+		if !packagepath.CanImport(target.Path(), path) {
+			return nil, nil, fmt.Errorf("the fake would import %s, which package %s cannot import", path, target.Name())
+		}
+	}
+
+	// Qualify references as insertDeclsAfter does, recording new imports.
+	importEnv := make(map[ImportPath]string) // value is local name
+	if src != nil {
+		f, err := parser.ParseFile(token.NewFileSet(), path, src, parser.ImportsOnly)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, imp := range f.Imports {
+			importPath := metadata.UnquoteImportPath(imp)
+			switch {
+			case imp.Name == nil:
+				if dep := snapshot.Metadata(mp.DepsByImpPath[importPath]); dep != nil {
+					importEnv[importPath] = string(dep.Name)
+				}
+			case imp.Name.Name == ".":
+				importEnv[importPath] = "" // see types.Qualifier
+			case imp.Name.Name != "_":
+				importEnv[importPath] = imp.Name.Name
+			}
+		}
+	}
+	type newImport struct{ name, importPath string }
+	var newImports []newImport
+	qual := func(pkg *types.Package) string {
+		if pkg.Path() == target.Path() {
+			return ""
+		}
+		importPath := ImportPath(pkg.Path())
+		name, ok := importEnv[importPath]
+		if !ok {
+			name = pkg.Name()
+			importEnv[importPath] = name
+			new := newImport{importPath: string(importPath)}
+			if name != pathpkg.Base(trimVersionSuffix(new.importPath)) {
+				new.name = name
+			}
+			newImports = append(newImports, new)
+		}
+		return name
+	}
+
+	var buf bytes.Buffer
+	if src != nil {
+		buf.Write(src)
+	} else {
+		fmt.Fprintf(&buf, "package %s\n", target.Name())
+	}
+	buf.WriteByte('\n')
+	result := &api.OImplementInterfaceResult{Interface: interfaceName(iface), Type: name}
+	for m := range methods.Methods() {
+		result.Methods = append(result.Methods, m.Name())
+	}
+	emitFake(&buf, iface, name, qual)
+
+	// Re-parse, add the imports and pretty-print, as insertDeclsAfter does.
+	fset := token.NewFileSet()
+	newF, err := parser.ParseFile(fset, path, buf.Bytes(), parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not parse the fake: %v", err)
+	}
+	for _, imp := range newImports {
+		astutil.AddNamedImport(fset, newF, imp.name, imp.importPath)
+	}
+	var output bytes.Buffer
+	if err := format.Node(&output, fset, newF); err != nil {
+		return nil, nil, fmt.Errorf("format.Node: %w", err)
+	}
+
+	if src == nil {
+		return []protocol.DocumentChange{
+			protocol.DocumentChangeCreate(uri),
+			protocol.DocumentChangeEdit(fh, []protocol.TextEdit{
+				{Range: protocol.Range{}, NewText: output.String()},
+			}),
+		}, result, nil
+	}
+	edits, err := protocol.EditsFromDiffEdits(protocol.NewMapper(uri, src), diff.Bytes(src, output.Bytes()))
+	if err != nil {
+		return nil, nil, err
+	}
+	return []protocol.DocumentChange{protocol.DocumentChangeEdit(fh, edits)}, result, nil
+}
+
+// emitFake writes to out the declarations of the fake name of iface.
+func emitFake(out *bytes.Buffer, iface *types.TypeName, name string, qual types.Qualifier) {
+	methods := iface.Type().Underlying().(*types.Interface)
+	ifaceRef := types.TypeString(iface.Type(), qual)
+
+	// fakeParam is a parameter of a faked method, and the field of the
+	// call record that holds its argument.
+	type fakeParam struct {
+		name, field string
+		typ         types.Type
+	}
+	params := make(map[*types.Func][]fakeParam)
+	for m := range methods.Methods() {
+		// Rename parameters that are unnamed or shadow the receiver, the
+		// local fn, or a package that the signature refers to.
+		taken := map[string]bool{"f": true, "fn": true}
+		types.TypeString(m.Type(), func(p *types.Package) string {
+			taken[qual(p)] = true
+			return ""
+		})
+		fieldTaken := make(map[string]bool)
+		for i := range m.Signature().Params().Len() {
+			v := m.Signature().Params().At(i)
+			name := v.Name()
+			if name == "" || name == "_" || taken[name] {
+				name = fmt.Sprintf("arg%d", i)
+			}
+			taken[name] = true
+			field := strings.ToUpper(name[:1]) + name[1:]
+			if fieldTaken[field] {
+				field = fmt.Sprintf("Arg%d", i)
+			}
+			fieldTaken[field] = true
+			params[m] = append(params[m], fakeParam{name, field, v.Type()})
+		}
+	}
+
+	// signature formats the parameters and results of m, with the names
+	// of params but without result names.
+	signature := func(m *types.Func) string {
+		var b strings.Builder
+		b.WriteString("(")
+		for i, p := range params[m] {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if m.Signature().Variadic() && i == len(params[m])-1 {
+				fmt.Fprintf(&b, "%s ...%s", p.name, types.TypeString(p.typ.(*types.Slice).Elem(), qual))
+			} else {
+				fmt.Fprintf(&b, "%s %s", p.name, types.TypeString(p.typ, qual))
+			}
+		}
+		b.WriteString(")")
+		results := m.Signature().Results()
+		switch results.Len() {
+		case 0:
+		case 1:
+			fmt.Fprintf(&b, " %s", types.TypeString(results.At(0).Type(), qual))
+		default:
+			b.WriteString(" (")
+			for i := range results.Len() {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(types.TypeString(results.At(i).Type(), qual))
+			}
+			b.WriteString(")")
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(out, `// %s is a fake [%s] that records its calls. Each method
+// calls the function in the corresponding Func field if it is set, and
+// otherwise returns zero values.
+type %s struct {
+	mu %s.Mutex
+
+`, name, ifaceRef, name, qual(types.NewPackage("sync", "sync")))
+	for m := range methods.Methods() {
+		fmt.Fprintf(out, "\t%sFunc func%s\n", m.Name(), signature(m))
+		fmt.Fprintf(out, "\t%sCalls []%s%sCall\n", m.Name(), name, m.Name())
+	}
+	out.WriteString("}\n\n")
+	fmt.Fprintf(out, "var _ %s = (*%s)(nil)\n", ifaceRef, name)
+
+	for m := range methods.Methods() {
+		record := name + m.Name() + "Call"
+		fmt.Fprintf(out, "\n// %s records the arguments of a call of [%s.%s].\n", record, name, m.Name())
+		if len(params[m]) == 0 {
+			fmt.Fprintf(out, "type %s struct{}\n\n", record)
+		} else {
+			fmt.Fprintf(out, "type %s struct {\n", record)
+			for _, p := range params[m] {
+				fmt.Fprintf(out, "\t%s %s\n", p.field, types.TypeString(p.typ, qual))
+			}
+			out.WriteString("}\n\n")
+		}
+		var args, fields []string
+		for i, p := range params[m] {
+			fields = append(fields, p.field+": "+p.name)
+			if m.Signature().Variadic() && i == len(params[m])-1 {
+				args = append(args, p.name+"...")
+			} else {
+				args = append(args, p.name)
+			}
+		}
+
+		fmt.Fprintf(out, "// %s implements [%s].\n", m.Name(), ifaceRef)
+		fmt.Fprintf(out, "func (f *%s) %s%s {\n", name, m.Name(), signature(m))
+		fmt.Fprintf(out, "\tf.mu.Lock()\n")
+		fmt.Fprintf(out, "\tf.%sCalls = append(f.%sCalls, %s{%s})\n", m.Name(), m.Name(), record, strings.Join(fields, ", "))
+		fmt.Fprintf(out, "\tfn := f.%sFunc\n", m.Name())
+		fmt.Fprintf(out, "\tf.mu.Unlock()\n")
+		call := fmt.Sprintf("fn(%s)", strings.Join(args, ", "))
+		results := m.Signature().Results()
+		if results.Len() == 0 {
+			fmt.Fprintf(out, "\tif fn != nil {\n\t\t%s\n\t}\n}\n", call)
+			continue
+		}
+		var zeros []string
+		for v := range results.Variables() {
+			zero, ok := typesinternal.ZeroString(v.Type(), qual)
+			if !ok {
+				zero = "*new(" + types.TypeString(v.Type(), qual) + ")"
+			}
+			zeros = append(zeros, zero)
+		}
+		fmt.Fprintf(out, "\tif fn != nil {\n\t\treturn %s\n\t}\n\treturn %s\n}\n", call, strings.Join(zeros, ", "))
+	}
+}
+
+// interfaceAt returns the interface type whose name is at rng of fh.
+func interfaceAt(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range) (*types.TypeName, error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	obj, err := identObject(pkg, pgf, rng)
+	if err != nil {
+		return nil, err
+	}
+	tname, ok := obj.(*types.TypeName)
+	if !ok || !types.IsInterface(tname.Type()) || is[*types.TypeParam](tname.Type()) {
+		return nil, fmt.Errorf("%s is not an interface type", obj.Name())
+	}
+	if named, ok := types.Unalias(tname.Type()).(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("cannot implement generic interface %s", tname.Name())
+	}
+	if tname.Pkg() == nil && tname.Name() != "error" {
+		return nil, fmt.Errorf("%s has no methods to implement", tname.Name())
+	}
+	return tname, nil
+}
+
+// identObject returns the object named by the identifier at rng of pgf.
+func identObject(pkg *cache.Package, pgf *parsego.File, rng protocol.Range) (types.Object, error) {
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, err
+	}
+	cur, ok := pgf.Cursor().FindByPos(start, end)
+	if ok {
+		if id, ok := cur.Node().(*ast.Ident); ok {
+			if obj := pkg.TypesInfo().ObjectOf(id); obj != nil {
+				return obj, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no symbol at %s:%d", pgf.URI.Path(), rng.Start.Line+1)
+}
+
+// interfaceName returns the name of iface qualified by its package name.
+func interfaceName(iface *types.TypeName) string {
+	if iface.Pkg() == nil {
+		return iface.Name()
+	}
+	return iface.Pkg().Name() + "." + iface.Name()
+}
+
+// packageInDir returns the package, other than a test package, whose
+// files are in dir, or nil if there is none. It waits for the workspace
+// to be loaded, as nothing else may have loaded it yet.
+func packageInDir(ctx context.Context, snapshot *cache.Snapshot, dir string) (*metadata.Package, error) {
+	graph, err := snapshot.LoadMetadataGraph(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	for _, mp := range graph.Packages {
+		if mp.ForTest != "" || strings.HasSuffix(string(mp.Name), "_test") || len(mp.CompiledGoFiles) == 0 {
+			continue
+		}
+		if mp.CompiledGoFiles[0].DirPath() == dir {
+			return mp, nil
+		}
+	}
+	return nil, nil
+}
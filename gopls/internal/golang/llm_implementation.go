@@ -0,0 +1,58 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+)
+
+// This file filters the results of implementation queries for LLMs. The
+// methodsets search covers the workspace and all its dependencies, which
+// for an interface like io.Reader means dozens of standard library types
+// that the workspace can neither name nor change.
+
+// trivialInterfaces are the interfaces that ExcludeTrivial leaves out,
+// by package path and name: almost every type implements them.
+var trivialInterfaces = map[string]bool{
+	"error":          true,
+	"fmt.Stringer":   true,
+	"fmt.GoStringer": true,
+}
+
+// isInterfaceQuery reports whether an implementation query for obj, a type
+// or method, looks for subtypes: the types that implement an interface.
+func isInterfaceQuery(obj types.Object) bool {
+	queryType, _ := typeOrMethod(obj)
+	return queryType != nil && types.IsInterface(queryType)
+}
+
+// importableFrom reports whether the type name declared in the package
+// pkgPath outside the workspace can be referred to from the workspace:
+// it must be exported, and not in an internal package. (A dependency's
+// internal packages are never importable from another module.)
+func importableFrom(pkgPath metadata.PackagePath, name string) bool {
+	if !token.IsExported(name) {
+		return false
+	}
+	for elem := range strings.SplitSeq(string(pkgPath), "/") {
+		if elem == "internal" {
+			return false
+		}
+	}
+	return true
+}
+
+// isTrivialInterface reports whether the interface pkgPath.name is one of
+// trivialInterfaces. pkgPath is empty for predeclared types.
+func isTrivialInterface(pkgPath metadata.PackagePath, name string) bool {
+	if pkgPath == "" {
+		return trivialInterfaces[name]
+	}
+	return trivialInterfaces[string(pkgPath)+"."+name]
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+	"golang.org/x/tools/internal/diff"
+	"golang.org/x/tools/internal/refactor/inline"
+)
+
+// This file runs the inliner for the bridge, on a single call as the
+// refactor.inline.call code action does, or on every call of a function
+// as change signature does, and reports what the inliner had to do to
+// keep the behavior of the calls.
+
+// LLMInline returns the changes that inline the call at rng of fh or, if
+// rng is the name of a function declaration, every call of the function.
+// The result has the callee and the caveats of the inlining; the caller
+// sets its diff.
+func LLMInline(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range) (_ []protocol.DocumentChange, _ *api.OInlineResult, err error) {
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end, err := pgf.RangePos(rng)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &api.OInlineResult{}
+	verbose := logger(ctx, "inliner", snapshot.Options().VerboseOutput)
+	logf := func(format string, args ...any) {
+		verbose(format, args...)
+		// The inliner logs each parameter it cannot replace by its argument.
+		if rest, ok := strings.CutPrefix(fmt.Sprintf(format, args...), "keeping param "); ok {
+			result.Caveats = addCaveat(result.Caveats, "kept param "+rest)
+		}
+	}
+	hooks := &inlineAllHooks{
+		inlined: func(res *inline.Result) {
+			if res.BindingDecl {
+				result.Caveats = addCaveat(result.Caveats, "arguments bound to variables (var params = args) to keep their evaluation order and effects")
+			}
+			if res.Literalized {
+				result.Caveats = addCaveat(result.Caveats, "call replaced by a call of a function literal, func(params) { body }(args), as the body could not be inlined in place")
+			}
+		},
+		skipped: func(loc protocol.Location, err error) {
+			result.Caveats = addCaveat(result.Caveats, fmt.Sprintf("not inlined: %s:%d: %v", loc.URI.Path(), loc.Range.Start.Line+1, err))
+		},
+	}
+
+	// As in inlineCall, report panics of the inliner on ill-typed input as
+	// errors.
+	if len(pkg.ParseErrors())+len(pkg.TypeErrors()) > 0 {
+		defer func() {
+			if x := recover(); x != nil {
+				err = fmt.Errorf("inlining failed (%q), likely because inputs were ill-typed", x)
+			}
+		}()
+	}
+
+	// A function declaration: inline all its calls.
+	for _, decl := range pgf.File.Decls {
+		decl, ok := decl.(*ast.FuncDecl)
+		if !ok || start < decl.Name.Pos() || decl.Name.End() < end {
+			continue
+		}
+		if decl.Body == nil {
+			return nil, nil, fmt.Errorf("cannot inline %s: it has no body", decl.Name.Name)
+		}
+		result.Callee, result.AllCalls = decl.Name.Name, true
+		callee, err := inline.AnalyzeCallee(logf, pkg.FileSet(), pkg.Types(), pkg.TypesInfo(), decl, pgf.Src)
+		if err != nil {
+			return nil, nil, err
+		}
+		content, err := inlineAllCalls(ctx, snapshot, pkg, pgf, decl, callee, nil, hooks, &inline.Options{Logf: logf})
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(content) == 0 && len(result.Caveats) > 0 {
+			return nil, nil, fmt.Errorf("no calls of %s could be inlined: %s", decl.Name.Name, strings.Join(result.Caveats, "; "))
+		} else if len(content) == 0 {
+			return nil, nil, fmt.Errorf("no static calls of %s to inline", decl.Name.Name)
+		}
+		changes, err := contentChanges(ctx, snapshot, content)
+		if err != nil {
+			return nil, nil, err
+		}
+		return changes, result, nil
+	}
+
+	// Otherwise, inline the call.
+	call, fn, err := enclosingStaticCall(pkg, pgf, start, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot inline: %v; give the locator of a call, or of a function declaration to inline all its calls", err)
+	}
+	result.Callee = fn.Name()
+	calleePkg, calleePGF, calleePos, err := NarrowestDeclaringPackage(ctx, snapshot, pkg, fn)
+	if err != nil {
+		return nil, nil, err
+	}
+	i := slices.IndexFunc(calleePGF.File.Decls, func(decl ast.Decl) bool {
+		fn, ok := decl.(*ast.FuncDecl)
+		return ok && fn.Name.Pos() == calleePos
+	})
+	if i < 0 {
+		return nil, nil, fmt.Errorf("can't find callee")
+	}
+	callee, err := inline.AnalyzeCallee(logf, calleePkg.FileSet(), calleePkg.Types(), calleePkg.TypesInfo(), calleePGF.File.Decls[i].(*ast.FuncDecl), calleePGF.Src)
+	if err != nil {
+		return nil, nil, err
+	}
+	caller := &inline.Caller{
+		Fset:  pkg.FileSet(),
+		Types: pkg.Types(),
+		Info:  pkg.TypesInfo(),
+		File:  pgf.File,
+		Call:  call,
+	}
+	res, err := inline.Inline(caller, callee, &inline.Options{Logf: logf})
+	if err != nil {
+		return nil, nil, err
+	}
+	hooks.inlined(res)
+	changes, err := suggestedFixToDocumentChange(ctx, snapshot, pkg.FileSet(), &analysis.SuggestedFix{TextEdits: res.Edits})
+	if err != nil {
+		return nil, nil, err
+	}
+	return changes, result, nil
+}
+
+// addCaveat appends caveat to caveats unless it is already there; the
+// inliner reports the same decision for each call.
+func addCaveat(caveats []string, caveat string) []string {
+	if slices.Contains(caveats, caveat) {
+		return caveats
+	}
+	return append(caveats, caveat)
+}
+
+// contentChanges returns the changes that replace the content of each
+// file by the new content, as ChangeSignature does, in order of URI.
+func contentChanges(ctx context.Context, snapshot *cache.Snapshot, content map[protocol.DocumentURI][]byte) ([]protocol.DocumentChange, error) {
+	var changes []protocol.DocumentChange
+	for _, uri := range slices.Sorted(maps.Keys(content)) {
+		after := content[uri]
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		before, err := fh.Content()
+		if err != nil {
+			return nil, err
+		}
+		edits, err := protocol.EditsFromDiffEdits(protocol.NewMapper(uri, before), diff.Bytes(before, after))
+		if err != nil {
+			return nil, fmt.Errorf("computing edits for %s: %v", uri, err)
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, edits))
+	}
+	return changes, nil
+}
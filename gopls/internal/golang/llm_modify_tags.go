@@ -0,0 +1,195 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/fatih/gomodifytags/modifytags"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/tokeninternal"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+	internalastutil "golang.org/x/tools/internal/astutil"
+	"golang.org/x/tools/internal/diff"
+)
+
+// This file edits struct tags for the bridge, as the
+// refactor.rewrite.{add,remove}Tags code actions do, but on a struct
+// named by a locator and, optionally, on some of its fields only.
+
+// tagTransforms maps the transform names of the bridge, with case,
+// underscores and dashes ignored, to those of gomodifytags.
+var tagTransforms = map[string]modifytags.Transform{
+	"":           modifytags.SnakeCase,
+	"snakecase":  modifytags.SnakeCase,
+	"camelcase":  modifytags.CamelCase,
+	"lispcase":   modifytags.LispCase,
+	"pascalcase": modifytags.PascalCase,
+	"titlecase":  modifytags.TitleCase,
+	"keep":       modifytags.Keep,
+}
+
+// LLMModifyTags returns the changes that modify, as input says, the tags
+// of the fields of the struct type named at rng of fh, or of the fields
+// of input.Fields only. It also returns the struct and the fields whose
+// tags changed; the caller sets the diff.
+func LLMModifyTags(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, input api.IModifyTagsParams) ([]protocol.DocumentChange, *api.OModifyTagsResult, error) {
+	m, err := tagModification(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pkg, pgf, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := identObject(pkg, pgf, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	tname, ok := obj.(*types.TypeName)
+	if !ok || !is[*types.Struct](tname.Type().Underlying()) {
+		return nil, nil, fmt.Errorf("%s is not a struct type", obj.Name())
+	}
+	_, declPGF, declPos, err := NarrowestDeclaringPackage(ctx, snapshot, pkg, tname)
+	if err != nil {
+		return nil, nil, err
+	}
+	var st *ast.StructType
+	for n := range ast.Preorder(declPGF.File) {
+		if spec, ok := n.(*ast.TypeSpec); ok && spec.Name.Pos() == declPos {
+			st, _ = spec.Type.(*ast.StructType)
+			break
+		}
+	}
+	if st == nil {
+		return nil, nil, fmt.Errorf("%s is not declared by a struct type literal", tname.Name())
+	}
+
+	// Select the fields.
+	fieldName := func(f *ast.Field) []string {
+		if len(f.Names) == 0 {
+			_, id, _ := internalastutil.UnpackRecv(f.Type) // embedded field
+			if id == nil {
+				return nil
+			}
+			return []string{id.Name}
+		}
+		var names []string
+		for _, id := range f.Names {
+			names = append(names, id.Name)
+		}
+		return names
+	}
+	var fields []*ast.Field
+	for _, f := range st.Fields.List {
+		if len(input.Fields) == 0 || slices.ContainsFunc(fieldName(f), func(name string) bool { return slices.Contains(input.Fields, name) }) {
+			fields = append(fields, f)
+		}
+	}
+	for _, name := range input.Fields {
+		if !slices.ContainsFunc(st.Fields.List, func(f *ast.Field) bool { return slices.Contains(fieldName(f), name) }) {
+			return nil, nil, fmt.Errorf("%s has no field %s", tname.Name(), name)
+		}
+	}
+
+	// As in ModifyTags, modify a copy of the file.
+	cloned := internalastutil.CloneNode(declPGF.File)
+	fset := tokeninternal.FileSetFor(declPGF.Tok)
+	for _, f := range fields {
+		if err := m.Apply(fset, cloned, f.Pos(), f.End()); err != nil {
+			return nil, nil, fmt.Errorf("could not modify tags: %v", err)
+		}
+	}
+
+	// Report the fields whose tags changed; the copy keeps the positions.
+	tag := func(f *ast.Field) string {
+		if f.Tag == nil {
+			return ""
+		}
+		return f.Tag.Value
+	}
+	result := &api.OModifyTagsResult{Struct: tname.Name()}
+	for n := range ast.Preorder(cloned) {
+		if clonedST, ok := n.(*ast.StructType); ok && clonedST.Pos() == st.Pos() {
+			for i, f := range clonedST.Fields.List {
+				if tag(f) != tag(st.Fields.List[i]) {
+					result.Fields = append(result.Fields, strings.Join(fieldName(f), ", "))
+				}
+			}
+			break
+		}
+	}
+	if len(result.Fields) == 0 {
+		return nil, nil, fmt.Errorf("no tags of %s change", tname.Name())
+	}
+
+	var after bytes.Buffer
+	if err := format.Node(&after, fset, cloned); err != nil {
+		return nil, nil, err
+	}
+	edits, err := protocol.EditsFromDiffEdits(declPGF.Mapper, diff.Bytes(declPGF.Src, after.Bytes()))
+	if err != nil {
+		return nil, nil, err
+	}
+	declFH, err := snapshot.ReadFile(ctx, declPGF.URI)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []protocol.DocumentChange{protocol.DocumentChangeEdit(declFH, edits)}, result, nil
+}
+
+// tagModification returns the modification of struct tags that input
+// describes, as the modifyTags command handler does for its arguments.
+func tagModification(input api.IModifyTagsParams) (*modifytags.Modification, error) {
+	name := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(input.Transform))
+	transform, ok := tagTransforms[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown transform %q (want snakecase, camelcase, lispcase, pascalcase, titlecase or keep)", input.Transform)
+	}
+	options := func(opts []string) (map[string][]string, error) {
+		m := make(map[string][]string)
+		for _, opt := range opts {
+			key, option, ok := strings.Cut(opt, "=")
+			if !ok || key == "" || option == "" {
+				return nil, fmt.Errorf("invalid option %q (want tag=option, e.g. json=omitempty)", opt)
+			}
+			m[key] = append(m[key], option)
+		}
+		return m, nil
+	}
+	addOptions, err := options(input.AddOptions)
+	if err != nil {
+		return nil, err
+	}
+	removeOptions, err := options(input.RemoveOptions)
+	if err != nil {
+		return nil, err
+	}
+	m := &modifytags.Modification{
+		Add:                  input.Add,
+		AddOptions:           addOptions,
+		Remove:               input.Remove,
+		RemoveOptions:        removeOptions,
+		Overwrite:            input.Overwrite,
+		SkipUnexportedFields: input.SkipUnexported,
+		Transform:            transform,
+		Clear:                input.Clear,
+		ClearOptions:         input.ClearOptions,
+	}
+	if len(m.Add)+len(m.AddOptions)+len(m.Remove)+len(m.RemoveOptions) == 0 && !m.Clear && !m.ClearOptions {
+		return nil, fmt.Errorf("nothing to do: give add, remove, add_options, remove_options, clear or clear_options")
+	}
+	return m, nil
+}
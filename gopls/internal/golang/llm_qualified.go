@@ -0,0 +1,281 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// This file resolves qualified symbol names, as they appear in logs and stack
+// traces, to the context-file form of api.SymbolLocator that the rest of the
+// bridge understands, and anchors locators at the symbol's declaration so
+// that they can be resolved in other views.
+
+// qualifiedName is a parsed qualified symbol name. For
+// "github.com/org/svc/internal/store.(*DB).Query" it is
+// {pkgPath: "github.com/org/svc/internal/store", recv: "DB", name: "Query"}.
+type qualifiedName struct {
+	pkgPath string
+	recv    string // receiver or enclosing type, without '*'; "" for package-level symbols
+	name    string
+}
+
+var (
+	// closureSuffix matches the compiler-generated names of closures in stack
+	// traces, e.g. ".func1" or ".func2.3"; they resolve to the enclosing function.
+	closureSuffix = regexp.MustCompile(`(\.func\d+(\.\d+)*)+$`)
+	// typeArgs matches instantiation brackets, e.g. "[...]" or "[int,string]".
+	typeArgs = regexp.MustCompile(`\[[^\[\]]*\]`)
+)
+
+// parseQualifiedNames returns every reading of s as a qualified name, from
+// the longest package path to the shortest. There can be several because
+// the last element of an import path may itself contain dots (gopkg.in/yaml.v3).
+func parseQualifiedNames(s string) []qualifiedName {
+	s = strings.TrimSpace(s)
+	// Drop a stack trace argument list: "pkg.(*T).M(0xc000010000, 0x1)".
+	if strings.HasSuffix(s, ")") {
+		if i := strings.LastIndex(s, "("); i > strings.LastIndex(s, ".") {
+			s = s[:i]
+		}
+	}
+	s = closureSuffix.ReplaceAllString(s, "")
+	s = typeArgs.ReplaceAllString(s, "")
+
+	var names []qualifiedName
+	start := strings.LastIndex(s, "/") + 1
+	for i := len(s) - 1; i > start; i-- {
+		if s[i] != '.' {
+			continue
+		}
+		qn := qualifiedName{pkgPath: s[:i]}
+		if strings.ContainsAny(qn.pkgPath, "()*") {
+			continue
+		}
+		rest := s[i+1:]
+		if strings.HasPrefix(rest, "(") {
+			// "(*T).M" or "(T).M"
+			end := strings.Index(rest, ").")
+			if end < 0 {
+				continue
+			}
+			qn.recv = strings.TrimPrefix(rest[1:end], "*")
+			qn.name = rest[end+2:]
+		} else if recv, name, ok := strings.Cut(rest, "."); ok {
+			qn.recv, qn.name = recv, name
+		} else {
+			qn.name = rest
+		}
+		if qn.name == "" || strings.ContainsAny(qn.name, ".()*") || strings.ContainsAny(qn.recv, ".()*") {
+			continue
+		}
+		names = append(names, qn)
+	}
+	return names
+}
+
+// ResolveQualifiedName converts a locator given by QualifiedName into the
+// equivalent context-file locator: the declaring file as ContextFile, the
+// declaration line as LineHint, and the receiver or enclosing type as
+// ParentScope. Kind and SignatureSnippet are kept.
+//
+// The package is looked up in the snapshot's metadata graph, so any loaded
+// package qualifies, including dependencies and the standard library. If it
+// is not there, the error is an *api.ResolutionError with code
+// api.ErrPackageNotLoaded; other failures are reported as for ResolveNode.
+func ResolveQualifiedName(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator) (api.SymbolLocator, error) {
+	names := parseQualifiedNames(locator.QualifiedName)
+	if len(names) == 0 {
+		return api.SymbolLocator{}, fmt.Errorf("invalid qualified_name %q: want an import path followed by a name, e.g. \"net/http.(*Server).Serve\"", locator.QualifiedName)
+	}
+
+	graph, err := snapshot.LoadMetadataGraph(ctx)
+	if err != nil {
+		return api.SymbolLocator{}, fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	for _, qn := range names {
+		mp := packageForPath(graph, metadata.PackagePath(qn.pkgPath))
+		if mp == nil {
+			continue
+		}
+		pkgs, err := snapshot.TypeCheck(ctx, mp.ID)
+		if err != nil {
+			return api.SymbolLocator{}, &api.ResolutionError{
+				Code:       api.ErrPackageNotLoaded,
+				Message:    fmt.Sprintf("package %s could not be type-checked: %v", qn.pkgPath, err),
+				SymbolName: locator.QualifiedName,
+			}
+		}
+		return qualifiedLocator(pkgs[0], qn, locator)
+	}
+
+	return api.SymbolLocator{}, &api.ResolutionError{
+		Code:       api.ErrPackageNotLoaded,
+		Message:    fmt.Sprintf("no package in %q is loaded in this workspace; check the import path, or that some workspace package imports it", locator.QualifiedName),
+		SymbolName: locator.QualifiedName,
+	}
+}
+
+// packageForPath returns the non-test package with the given path, or nil.
+func packageForPath(graph *metadata.Graph, path metadata.PackagePath) *metadata.Package {
+	var fallback *metadata.Package
+	for _, mp := range graph.ForPackagePath[path] {
+		if mp.IsIntermediateTestVariant() {
+			continue
+		}
+		if mp.ForTest == "" {
+			return mp
+		}
+		if fallback == nil {
+			fallback = mp
+		}
+	}
+	return fallback
+}
+
+// qualifiedLocator looks qn up in pkg and builds the context-file locator
+// for its declaration.
+func qualifiedLocator(pkg *cache.Package, qn qualifiedName, locator api.SymbolLocator) (api.SymbolLocator, error) {
+	scope := pkg.Types().Scope()
+	// notFound reports that missing is not among names; names within a
+	// small edit distance are suggested, looked up as members of parent
+	// if it is set.
+	notFound := func(what, missing string, names []string, parent string) error {
+		rerr := &api.ResolutionError{
+			Code:       api.ErrNotFound,
+			Message:    fmt.Sprintf("%s not found in package %s", what, qn.pkgPath),
+			SymbolName: locator.QualifiedName,
+		}
+		maxDist := maxEditDistance(missing)
+		for _, name := range names {
+			if editDistance(strings.ToLower(name), strings.ToLower(missing)) > maxDist {
+				continue
+			}
+			var obj types.Object
+			if parent == "" {
+				obj = scope.Lookup(name)
+			} else if tn, ok := scope.Lookup(parent).(*types.TypeName); ok {
+				obj, _, _ = types.LookupFieldOrMethod(tn.Type(), true, pkg.Types(), name)
+			}
+			if obj != nil {
+				rerr.Suggestions = append(rerr.Suggestions, objectSymbol(pkg.FileSet(), obj, declaredParentScope(obj, parent)))
+			}
+		}
+		sortResolutionSymbols(rerr.Suggestions)
+		if len(rerr.Suggestions) > maxResolutionSymbols {
+			rerr.Suggestions = rerr.Suggestions[:maxResolutionSymbols]
+		}
+		return rerr
+	}
+
+	var (
+		obj    types.Object
+		parent string
+	)
+	if qn.recv == "" {
+		obj = scope.Lookup(qn.name)
+		if obj == nil {
+			return api.SymbolLocator{}, notFound(fmt.Sprintf("symbol '%s'", qn.name), qn.name, scope.Names(), "")
+		}
+	} else {
+		tn, ok := scope.Lookup(qn.recv).(*types.TypeName)
+		if !ok {
+			return api.SymbolLocator{}, notFound(fmt.Sprintf("type '%s'", qn.recv), qn.recv, scope.Names(), "")
+		}
+		var index []int
+		obj, index, _ = types.LookupFieldOrMethod(tn.Type(), true, pkg.Types(), qn.name)
+		if obj == nil {
+			return api.SymbolLocator{}, notFound(fmt.Sprintf("field or method '%s.%s'", qn.recv, qn.name), qn.name, memberNames(tn.Type()), qn.recv)
+		}
+		// Promoted fields are declared in another struct, so only a direct
+		// field can name its parent scope; the line hint finds the rest.
+		if len(index) == 1 {
+			parent = qn.recv
+		}
+		parent = declaredParentScope(obj, parent)
+	}
+
+	if !obj.Pos().IsValid() {
+		return api.SymbolLocator{}, fmt.Errorf("'%s' has no source position", locator.QualifiedName)
+	}
+	return declarationLocator(pkg.FileSet(), obj, parent, locator), nil
+}
+
+// declarationLocator returns the context-file locator for the declaration
+// of obj, keeping the Kind and SignatureSnippet of locator.
+func declarationLocator(fset *token.FileSet, obj types.Object, parent string, locator api.SymbolLocator) api.SymbolLocator {
+	posn := safetoken.StartPosition(fset, obj.Pos())
+	return api.SymbolLocator{
+		SymbolName:       obj.Name(),
+		ContextFile:      posn.Filename,
+		ParentScope:      parent,
+		Kind:             locator.Kind,
+		LineHint:         posn.Line,
+		SignatureSnippet: locator.SignatureSnippet,
+	}
+}
+
+// DeclarationLocator resolves locator in snapshot and returns the
+// context-file locator for the declaration of the symbol it denotes, along
+// with the path of the declaring package. The result resolves to the same
+// symbol in any view that loads the declaring file, even one that does not
+// load locator.ContextFile.
+func DeclarationLocator(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator) (api.SymbolLocator, string, error) {
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(locator.ContextFile))
+	if err != nil {
+		return api.SymbolLocator{}, "", err
+	}
+	result, err := ResolveNode(ctx, snapshot, fh, locator)
+	if err != nil {
+		return api.SymbolLocator{}, "", err
+	}
+	obj := result.Object
+	if obj == nil || obj.Pkg() == nil || !obj.Pos().IsValid() {
+		return api.SymbolLocator{}, "", fmt.Errorf("'%s' has no declaration in a package", locator.SymbolName)
+	}
+	pkg, _, err := NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return api.SymbolLocator{}, "", err
+	}
+	return declarationLocator(pkg.FileSet(), obj, declaredParentScope(obj, ""), locator), obj.Pkg().Path(), nil
+}
+
+// declaredParentScope returns the parent scope of a member: the declaring
+// receiver for methods, which may differ from the type it was looked up on,
+// and parent otherwise.
+func declaredParentScope(obj types.Object, parent string) string {
+	if fn, ok := obj.(*types.Func); ok && fn.Signature().Recv() != nil {
+		return getParentScope(obj, "")
+	}
+	return parent
+}
+
+// memberNames returns the names of the fields and methods of T and *T,
+// including promoted ones.
+func memberNames(T types.Type) []string {
+	var names []string
+	mset := types.NewMethodSet(types.NewPointer(T))
+	for i := range mset.Len() {
+		names = append(names, mset.At(i).Obj().Name())
+	}
+	if st, ok := T.Underlying().(*types.Struct); ok {
+		for i := range st.NumFields() {
+			names = append(names, st.Field(i).Name())
+		}
+	}
+	return names
+}
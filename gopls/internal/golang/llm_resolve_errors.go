@@ -0,0 +1,434 @@
+// Copyright 2025 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package golang
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/cache/symbols"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// This file builds the structured *api.ResolutionError values returned by
+// ResolveNode, so that an agent can retry with a better locator instead of
+// guessing from a flat error string.
+
+// maxResolutionSymbols caps the candidates and suggestions in a ResolutionError.
+const maxResolutionSymbols = 10
+
+func fileNotInWorkspaceError(fh file.Handle, locator api.SymbolLocator) *api.ResolutionError {
+	msg := fmt.Sprintf("%s does not belong to any package in the workspace", locator.ContextFile)
+	if _, err := fh.Content(); err != nil {
+		msg = fmt.Sprintf("%s cannot be read: %v", locator.ContextFile, err)
+	}
+	return &api.ResolutionError{
+		Code:        api.ErrFileNotInWorkspace,
+		Message:     msg,
+		SymbolName:  locator.SymbolName,
+		ContextFile: locator.ContextFile,
+	}
+}
+
+func packageNotLoadedError(locator api.SymbolLocator, mp *metadata.Package, err error) *api.ResolutionError {
+	return &api.ResolutionError{
+		Code:        api.ErrPackageNotLoaded,
+		Message:     fmt.Sprintf("package %s containing %s could not be type-checked: %v", mp.PkgPath, locator.ContextFile, err),
+		SymbolName:  locator.SymbolName,
+		ContextFile: locator.ContextFile,
+	}
+}
+
+// originObject maps instantiated generic functions, methods and fields back
+// to their declaration, so that all uses of one declaration compare equal.
+func originObject(obj types.Object) types.Object {
+	switch obj := obj.(type) {
+	case *types.Func:
+		return obj.Origin()
+	case *types.Var:
+		return obj.Origin()
+	}
+	return obj
+}
+
+// distinctCandidates returns one occurrence per distinct symbol among
+// candidates, preferring the declaring occurrence.
+func distinctCandidates(candidates []ResolveNodeResult) []*ResolveNodeResult {
+	var distinct []*ResolveNodeResult
+	index := make(map[types.Object]int)
+	for i := range candidates {
+		c := &candidates[i]
+		if c.Object == nil {
+			continue
+		}
+		obj := originObject(c.Object)
+		if j, ok := index[obj]; ok {
+			if c.IsDefinition && !distinct[j].IsDefinition {
+				distinct[j] = c
+			}
+			continue
+		}
+		index[obj] = len(distinct)
+		distinct = append(distinct, c)
+	}
+	return distinct
+}
+
+// ambiguousCandidates returns one occurrence per distinct symbol among
+// candidates if the locator cannot tell them apart, or nil if it singles
+// one out. A line hint always decides, since the closest occurrence wins;
+// a signature snippet decides unless several symbols match it equally well.
+func ambiguousCandidates(candidates []ResolveNodeResult, locator api.SymbolLocator) []*ResolveNodeResult {
+	if locator.LineHint > 0 {
+		return nil
+	}
+	distinct := distinctCandidates(candidates)
+	if len(distinct) < 2 {
+		return nil
+	}
+
+	if locator.SignatureSnippet != "" {
+		sims := make([]float64, len(distinct))
+		best := 0.0
+		for i, c := range distinct {
+			sims[i] = signatureSimilarity(locator.SignatureSnippet, candidateSignature(c.Object))
+			best = max(best, sims[i])
+		}
+		var tied []*ResolveNodeResult
+		for i, c := range distinct {
+			if sims[i] >= best-similarityEpsilon {
+				tied = append(tied, c)
+			}
+		}
+		if len(tied) < 2 {
+			return nil
+		}
+		distinct = tied
+	}
+	return distinct
+}
+
+func ambiguousError(pkg *cache.Package, locator api.SymbolLocator, distinct []*ResolveNodeResult) *api.ResolutionError {
+	rerr := &api.ResolutionError{
+		Code: api.ErrAmbiguous,
+		Message: fmt.Sprintf("'%s' matches %d distinct symbols in %s; add parent_scope, kind, line_hint or signature_snippet to choose one",
+			locator.SymbolName, len(distinct), locator.ContextFile),
+		SymbolName:  locator.SymbolName,
+		ContextFile: locator.ContextFile,
+	}
+	for _, c := range distinct {
+		rerr.Candidates = append(rerr.Candidates, objectSymbol(pkg.FileSet(), c.Object, candidateParentScope(c)))
+	}
+	sortResolutionSymbols(rerr.Candidates)
+	if len(rerr.Candidates) > maxResolutionSymbols {
+		rerr.Candidates = rerr.Candidates[:maxResolutionSymbols]
+	}
+	return rerr
+}
+
+// candidateParentScope returns the parent_scope value that selects c's
+// symbol, or "" for package-level symbols that need none.
+func candidateParentScope(c *ResolveNodeResult) string {
+	obj := c.Object
+	if fn, ok := obj.(*types.Func); ok && fn.Signature().Recv() != nil {
+		return getParentScope(obj, "")
+	}
+	if obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+		return ""
+	}
+	if c.IsDefinition {
+		// Locals and fields: the enclosing function or type at the declaration.
+		return c.EnclosingFunc
+	}
+	return ""
+}
+
+// notFoundError reports that no occurrence of locator.SymbolName passed the
+// locator's filters. rejected holds occurrences of the name that were
+// excluded by parent_scope or kind; they are the most likely intended
+// symbols and are suggested first, followed by the same name declared
+// elsewhere in the workspace and similarly spelled names in scope.
+func notFoundError(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, locator api.SymbolLocator, rejected []ResolveNodeResult) *api.ResolutionError {
+	msg := fmt.Sprintf("symbol '%s' not found in %s", locator.SymbolName, locator.ContextFile)
+	if len(rejected) > 0 {
+		var filters []string
+		if locator.ParentScope != "" {
+			filters = append(filters, "parent_scope="+locator.ParentScope)
+		}
+		if locator.Kind != "" {
+			filters = append(filters, "kind="+locator.Kind)
+		}
+		msg = fmt.Sprintf("symbol '%s' occurs in %s, but not with %s", locator.SymbolName, locator.ContextFile, strings.Join(filters, ", "))
+	}
+	rerr := &api.ResolutionError{
+		Code:        api.ErrNotFound,
+		Message:     msg,
+		SymbolName:  locator.SymbolName,
+		ContextFile: locator.ContextFile,
+	}
+
+	seen := make(map[string]bool) // file:line:name
+	add := func(sym api.Symbol) {
+		key := fmt.Sprintf("%s:%d:%s", sym.FilePath, sym.Line, sym.Name)
+		if !seen[key] {
+			seen[key] = true
+			rerr.Suggestions = append(rerr.Suggestions, sym)
+		}
+	}
+
+	var filtered []api.Symbol
+	for _, c := range distinctCandidates(rejected) {
+		filtered = append(filtered, objectSymbol(pkg.FileSet(), c.Object, candidateParentScope(c)))
+	}
+	sortResolutionSymbols(filtered)
+	for _, sym := range filtered {
+		add(sym)
+	}
+	for _, sym := range nearMissSymbols(ctx, snapshot, pkg, pgf, locator.SymbolName) {
+		add(sym)
+	}
+	if len(rerr.Suggestions) > maxResolutionSymbols {
+		rerr.Suggestions = rerr.Suggestions[:maxResolutionSymbols]
+	}
+	return rerr
+}
+
+// nearMissSymbols returns declarations named exactly name anywhere in the
+// workspace, and declarations in the context package and file whose names
+// are within a small edit distance of name, closest first.
+func nearMissSymbols(ctx context.Context, snapshot *cache.Snapshot, pkg *cache.Package, pgf *parsego.File, name string) []api.Symbol {
+	type nearMiss struct {
+		sym  api.Symbol
+		dist int
+	}
+	var misses []nearMiss
+	maxDist := maxEditDistance(name)
+	consider := func(sym api.Symbol, samePackage bool) {
+		if sym.Name == name {
+			misses = append(misses, nearMiss{sym, -1}) // exact matches sort first
+			return
+		}
+		if !samePackage {
+			return
+		}
+		if d := editDistance(strings.ToLower(sym.Name), strings.ToLower(name)); d <= maxDist {
+			misses = append(misses, nearMiss{sym, d})
+		}
+	}
+
+	// Package-level declarations, fields and methods, from the workspace symbol index.
+	// The context package comes first: it may have been loaded ad hoc,
+	// outside the workspace packages.
+	if mps, err := snapshot.WorkspaceMetadata(ctx); err == nil {
+		mps = append([]*metadata.Package{pkg.Metadata()}, mps...)
+		var ids []metadata.PackageID
+		for _, mp := range mps {
+			ids = append(ids, mp.ID)
+		}
+		if symbolPkgs, err := snapshot.Symbols(ctx, ids...); err == nil {
+			seenFiles := make(map[protocol.DocumentURI]bool)
+			for i, sp := range symbolPkgs {
+				if sp == nil {
+					continue
+				}
+				mp := mps[i]
+				samePackage := mp.PkgPath == pkg.Metadata().PkgPath
+				for j, syms := range sp.Symbols {
+					uri := sp.Files[j]
+					if seenFiles[uri] {
+						continue
+					}
+					seenFiles[uri] = true
+					for _, s := range syms {
+						consider(indexSymbol(s, string(mp.PkgPath), uri), samePackage)
+					}
+				}
+			}
+		}
+	}
+
+	// Local declarations in the context file, which the index does not cover.
+	for ident, obj := range pkg.TypesInfo().Defs {
+		if obj == nil || ident.Pos() < pgf.File.FileStart || ident.Pos() > pgf.File.FileEnd {
+			continue
+		}
+		if obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope() {
+			continue // package-level: already in the index
+		}
+		if v, ok := obj.(*types.Var); ok && v.IsField() {
+			continue
+		}
+		if _, ok := obj.(*types.Func); ok {
+			continue // methods: already in the index
+		}
+		consider(objectSymbol(pkg.FileSet(), obj, enclosingFuncName(pgf.File, ident.Pos())), true)
+	}
+
+	slices.SortFunc(misses, func(a, b nearMiss) int {
+		if c := cmp.Compare(a.dist, b.dist); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.sym.FilePath, b.sym.FilePath); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.sym.Line, b.sym.Line); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.sym.Name, b.sym.Name)
+	})
+	syms := make([]api.Symbol, 0, len(misses))
+	for _, m := range misses {
+		syms = append(syms, m.sym)
+	}
+	return syms
+}
+
+// indexSymbol converts a workspace symbol index entry, whose name is
+// qualified by its enclosing types (e.g. "Server.Start"), to an api.Symbol.
+func indexSymbol(s symbols.Symbol, pkgPath string, uri protocol.DocumentURI) api.Symbol {
+	sym := api.Symbol{
+		Name:        s.Name,
+		Kind:        ConvertLSPSymbolKind(s.Kind),
+		PackagePath: pkgPath,
+		FilePath:    uri.Path(),
+		Line:        int(s.Range.Start.Line) + 1,
+	}
+	if i := strings.LastIndex(s.Name, "."); i >= 0 {
+		parent := s.Name[:i]
+		if j := strings.LastIndex(parent, "."); j >= 0 {
+			parent = parent[j+1:]
+		}
+		sym.Name = s.Name[i+1:]
+		if sym.Kind == api.SymbolKindMethod {
+			sym.Receiver = parent
+		} else {
+			sym.Parent = parent
+		}
+	}
+	return sym
+}
+
+// enclosingFuncName returns the name of the function declaration enclosing
+// pos, formatted as the parent scope of its locals.
+func enclosingFuncName(f *ast.File, pos token.Pos) string {
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || pos < fn.Pos() || pos > fn.End() {
+			continue
+		}
+		if fn.Recv == nil {
+			return fn.Name.Name
+		}
+		return fmt.Sprintf("(%s).%s", getReceiverTypeName(fn.Recv), fn.Name.Name)
+	}
+	return ""
+}
+
+// objectSymbol describes obj as an api.Symbol; parent is the parent_scope
+// that selects it, if any.
+func objectSymbol(fset *token.FileSet, obj types.Object, parent string) api.Symbol {
+	sym := api.Symbol{
+		Name:      obj.Name(),
+		Kind:      objectSymbolKind(obj),
+		Signature: candidateSignature(obj),
+	}
+	if sym.Kind == api.SymbolKindMethod {
+		sym.Receiver = parent
+	} else {
+		sym.Parent = parent
+	}
+	if obj.Pkg() != nil {
+		sym.PackagePath = obj.Pkg().Path()
+	}
+	if obj.Pos().IsValid() {
+		posn := safetoken.StartPosition(fset, obj.Pos())
+		sym.FilePath = posn.Filename
+		sym.Line = posn.Line
+	}
+	return sym
+}
+
+// objectSymbolKind returns the api.SymbolKind of obj.
+func objectSymbolKind(obj types.Object) api.SymbolKind {
+	switch obj := obj.(type) {
+	case *types.Func:
+		if obj.Signature().Recv() != nil {
+			return api.SymbolKindMethod
+		}
+		return api.SymbolKindFunction
+	case *types.TypeName:
+		switch obj.Type().Underlying().(type) {
+		case *types.Struct:
+			return api.SymbolKindStruct
+		case *types.Interface:
+			return api.SymbolKindInterface
+		}
+		return api.SymbolKindType
+	case *types.Var:
+		if obj.IsField() {
+			return api.SymbolKindField
+		}
+		return api.SymbolKindVariable
+	case *types.Const:
+		return api.SymbolKindConstant
+	}
+	return api.SymbolKindType
+}
+
+func sortResolutionSymbols(syms []api.Symbol) {
+	slices.SortFunc(syms, func(a, b api.Symbol) int {
+		if c := cmp.Compare(a.FilePath, b.FilePath); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Line, b.Line)
+	})
+}
+
+// maxEditDistance is the largest edit distance at which a name is still
+// considered a misspelling of name.
+func maxEditDistance(name string) int {
+	switch n := len(name); {
+	case n <= 3:
+		return 1
+	case n <= 6:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
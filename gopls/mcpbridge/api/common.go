@@ -19,7 +19,7 @@ type SymbolLocator struct {
 	// - For variables: "retryCount"
 	//
 	// Example: "ServeHTTP"
-	SymbolName string `json:"symbol_name" jsonschema:"The exact name of the function, struct, method, or variable to find. Do not include package prefixes (e.g., use 'Println', not 'fmt.Println')."`
+	SymbolName string `json:"symbol_name,omitempty" jsonschema:"The exact name of the function, struct, method, or variable to find. Do not include package prefixes (e.g., use 'Println', not 'fmt.Println'). Required unless qualified_name is set."`
 
 	// ContextFile is the absolute path of the file where the LLM is currently reading
 	// or where the reference to the symbol occurs.
@@ -29,7 +29,23 @@ type SymbolLocator struct {
 	// 2. Resolve local scopes (if the symbol is a local variable).
 	//
 	// Example: "/Users/dev/project/server/http.go"
-	ContextFile string `json:"context_file" jsonschema:"The absolute path of the file you are currently reading or analyzing. This serves as the starting point for resolution."`
+	ContextFile string `json:"context_file,omitempty" jsonschema:"The absolute path of the file you are currently reading or analyzing. This serves as the starting point for resolution. Required unless qualified_name is set."`
+
+	// QualifiedName identifies the symbol by import path, as in logs and
+	// stack traces, when there is no meaningful file to anchor on.
+	//
+	// Usage:
+	// - Package-level symbols: "import/path.Name"
+	// - Methods and fields: "import/path.(*Type).Name", "import/path.Type.Name"
+	// - Stack trace frames are accepted as is: argument lists, closure suffixes
+	//   (".func1") and type arguments are ignored.
+	//
+	// When set, SymbolName and ContextFile may be omitted: the symbol is looked
+	// up in the package scope and the locator is anchored at its declaration.
+	// If ContextFile is also given, it selects the workspace to search.
+	//
+	// Example: "github.com/org/svc/internal/store.(*DB).Query"
+	QualifiedName string `json:"qualified_name,omitempty" jsonschema:"Fully qualified name, e.g. 'github.com/org/svc/internal/store.(*DB).Query' or 'net/http.ListenAndServe', as found in logs and stack traces. Use instead of symbol_name and context_file when you have no file to anchor on."`
 
 	// PackageIdentifier is the package name or alias *as seen in the ContextFile*.
 	//
@@ -77,9 +93,70 @@ type SymbolLocator struct {
 	//
 	// Usage:
 	// - Used for "Hallucination Verification".
-	// - If the resolver finds a symbol, it compares it against this snippet.
-	// - If they don't look similar, the tool may return a warning or try finding a better match.
+	// - Every candidate's signature is compared against this snippet by
+	//   identifier similarity; the closest match wins over line_hint.
+	// - If the chosen symbol still doesn't look similar, the result carries a
+	//   signature_mismatch ResolutionWarning with the actual signature.
 	//
 	// Example: "func (s *Server) Start(ctx context.Context)"
-	SignatureSnippet string `json:"signature_snippet,omitempty" jsonschema:"A distinct snippet of code (like the function signature) used to verify the match."`
+	SignatureSnippet string `json:"signature_snippet,omitempty" jsonschema:"A distinct snippet of code (like the function signature) used to verify the match and to prefer the closest of several same-named symbols."`
+}
+
+// WarningSignatureMismatch is the ResolutionWarning code reported when the
+// resolved symbol's signature does not resemble SymbolLocator.SignatureSnippet.
+const WarningSignatureMismatch = "signature_mismatch"
+
+// ResolutionWarning flags a symbol that was resolved but may not be the one
+// the caller meant. The tool still returns its result for the resolved symbol.
+type ResolutionWarning struct {
+	// Code identifies the kind of warning, e.g. WarningSignatureMismatch.
+	Code string `json:"code" jsonschema:"machine-readable warning code (e.g. signature_mismatch)"`
+	// Message is a human-readable explanation.
+	Message string `json:"message" jsonschema:"human-readable explanation"`
+	// ExpectedSnippet is the SignatureSnippet given in the locator.
+	ExpectedSnippet string `json:"expected_snippet,omitempty" jsonschema:"the signature_snippet from the locator"`
+	// ActualSignature is the signature of the symbol that was resolved.
+	ActualSignature string `json:"actual_signature,omitempty" jsonschema:"the signature of the symbol that was actually resolved"`
+	// Similarity is the token similarity between the two, from 0 to 1.
+	Similarity float64 `json:"similarity,omitempty" jsonschema:"token similarity between the snippet and the actual signature (0-1)"`
+}
+
+// ResolutionErrorCode classifies why a SymbolLocator could not be resolved.
+type ResolutionErrorCode string
+
+const (
+	// ErrNotFound: no symbol with the given name (and filters) occurs in the context file.
+	ErrNotFound ResolutionErrorCode = "NOT_FOUND"
+	// ErrAmbiguous: several distinct symbols match and nothing in the locator picks one.
+	ErrAmbiguous ResolutionErrorCode = "AMBIGUOUS"
+	// ErrFileNotInWorkspace: the context file does not belong to any package gopls knows about.
+	ErrFileNotInWorkspace ResolutionErrorCode = "FILE_NOT_IN_WORKSPACE"
+	// ErrPackageNotLoaded: the package could not be type-checked, or (for a
+	// qualified_name) is not loaded in the workspace at all.
+	ErrPackageNotLoaded ResolutionErrorCode = "PACKAGE_NOT_LOADED"
+)
+
+// ResolutionError is returned when a SymbolLocator cannot be resolved to
+// exactly one symbol. It carries enough information for the caller to retry
+// with a disambiguated locator instead of guessing.
+//
+// For candidates and suggestions, the value to pass as parent_scope is
+// Receiver for methods and Parent for everything else.
+type ResolutionError struct {
+	// Code classifies the failure.
+	Code ResolutionErrorCode `json:"code" jsonschema:"machine-readable error code: NOT_FOUND, AMBIGUOUS, FILE_NOT_IN_WORKSPACE or PACKAGE_NOT_LOADED"`
+	// Message is a human-readable explanation.
+	Message string `json:"message" jsonschema:"human-readable explanation"`
+	// SymbolName and ContextFile echo the locator that failed.
+	SymbolName  string `json:"symbol_name,omitempty" jsonschema:"the symbol_name from the locator"`
+	ContextFile string `json:"context_file,omitempty" jsonschema:"the context_file from the locator"`
+	// Candidates lists the distinct symbols that matched an AMBIGUOUS locator.
+	Candidates []Symbol `json:"candidates,omitempty" jsonschema:"for AMBIGUOUS: the symbols that matched; retry with the parent_scope, kind or line_hint of the one you meant"`
+	// Suggestions lists near misses for a NOT_FOUND locator: the same name
+	// declared elsewhere, and similarly spelled names in scope.
+	Suggestions []Symbol `json:"suggestions,omitempty" jsonschema:"for NOT_FOUND: the same name declared in other files or packages, and similarly spelled names in scope"`
+}
+
+func (e *ResolutionError) Error() string {
+	return string(e.Code) + ": " + e.Message
 }
@@ -7,6 +7,21 @@ type ISymbolReferencesParams struct {
 	// This uses semantic information (symbol name, context file, package, scope)
 	// instead of error-prone line/column numbers.
 	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator (symbol_name, context_file, package_name, parent_scope, kind, line_hint)"`
+	// IncludeDynamic tags each reference to a concrete method as static or
+	// through one of the interface methods it implements.
+	IncludeDynamic bool `json:"include_dynamic,omitempty" jsonschema:"for a concrete method, tag each reference as static or through an interface method it implements, with the interface (default: false, untagged)"`
+	// OnlyWrites keeps the references of kind "write".
+	OnlyWrites bool `json:"only_writes,omitempty" jsonschema:"only report writes: assignments, increments, composite literal keys and address-taking (default: false)"`
+	// OnlyCalls keeps the references of kind "call". With OnlyWrites, both kinds are kept.
+	OnlyCalls bool `json:"only_calls,omitempty" jsonschema:"only report calls; combined with only_writes, report both (default: false)"`
+	// ExcludeTests drops the references in _test.go files.
+	ExcludeTests bool `json:"exclude_tests,omitempty" jsonschema:"leave out references in _test.go files (default: false)"`
+	// ExcludeGenerated drops the references in generated files.
+	ExcludeGenerated bool `json:"exclude_generated,omitempty" jsonschema:"leave out references in generated files, marked '// Code generated ... DO NOT EDIT.' (default: false)"`
+	// Cursor is the next_cursor of a previous response, to fetch the next page.
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor from a previous response, to fetch the next page of that result (the other parameters are then ignored, except page_size)"`
+	// PageSize is the number of references per page.
+	PageSize int `json:"page_size,omitempty" jsonschema:"number of references per page (default: 100, max: 1000)"`
 }
 
 // OSymbolReferencesResult is the output for go_symbol_references tool.
@@ -16,6 +31,9 @@ type OSymbolReferencesResult struct {
 	// This provides signature, documentation, and snippet for each symbol.
 	Symbols []*Symbol `json:"symbols,omitempty" jsonschema:"rich symbol information for each referenced symbol"`
 
+	// References lists the references found, in order.
+	References []SymbolReference `json:"references,omitempty" jsonschema:"the references found"`
+
 	// TotalCount is the total number of references found.
 	TotalCount int `json:"total_count,omitempty" jsonschema:"total number of references found"`
 	// Returned is the number of references returned in this response.
@@ -24,6 +42,29 @@ type OSymbolReferencesResult struct {
 	Truncated bool `json:"truncated,omitempty" jsonschema:"whether the result was truncated due to size limits"`
 	// Hint provides guidance when results are truncated.
 	Hint string `json:"hint,omitempty" jsonschema:"suggestion for getting more details"`
+	// NextCursor is the cursor of the next page, if any.
+	NextCursor string `json:"next_cursor,omitempty" jsonschema:"pass as cursor to fetch the next page; empty on the last page"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
+}
+
+// SymbolReference is one reference to a symbol.
+type SymbolReference struct {
+	// File is the file path.
+	File string `json:"file" jsonschema:"the file path"`
+	// Line is the line number (1-indexed).
+	Line int `json:"line" jsonschema:"the line number (1-indexed)"`
+	// Column is the column number (1-indexed).
+	Column int `json:"column" jsonschema:"the column number (1-indexed)"`
+	// Kind is what the code does with the symbol: "read", "write", "call",
+	// "type" or "embed".
+	Kind string `json:"kind,omitempty" jsonschema:"read, write (assignment, increment, composite literal key, address taken), call, type (type expression or conversion) or embed (embedded field)"`
+	// Dispatch is "static" for a reference to a concrete method itself and
+	// "interface" for one to an interface method it implements. It is only
+	// set when include_dynamic is.
+	Dispatch string `json:"dispatch,omitempty" jsonschema:"static, or interface for a reference through an interface method (only with include_dynamic)"`
+	// Interface is the interface of a reference with dispatch "interface".
+	Interface string `json:"interface,omitempty" jsonschema:"the interface, for dispatch interface"`
 }
 
 // IRenameSymbolParams is the input for go_dryrun_rename_symbol tool.
@@ -42,6 +83,49 @@ type ORenameSymbolResult struct {
 	// Changes is a line-by-line diff format that's LLM-friendly.
 	// Each change shows the complete old/new line content for easy verification and rewriting.
 	Changes []RenameChange `json:"changes,omitempty" jsonschema:"line-by-line changes with full line content"`
+	// FileHashes maps each file the rename would modify to the SHA-256 of the
+	// content the preview was computed from. Pass it to go_apply_rename as
+	// expected_hashes to make sure the previewed changes are the ones applied.
+	FileHashes map[string]string `json:"file_hashes,omitempty" jsonschema:"SHA-256 of each touched file's content the preview was computed from (pass to go_apply_rename as expected_hashes)"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
+}
+
+// IApplyRenameParams is the input for go_apply_rename tool.
+type IApplyRenameParams struct {
+	// Locator specifies the symbol to rename.
+	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator (symbol_name, context_file, package_name, parent_scope, kind, line_hint)"`
+	// NewName is the new name for the symbol.
+	NewName string `json:"new_name" jsonschema:"the new name for the symbol"`
+	// ExpectedHashes optionally pins the content of the touched files, as
+	// returned in file_hashes by go_dryrun_rename_symbol.
+	ExpectedHashes map[string]string `json:"expected_hashes,omitempty" jsonschema:"optional file path -> SHA-256 map from go_dryrun_rename_symbol file_hashes; the rename is refused if any file changed since the preview"`
+}
+
+// OApplyRenameResult is the output for go_apply_rename tool.
+type OApplyRenameResult struct {
+	// Applied reports whether the changes were written to disk.
+	Applied bool `json:"applied" jsonschema:"whether the rename was written to disk"`
+	// FilesChanged lists the files that were modified, created, or moved.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files that were modified, created, or moved"`
+	// Conflicts lists the files whose content no longer matches what the
+	// rename was computed from. When non-empty, nothing was written.
+	Conflicts []RenameConflict `json:"conflicts,omitempty" jsonschema:"stale files that prevented the rename (nothing is written when present)"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"apply rename summary"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
+}
+
+// RenameConflict reports a file whose content changed underneath a rename.
+type RenameConflict struct {
+	File string `json:"file" jsonschema:"file path"`
+	// Reason explains the mismatch.
+	Reason string `json:"reason" jsonschema:"why the file is considered stale"`
+	// ExpectedHash is the hash the rename was computed from (or the caller's expected hash).
+	ExpectedHash string `json:"expected_hash,omitempty" jsonschema:"the content hash the rename expected"`
+	// ActualHash is the hash of the current content, empty if the file is missing.
+	ActualHash string `json:"actual_hash,omitempty" jsonschema:"the current content hash (empty if the file is missing)"`
 }
 
 // RenameChange represents a single line change in a rename operation.
@@ -62,6 +146,14 @@ type IImplementationParams struct {
 	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator (symbol_name, context_file, package_name, parent_scope, kind, line_hint)"`
 	// IncludeBody indicates whether to include the function body in the returned Symbols.
 	IncludeBody bool `json:"include_body,omitempty" jsonschema:"whether to include function bodies in the returned Symbols (default: false)"`
+	// IncludeDependencies also reports the types outside the workspace (standard library, dependencies) that implement an interface.
+	IncludeDependencies bool `json:"include_dependencies,omitempty" jsonschema:"for an interface, also report implementations outside the workspace, e.g. in the standard library (default: false)"`
+	// ExcludeTrivial leaves out the interfaces almost every type implements, such as error and fmt.Stringer.
+	ExcludeTrivial bool `json:"exclude_trivial,omitempty" jsonschema:"for a type, leave out trivial interfaces such as error, fmt.Stringer and fmt.GoStringer (default: false)"`
+	// Cursor is the next_cursor of a previous response, to fetch the next page.
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor from a previous response, to fetch the next page of that result (the other parameters are then ignored, except page_size)"`
+	// PageSize is the number of implementations per page.
+	PageSize int `json:"page_size,omitempty" jsonschema:"number of implementations per page (default: 50, max: 1000)"`
 }
 
 // OImplementationResult is the output for go_implementation tool.
@@ -70,6 +162,12 @@ type OImplementationResult struct {
 	Symbols []*Symbol `json:"symbols,omitempty" jsonschema:"rich symbol information for each implementation"`
 	// Summary is a human-readable summary of the results.
 	Summary string `json:"summary" jsonschema:"implementation results summary"`
+	// TotalCount is the number of implementations on all pages.
+	TotalCount int `json:"total_count,omitempty" jsonschema:"total number of implementations found"`
+	// NextCursor is the cursor of the next page, if any.
+	NextCursor string `json:"next_cursor,omitempty" jsonschema:"pass as cursor to fetch the next page; empty on the last page"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
 }
 
 // IListToolsParams is the input for list_tools tool.
@@ -112,6 +210,8 @@ type ODefinitionResult struct {
 	Symbol *Symbol `json:"symbol,omitempty" jsonschema:"the symbol at the definition location"`
 	// Summary is a human-readable summary of the result.
 	Summary string `json:"summary" jsonschema:"definition result summary"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
 }
 
 // IDependencyGraphParams is the input for get_dependency_graph tool.
@@ -125,6 +225,11 @@ type IDependencyGraphParams struct {
 	IncludeTransitive bool `json:"include_transitive,omitempty" jsonschema:"whether to include transitive dependencies (default: false)"`
 	// MaxDepth limits the depth of transitive dependency traversal.
 	MaxDepth int `json:"max_depth,omitempty" jsonschema:"maximum depth for transitive dependencies (default: 0 = unlimited)"`
+	// Cursor is the next_cursor of a previous response, to fetch the next page.
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor from a previous response, to fetch the next page of that result (the other parameters are then ignored, except page_size)"`
+	// PageSize is the number of packages per page. The pages run through
+	// the dependencies, then the dependents.
+	PageSize int `json:"page_size,omitempty" jsonschema:"number of packages per page, dependencies first, then dependents (default: 200, max: 1000)"`
 }
 
 // ODependencyGraphResult is the output for get_dependency_graph tool.
@@ -137,6 +242,7 @@ type ODependencyGraphResult struct {
 	TotalDependencies int                 `json:"total_dependencies,omitempty" jsonschema:"total number of dependencies"`
 	TotalDependents   int                 `json:"total_dependents,omitempty" jsonschema:"total number of dependents"`
 	Truncated         bool                `json:"truncated,omitempty" jsonschema:"whether results were truncated"`
+	NextCursor        string              `json:"next_cursor,omitempty" jsonschema:"pass as cursor to fetch the next page; empty on the last page"`
 }
 
 // PackageDependency represents a package that is imported by the analyzed package.
@@ -163,8 +269,23 @@ type ICallHierarchyParams struct {
 	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator (symbol_name, context_file, package_name, parent_scope, kind, line_hint)"`
 	// Direction determines which direction to traverse: "incoming", "outgoing", or "both".
 	Direction string `json:"direction,omitempty" jsonschema:"call hierarchy direction (incoming/outgoing/both, default: both)"`
-	// Cwd optionally specifies the working directory for call hierarchy analysis.
-	Cwd string `json:"Cwd,omitempty" jsonschema:"the working directory for call hierarchy analysis (default: use default view)"`
+	// Cwd is ignored: like every locator-based tool, the view is chosen
+	// from the locator. It is kept so that existing callers stay valid.
+	Cwd string `json:"Cwd,omitempty" jsonschema:"ignored; the view is chosen from the locator (kept for compatibility)"`
+	// MaxDepth is how many levels of callers or callees to expand.
+	// 1 (the default) lists the direct calls only.
+	MaxDepth int `json:"max_depth,omitempty" jsonschema:"number of levels of calls to expand in each direction (default: 1 = direct calls only, max: 10)"`
+	// IncludeDynamic tags each incoming call of a concrete method as static
+	// or through one of the interface methods it implements.
+	IncludeDynamic bool `json:"include_dynamic,omitempty" jsonschema:"for a concrete method, tag each incoming call as static or through an interface method it implements, with the interface (default: false, untagged)"`
+	// MaxNodes bounds the number of nodes in each direction's tree.
+	MaxNodes int `json:"max_nodes,omitempty" jsonschema:"maximum number of nodes per direction when max_depth > 1 (default: 100, max: 500)"`
+	// Cursor is the next_cursor of a previous response, to fetch the next page.
+	Cursor string `json:"cursor,omitempty" jsonschema:"next_cursor from a previous response, to fetch the next page of that result (the other parameters are then ignored, except page_size)"`
+	// PageSize is the number of direct calls per page. The pages run
+	// through the incoming calls, then the outgoing ones. The trees of
+	// max_depth > 1 are bounded by max_nodes instead.
+	PageSize int `json:"page_size,omitempty" jsonschema:"number of direct calls per page, incoming first, then outgoing; not used when max_depth > 1 (default: 100, max: 1000)"`
 }
 
 // OCallHierarchyResult is the output for get_call_hierarchy tool.
@@ -179,8 +300,20 @@ type OCallHierarchyResult struct {
 	TotalIncoming int `json:"total_incoming,omitempty" jsonschema:"total number of incoming calls"`
 	// TotalOutgoing is the total number of outgoing calls.
 	TotalOutgoing int `json:"total_outgoing,omitempty" jsonschema:"total number of outgoing calls"`
+	// IncomingTree is the recursive expansion of the callers, set when
+	// max_depth > 1. Nodes are listed breadth-first.
+	IncomingTree []CallHierarchyNode `json:"incoming_tree,omitempty" jsonschema:"callers expanded recursively up to max_depth (flattened tree in breadth-first order; only when max_depth > 1)"`
+	// OutgoingTree is the recursive expansion of the callees, set when
+	// max_depth > 1. Nodes are listed breadth-first.
+	OutgoingTree []CallHierarchyNode `json:"outgoing_tree,omitempty" jsonschema:"callees expanded recursively up to max_depth (flattened tree in breadth-first order; only when max_depth > 1)"`
+	// Truncated reports that a tree stopped growing at max_nodes.
+	Truncated bool `json:"truncated,omitempty" jsonschema:"true if a tree was cut off at max_nodes"`
+	// NextCursor is the cursor of the next page, if any.
+	NextCursor string `json:"next_cursor,omitempty" jsonschema:"pass as cursor to fetch the next page; empty on the last page"`
 	// Summary is a human-readable summary.
 	Summary string `json:"summary" jsonschema:"call hierarchy summary"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
 }
 
 // CallHierarchyCall represents a call in the hierarchy.
@@ -191,6 +324,27 @@ type CallHierarchyCall struct {
 	CallRanges []CallRange `json:"call_ranges,omitempty" jsonschema:"locations where this call occurs"`
 }
 
+// CallHierarchyNode is one node of a recursively expanded call hierarchy.
+// The tree is flattened: each node names its parent by ID.
+type CallHierarchyNode struct {
+	// ID identifies this node within its direction (1-based).
+	ID int `json:"id" jsonschema:"node identifier, unique within one direction (1-based)"`
+	// ParentID is the ID of the parent node, or 0 if the parent is the root function.
+	ParentID int `json:"parent_id" jsonschema:"identifier of the parent node (0 = the root function)"`
+	// Depth is the distance from the root function (1 for direct calls).
+	Depth int `json:"depth" jsonschema:"distance from the root function (1 = direct call)"`
+	// Symbol is the caller (incoming) or callee (outgoing) of the parent node.
+	Symbol Symbol `json:"symbol" jsonschema:"the caller (incoming) or callee (outgoing) of the parent node"`
+	// CallRanges are the locations of the calls between this node and its parent.
+	CallRanges []CallRange `json:"call_ranges,omitempty" jsonschema:"locations of the calls between this node and its parent"`
+	// RepeatOf is the ID of the node where this function was already
+	// expanded; this node is not expanded again.
+	RepeatOf int `json:"repeat_of,omitempty" jsonschema:"ID of the earlier node for the same function, which is expanded there instead"`
+	// Cycle reports that this function is an ancestor of the node: the
+	// calls are recursive. RepeatOf is that ancestor, or 0 for the root.
+	Cycle bool `json:"cycle,omitempty" jsonschema:"true if this function already appears on the path from the root (recursion); repeat_of is that ancestor, 0 = the root"`
+}
+
 // CallRange represents a location where a call occurs.
 type CallRange struct {
 	// File is the file path.
@@ -199,4 +353,473 @@ type CallRange struct {
 	StartLine int `json:"start_line" jsonschema:"the start line number (1-indexed)"`
 	// EndLine is the end line number (1-indexed).
 	EndLine int `json:"end_line" jsonschema:"the end line number (1-indexed)"`
+	// Dispatch is "static" for a call of a concrete method itself and
+	// "interface" for one through an interface method it implements. It is
+	// only set on incoming calls of a concrete method with include_dynamic.
+	Dispatch string `json:"dispatch,omitempty" jsonschema:"static, or interface for a call through an interface method (only for incoming calls with include_dynamic)"`
+	// Interface is the interface of a call with dispatch "interface".
+	Interface string `json:"interface,omitempty" jsonschema:"the interface, for dispatch interface"`
+}
+
+// ICallPathParams is the input for go_call_path tool.
+type ICallPathParams struct {
+	// From locates the function the paths start at.
+	From SymbolLocator `json:"from" jsonschema:"semantic symbol locator of the calling function the paths start at"`
+	// To locates the function the paths end at.
+	To SymbolLocator `json:"to" jsonschema:"semantic symbol locator of the called function the paths end at"`
+	// Algorithm selects how the call graph is built: "static", "cha" or "vta".
+	Algorithm string `json:"algorithm,omitempty" jsonschema:"call graph algorithm: static (static calls only), cha (an interface call reaches every implementation), or vta (the default: an interface call reaches the implementations whose values can flow to it)"`
+	// MaxPaths is how many of the shortest paths to return.
+	MaxPaths int `json:"max_paths,omitempty" jsonschema:"number of shortest paths to return (default: 1, max: 10)"`
+}
+
+// OCallPathResult is the output for go_call_path tool.
+type OCallPathResult struct {
+	// From is the function the paths start at.
+	From Symbol `json:"from" jsonschema:"the function the paths start at"`
+	// To is the function the paths end at.
+	To Symbol `json:"to" jsonschema:"the function the paths end at"`
+	// Algorithm is the call graph algorithm that was used.
+	Algorithm string `json:"algorithm" jsonschema:"the call graph algorithm used"`
+	// Paths are the call paths found, shortest first. Empty if To is
+	// unreachable from From.
+	Paths []CallPath `json:"paths,omitempty" jsonschema:"call paths from From to To, shortest first"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"call path summary"`
+	// Warnings flags doubts about which symbols the locators resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
+}
+
+// CallPath is a chain of calls from one function to another.
+type CallPath struct {
+	// Hops are the calls along the path, in order. The first is made by
+	// the start function, the last calls the end function.
+	Hops []CallPathHop `json:"hops" jsonschema:"the calls along the path, in order"`
+}
+
+// CallPathHop is one call on a call path.
+type CallPathHop struct {
+	// Symbol is the called function.
+	Symbol Symbol `json:"symbol" jsonschema:"the called function"`
+	// CallSite is where the previous function on the path makes the call.
+	CallSite CallRange `json:"call_site" jsonschema:"location of the call in the calling function"`
+	// Dispatch is "static" for a direct call, "interface" for a call
+	// through an interface method, and "dynamic" for a call through a
+	// function value.
+	Dispatch string `json:"dispatch" jsonschema:"how the call is made: static, interface or dynamic"`
+}
+
+// ITypeHierarchyParams is the input for go_type_hierarchy tool.
+type ITypeHierarchyParams struct {
+	// Locator specifies the type to get the hierarchy for.
+	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator (symbol_name, context_file, package_name, parent_scope, kind, line_hint)"`
+	// Direction determines which direction to traverse: "supertypes", "subtypes", or "both".
+	Direction string `json:"direction,omitempty" jsonschema:"type hierarchy direction (supertypes/subtypes/both, default: both)"`
+	// MaxDepth limits how many levels of the hierarchy are expanded in each direction.
+	MaxDepth int `json:"max_depth,omitempty" jsonschema:"maximum number of levels to expand in each direction (default: 3, max: 10)"`
+}
+
+// OTypeHierarchyResult is the output for go_type_hierarchy tool.
+type OTypeHierarchyResult struct {
+	// Symbol is the type at the root of the hierarchy.
+	Symbol Symbol `json:"symbol" jsonschema:"the type at the root of the hierarchy"`
+	// Supertypes are the interfaces the type implements and the types it embeds.
+	Supertypes []TypeHierarchyNode `json:"supertypes,omitempty" jsonschema:"interfaces implemented and types embedded by the root type, expanded recursively (flattened tree in pre-order)"`
+	// Subtypes are the types that implement or embed the root type.
+	Subtypes []TypeHierarchyNode `json:"subtypes,omitempty" jsonschema:"types implementing or embedding the root type, expanded recursively (flattened tree in pre-order)"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"type hierarchy summary"`
+	// Warnings flags doubts about which symbol the locator resolved to.
+	Warnings []ResolutionWarning `json:"warnings,omitempty" jsonschema:"warnings about the symbol resolution (e.g. signature_snippet mismatch)"`
+}
+
+// TypeRelation describes how a node in the type hierarchy relates to its parent node.
+type TypeRelation string
+
+const (
+	TypeRelationImplements    TypeRelation = "implements"     // The parent implements this interface
+	TypeRelationImplementedBy TypeRelation = "implemented-by" // This type implements the parent interface
+	TypeRelationEmbeds        TypeRelation = "embeds"         // The parent embeds this type
+	TypeRelationEmbeddedBy    TypeRelation = "embedded-by"    // This type embeds the parent
+)
+
+// TypeHierarchyNode is a single type in the type hierarchy tree.
+//
+// The tree is flattened in depth-first pre-order: each node refers to its
+// parent by ID, and ParentID 0 denotes the root type.
+type TypeHierarchyNode struct {
+	// ID identifies this node within its direction (1-based).
+	ID int `json:"id" jsonschema:"node identifier, unique within one direction (1-based)"`
+	// ParentID is the ID of the parent node, or 0 if the parent is the root type.
+	ParentID int `json:"parent_id" jsonschema:"identifier of the parent node (0 = the root type)"`
+	// Depth is the distance from the root type (1 for direct relations).
+	Depth int `json:"depth" jsonschema:"distance from the root type (1 = direct relation)"`
+	// Relation is the edge from the parent node to this node.
+	Relation TypeRelation `json:"relation" jsonschema:"how this type relates to its parent (implements, implemented-by, embeds, embedded-by)"`
+	// Symbol is the type at this node.
+	Symbol Symbol `json:"symbol" jsonschema:"the type at this node"`
+	// Seen reports that this type was already expanded elsewhere in the tree
+	// (including recursion cycles), so its children are not repeated here.
+	Seen bool `json:"seen,omitempty" jsonschema:"true if this type already appears earlier in the tree and is not expanded again"`
+}
+
+// ICheckEditParams is the input for go_check_edit tool.
+type ICheckEditParams struct {
+	// FilePath is the absolute path of the Go file to edit. The file does not
+	// need to exist yet; a new file is checked as part of its directory's package.
+	FilePath string `json:"file_path" jsonschema:"absolute path of the Go file the proposed edit applies to"`
+	// NewContent is the complete proposed content of the file.
+	// Mutually exclusive with Edits.
+	NewContent string `json:"new_content,omitempty" jsonschema:"the complete proposed file content (mutually exclusive with edits)"`
+	// Edits is a set of line replacements applied to the current file content.
+	// Mutually exclusive with NewContent.
+	Edits []LineEdit `json:"edits,omitempty" jsonschema:"line replacements applied to the current file content (mutually exclusive with new_content)"`
+}
+
+// LineEdit replaces a range of lines in a file.
+//
+// Lines StartLine through EndLine (1-indexed, inclusive) are replaced by NewText.
+// If EndLine is StartLine-1, NewText is inserted before StartLine without
+// replacing anything.
+type LineEdit struct {
+	StartLine int `json:"start_line" jsonschema:"first line to replace (1-indexed)"`
+	EndLine   int `json:"end_line" jsonschema:"last line to replace (1-indexed, inclusive); use start_line-1 to insert before start_line"`
+	// NewText replaces the lines. A trailing newline is added if missing;
+	// an empty NewText deletes the lines.
+	NewText string `json:"new_text" jsonschema:"replacement text for the lines (empty deletes them)"`
+}
+
+// OCheckEditResult is the output for go_check_edit tool.
+type OCheckEditResult struct {
+	// FilePath is the file the edit was checked against.
+	FilePath string `json:"file_path" jsonschema:"the file the edit was checked against"`
+	// Packages are the packages that were type-checked and analyzed.
+	Packages []string `json:"packages,omitempty" jsonschema:"import paths of the packages that were checked"`
+	// Diagnostics are the type-check and analyzer findings with the edit applied.
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty" jsonschema:"type-check and analyzer diagnostics with the edit applied"`
+	// ErrorCount is the number of error-severity diagnostics.
+	ErrorCount int `json:"error_count" jsonschema:"number of error diagnostics"`
+	// WarningCount is the number of warning-severity diagnostics.
+	WarningCount int `json:"warning_count,omitempty" jsonschema:"number of warning diagnostics"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"edit check summary"`
+}
+
+// Diagnostic is a single compiler or analyzer finding.
+type Diagnostic struct {
+	File string `json:"file" jsonschema:"the file path"`
+	// Line and Column are 1-indexed.
+	Line   int `json:"line" jsonschema:"line number (1-indexed)"`
+	Column int `json:"column" jsonschema:"column number (1-indexed, in UTF-16 units)"`
+	// Severity is one of "error", "warning", "info", or "hint".
+	Severity string `json:"severity" jsonschema:"diagnostic severity (error, warning, info, hint)"`
+	// Source is the producer of the diagnostic, such as "compiler" or an analyzer name.
+	Source  string `json:"source,omitempty" jsonschema:"producer of the diagnostic (compiler or analyzer name)"`
+	Message string `json:"message" jsonschema:"the diagnostic message"`
+	// Fixes are the fixes suggested for the diagnostic. They are not applied.
+	Fixes []SuggestedFix `json:"fixes,omitempty" jsonschema:"fixes suggested for the diagnostic, as previews (not applied)"`
+}
+
+// SuggestedFix is a fix suggested for a diagnostic.
+type SuggestedFix struct {
+	Title string `json:"title" jsonschema:"what the fix does"`
+	// Diff is empty for a fix that is computed by running a command.
+	Diff string `json:"diff,omitempty" jsonschema:"unified diff of the fix (empty if the fix is computed by a command)"`
+}
+
+// IDiagnosticsParams is the input for go_diagnostics tool.
+//
+// The scope is FilePath if set, else PackagePath if set, else the whole
+// workspace.
+type IDiagnosticsParams struct {
+	// FilePath selects the diagnostics of one file, computed over the
+	// packages that contain it.
+	FilePath string `json:"file_path,omitempty" jsonschema:"absolute path of a Go file to diagnose"`
+	// PackagePath selects the diagnostics of one package and its tests.
+	PackagePath string `json:"package_path,omitempty" jsonschema:"import path of a package to diagnose, with its tests"`
+	// Cwd selects the view for PackagePath and workspace scopes.
+	Cwd string `json:"Cwd,omitempty" jsonschema:"a directory of the workspace to diagnose (default: every view)"`
+	// Analyzers selects the analyzers to run in addition to type checking.
+	// If empty, those enabled in the gopls settings run.
+	Analyzers []string `json:"analyzers,omitempty" jsonschema:"names of the analyzers to run, e.g. [\"printf\", \"shadow\"] (default: those enabled in the gopls settings); any gopls analyzer can be named, including those off by default"`
+	// TypeErrorsOnly skips the analyzers.
+	TypeErrorsOnly bool `json:"type_errors_only,omitempty" jsonschema:"report only parse and type errors, running no analyzer"`
+	// MinSeverity omits the diagnostics below it.
+	MinSeverity string `json:"min_severity,omitempty" jsonschema:"least severe diagnostics to report: error, warning, info or hint (default: info)"`
+}
+
+// ODiagnosticsResult is the output for go_diagnostics tool.
+type ODiagnosticsResult struct {
+	// Packages holds the diagnostics by package, in import path order.
+	// Packages without diagnostics are omitted.
+	Packages []PackageDiagnostics `json:"packages,omitempty" jsonschema:"diagnostics grouped by package"`
+	// PackageCount is the number of packages that were checked.
+	PackageCount int `json:"package_count" jsonschema:"number of packages checked"`
+	// Analyzers are the analyzers that ran.
+	Analyzers    []string `json:"analyzers,omitempty" jsonschema:"names of the analyzers that ran"`
+	ErrorCount   int      `json:"error_count" jsonschema:"number of error diagnostics"`
+	WarningCount int      `json:"warning_count,omitempty" jsonschema:"number of warning diagnostics"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"diagnostics summary"`
+}
+
+// PackageDiagnostics is the diagnostics of the files of one package.
+type PackageDiagnostics struct {
+	Package     string       `json:"package" jsonschema:"import path of the package"`
+	Diagnostics []Diagnostic `json:"diagnostics" jsonschema:"diagnostics of the package's files, by position"`
+}
+
+// ICodeActionsParams is the input for go_code_actions tool.
+//
+// The actions are those available at Locator if set, else on lines
+// StartLine through EndLine of FilePath.
+type ICodeActionsParams struct {
+	// Locator selects the identifier of a symbol, at its occurrence in the
+	// context file (a call site, for instance).
+	Locator *SymbolLocator `json:"locator,omitempty" jsonschema:"semantic symbol locator of the identifier to act on (mutually exclusive with file_path)"`
+	// FilePath, StartLine and EndLine select whole lines of a file.
+	FilePath  string `json:"file_path,omitempty" jsonschema:"absolute path of the Go file to act on (mutually exclusive with locator)"`
+	StartLine int    `json:"start_line,omitempty" jsonschema:"first line of the selection (1-indexed)"`
+	EndLine   int    `json:"end_line,omitempty" jsonschema:"last line of the selection (1-indexed, inclusive; default: start_line)"`
+	// Kinds restricts the actions to these kinds and their sub-kinds.
+	Kinds []string `json:"kinds,omitempty" jsonschema:"code action kinds to list, matching their sub-kinds too, e.g. [\"quickfix\", \"refactor.extract\"] (default: all)"`
+}
+
+// OCodeActionsResult is the output for go_code_actions tool.
+type OCodeActionsResult struct {
+	// Actions are the available actions, in the order gopls offers them.
+	Actions []CodeAction `json:"actions,omitempty" jsonschema:"the available code actions"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"code actions summary"`
+}
+
+// CodeAction is a code action available at a location.
+type CodeAction struct {
+	// ID identifies the action by its kind, location and title. It stays
+	// valid as long as the action is offered at that location.
+	ID    string `json:"id" jsonschema:"stable ID of the action (pass to go_dryrun_code_action)"`
+	Title string `json:"title" jsonschema:"what the action does"`
+	Kind  string `json:"kind" jsonschema:"code action kind, e.g. quickfix or refactor.extract.function"`
+	// Previewable is false for actions that run a command whose changes
+	// go_dryrun_code_action cannot compute.
+	Previewable bool `json:"previewable" jsonschema:"whether go_dryrun_code_action can preview the action"`
+	// Fixes are the messages of the diagnostics the action fixes.
+	Fixes []string `json:"fixes,omitempty" jsonschema:"messages of the diagnostics the action fixes"`
+}
+
+// IDryrunCodeActionParams is the input for go_dryrun_code_action tool.
+type IDryrunCodeActionParams struct {
+	// ID is an action ID returned by go_code_actions.
+	ID string `json:"id" jsonschema:"the ID of a code action, as returned by go_code_actions"`
+}
+
+// ODryrunCodeActionResult is the output for go_dryrun_code_action tool.
+type ODryrunCodeActionResult struct {
+	Title string `json:"title" jsonschema:"what the action does"`
+	Kind  string `json:"kind" jsonschema:"code action kind"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the changes the action would make (not applied)"`
+	// FilesChanged lists the files the action would modify or create.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the action would modify or create"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"code action preview summary"`
+}
+
+// IExtractParams is the input for go_dryrun_extract tool.
+//
+// The selection is Snippet if set, else lines StartLine through EndLine.
+type IExtractParams struct {
+	FilePath string `json:"file_path" jsonschema:"absolute path of the Go file to extract from"`
+	// Kind is one of "function", "method", "variable", "constant" or
+	// "to-new-file".
+	Kind      string `json:"kind" jsonschema:"what to extract the selection to: function, method, variable, constant or to-new-file"`
+	StartLine int    `json:"start_line,omitempty" jsonschema:"first line of the selection (1-indexed); with snippet, the line the snippet starts on"`
+	EndLine   int    `json:"end_line,omitempty" jsonschema:"last line of the selection (1-indexed, inclusive; default: start_line)"`
+	// Snippet selects the exact text to extract, such as an expression.
+	// It must occur once in the file, or once starting on StartLine.
+	Snippet string `json:"snippet,omitempty" jsonschema:"exact source text to extract, e.g. an expression for variable or constant; must be unique in the file unless start_line says where it starts"`
+}
+
+// OExtractResult is the output for go_dryrun_extract tool.
+type OExtractResult struct {
+	Kind string `json:"kind" jsonschema:"what the selection was extracted to"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the extraction (not applied)"`
+	// FilesChanged lists the files the extraction would modify or create.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the extraction would modify or create"`
+	// Parameters are the free variables of the selection that the extracted
+	// function or method takes as parameters.
+	Parameters []FreeVariable `json:"parameters,omitempty" jsonschema:"local variables declared outside the selection that became parameters (function and method)"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"extraction preview summary"`
+}
+
+// FreeVariable is a variable used in, but declared outside, a selection.
+type FreeVariable struct {
+	Name string `json:"name" jsonschema:"variable name"`
+	Type string `json:"type" jsonschema:"variable type"`
+}
+
+// IInlineParams is the input for go_dryrun_inline tool.
+type IInlineParams struct {
+	// Locator selects a call, by the name of the function at the call
+	// site, or a function declaration, to inline every call of it.
+	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator of the called function at a call site (line_hint on the call), or of the function declaration to inline every call"`
+}
+
+// OInlineResult is the output for go_dryrun_inline tool.
+type OInlineResult struct {
+	Callee string `json:"callee" jsonschema:"name of the inlined function"`
+	// AllCalls reports whether every call of the function was inlined,
+	// rather than a single call.
+	AllCalls bool `json:"all_calls" jsonschema:"whether every call of the function was inlined"`
+	// Caveats are what the inliner had to do to keep the behavior of the
+	// calls, such as binding arguments to variables to keep their
+	// evaluation order.
+	Caveats []string `json:"caveats,omitempty" jsonschema:"how the inliner preserved the behavior of the calls, e.g. arguments bound to variables to keep evaluation order"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the inlining (not applied)"`
+	// FilesChanged lists the files the inlining would modify.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the inlining would modify"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"inlining preview summary"`
+}
+
+// IChangeSignatureParams is the input for go_dryrun_change_signature tool.
+type IChangeSignatureParams struct {
+	// Locator selects the function or method, at its declaration or at a
+	// call.
+	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator of the function or method (its declaration or a call)"`
+	// Params is the new parameter list, in order. A parameter of the old
+	// signature that it does not mention is removed.
+	Params []SignatureParam `json:"params" jsonschema:"the new parameter list, in order: old parameters by old_index (leave one out to remove it), new ones by field and default"`
+}
+
+// SignatureParam is a parameter of a changed signature: a parameter of the
+// old signature, by OldIndex, or a new one, by Field and Default.
+type SignatureParam struct {
+	OldIndex *int `json:"old_index,omitempty" jsonschema:"0-based index of the parameter in the current signature"`
+	// Field declares a new parameter, such as "ctx context.Context".
+	Field string `json:"field,omitempty" jsonschema:"a new parameter as name and type, e.g. \"ctx context.Context\""`
+	// Default is the argument that existing calls pass for a new
+	// parameter. It is resolved in the file that declares the function,
+	// which is made to import the packages it names if need be.
+	Default string `json:"default,omitempty" jsonschema:"the argument existing calls pass for a new parameter, e.g. \"context.TODO()\"; resolved in the declaring file, whose missing imports are added"`
+}
+
+// OChangeSignatureResult is the output for go_dryrun_change_signature tool.
+type OChangeSignatureResult struct {
+	Function string `json:"function" jsonschema:"name of the changed function or method"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the declaration and its calls (not applied)"`
+	// FilesChanged lists the files the change would modify.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the change would modify"`
+	// Skipped are the calls left unchanged, which must be fixed by hand.
+	Skipped []SkippedCall `json:"skipped,omitempty" jsonschema:"calls and references that could not be rewritten safely; they must be fixed by hand"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"change signature preview summary"`
+}
+
+// SkippedCall is a call, or other reference, that a refactoring left
+// unchanged.
+type SkippedCall struct {
+	File string `json:"file" jsonschema:"the file path"`
+	Line int    `json:"line" jsonschema:"line number (1-indexed)"`
+	// Reason says why the call was not rewritten.
+	Reason string `json:"reason" jsonschema:"why the call was not rewritten"`
+}
+
+// IImplementInterfaceParams is the input for go_dryrun_implement_interface tool.
+type IImplementInterfaceParams struct {
+	// Interface selects the interface to implement.
+	Interface SymbolLocator `json:"interface" jsonschema:"semantic symbol locator of the interface (its declaration or a use)"`
+	// Concrete selects the type to add the missing methods to. Either
+	// Concrete or FakeFile is required.
+	Concrete *SymbolLocator `json:"concrete,omitempty" jsonschema:"semantic symbol locator of the concrete type to add stubs of the missing methods to"`
+	// FakeFile is the Go file to declare a fake of the interface in. It
+	// is created if it does not exist.
+	FakeFile string `json:"fake_file,omitempty" jsonschema:"absolute path of the Go file to generate a recording fake in (created if missing), instead of stubbing a concrete type"`
+	// FakeName is the name of the fake type; the default is "Fake" and
+	// the name of the interface.
+	FakeName string `json:"fake_name,omitempty" jsonschema:"name of the fake type (default: Fake<Interface>)"`
+}
+
+// OImplementInterfaceResult is the output for go_dryrun_implement_interface tool.
+type OImplementInterfaceResult struct {
+	Interface string `json:"interface" jsonschema:"the interface, qualified by its package name"`
+	// Type is the concrete type or the fake.
+	Type string `json:"type" jsonschema:"the concrete type given methods, or the generated fake"`
+	// Methods are the methods added to Type.
+	Methods []string `json:"methods" jsonschema:"names of the methods added"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the new methods or fake (not applied)"`
+	// FilesChanged lists the files the change would modify or create.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the change would modify or create"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"implement interface preview summary"`
+}
+
+// IAddTestParams is the input for go_dryrun_add_test tool.
+type IAddTestParams struct {
+	// Locator selects the function or method to test.
+	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator of the function or method to test"`
+	// TestCase is a case to add to the table of the existing test, such
+	// as {name: "empty", in: "", want: 0}.
+	TestCase string `json:"test_case,omitempty" jsonschema:"a table element to add to the function's existing table-driven test, e.g. {name: \"empty\", in: \"\", want: 0}; a skeleton case is added if omitted"`
+}
+
+// OAddTestResult is the output for go_dryrun_add_test tool.
+type OAddTestResult struct {
+	Function string `json:"function" jsonschema:"name of the function under test"`
+	Test     string `json:"test" jsonschema:"name of the test function"`
+	// TestFile is the file of the test, which may be created.
+	TestFile string `json:"test_file" jsonschema:"the _test.go file of the test"`
+	// AddedCase reports whether the test existed and a case was added to
+	// its table.
+	AddedCase bool `json:"added_case" jsonschema:"true if a case was added to an existing test rather than a new test created"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the test file (not applied)"`
+	// FilesChanged lists the files the change would modify or create.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the change would modify or create"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"add test preview summary"`
+}
+
+// IModifyTagsParams is the input for go_dryrun_modify_tags tool.
+type IModifyTagsParams struct {
+	// Locator selects the struct type.
+	Locator SymbolLocator `json:"locator" jsonschema:"semantic symbol locator of the struct type"`
+	// Fields restricts the change to the named fields.
+	Fields []string `json:"fields,omitempty" jsonschema:"names of the fields to modify (default: all fields)"`
+	// Add are the tag keys to add, such as "json".
+	Add []string `json:"add,omitempty" jsonschema:"tag keys to add, e.g. [\"json\", \"db\"]; values are the field names after transform"`
+	// Remove are the tag keys to remove.
+	Remove []string `json:"remove,omitempty" jsonschema:"tag keys to remove"`
+	// Transform converts field names into tag values.
+	Transform string `json:"transform,omitempty" jsonschema:"how field names become tag values: snakecase (default), camelcase, lispcase, pascalcase, titlecase or keep"`
+	// AddOptions are options to add, as tag=option.
+	AddOptions []string `json:"add_options,omitempty" jsonschema:"options to add, as tag=option, e.g. [\"json=omitempty\"]"`
+	// RemoveOptions are options to remove, as tag=option.
+	RemoveOptions []string `json:"remove_options,omitempty" jsonschema:"options to remove, as tag=option"`
+	// Overwrite replaces the values of existing tags when adding.
+	Overwrite bool `json:"overwrite,omitempty" jsonschema:"replace the values of existing tags when adding, e.g. to switch json names to camelcase"`
+	// Clear removes all tags, before any are added.
+	Clear bool `json:"clear,omitempty" jsonschema:"remove all tags before adding any"`
+	// ClearOptions removes all options, before any are added.
+	ClearOptions bool `json:"clear_options,omitempty" jsonschema:"remove all tag options before adding any"`
+	// SkipUnexported leaves the tags of unexported fields unchanged.
+	SkipUnexported bool `json:"skip_unexported,omitempty" jsonschema:"leave the tags of unexported fields unchanged"`
+}
+
+// OModifyTagsResult is the output for go_dryrun_modify_tags tool.
+type OModifyTagsResult struct {
+	Struct string `json:"struct" jsonschema:"name of the struct type"`
+	// Fields are the fields whose tags change.
+	Fields []string `json:"fields" jsonschema:"fields whose tags change"`
+	// Diff is the unified diff of the changes. Nothing is written to disk.
+	Diff string `json:"diff" jsonschema:"unified diff of the struct tags (not applied)"`
+	// FilesChanged lists the files the change would modify.
+	FilesChanged []string `json:"files_changed,omitempty" jsonschema:"files the change would modify"`
+	// Summary is a human-readable summary.
+	Summary string `json:"summary" jsonschema:"modify tags preview summary"`
 }
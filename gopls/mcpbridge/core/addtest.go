@@ -0,0 +1,60 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_dryrun_add_test =====
+// Origin: gopls/internal/golang/addtest.go AddTestForFunc(), via
+// golang.LLMAddTest
+
+func handleGoDryrunAddTest(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IAddTestParams) (*mcp.CallToolResult, *api.OAddTestResult, error) {
+	locator, dir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fh, rng, err := locatorRange(ctx, snapshot, locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, result, err := golang.LLMAddTest(ctx, snapshot, fh, rng, input.TestCase)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Diff, err = toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.FilesChanged = changedFiles(changes)
+	result.Summary = formatAddTest(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+func formatAddTest(result *api.OAddTestResult) string {
+	var b strings.Builder
+
+	if result.AddedCase {
+		fmt.Fprintf(&b, "=== DRY RUN: Add Case to %s Preview ===\n", result.Test)
+		fmt.Fprintf(&b, "%s already exists in %s; the case is added to its table\n", result.Test, result.TestFile)
+	} else {
+		fmt.Fprintf(&b, "=== DRY RUN: Add %s Preview ===\n", result.Test)
+		fmt.Fprintf(&b, "New table-driven test of %s in %s; fill in the test cases\n", result.Function, result.TestFile)
+	}
+	b.WriteString("NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&b, "%s\n", result.Diff)
+
+	return b.String()
+}
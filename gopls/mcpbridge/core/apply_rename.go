@@ -0,0 +1,337 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_apply_rename =====
+// Origin: gopls/internal/golang/rename.go Rename()
+//
+// The rename is computed exactly like go_dryrun_rename_symbol, then written to
+// disk only if every touched file still has the content the changes were
+// computed from. The session is notified of the writes immediately so the
+// next query does not have to wait for the file watcher.
+
+func handleGoApplyRename(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IApplyRenameParams) (*mcp.CallToolResult, *api.OApplyRenameResult, error) {
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", viewDir, err)
+	}
+	defer release()
+
+	changes, warning, err := h.renameChanges(ctx, snapshot, input.Locator, input.NewName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute rename: %w", err)
+	}
+
+	// Writing a rename of what may be the wrong symbol is not recoverable
+	// the way a bad preview is, so a snippet mismatch blocks the apply.
+	if warning != nil {
+		result := &api.OApplyRenameResult{Warnings: resolutionWarnings(warning)}
+		result.Summary = formatResolutionWarnings(result.Warnings) +
+			fmt.Sprintf("Rename %q to %q NOT applied: the resolved symbol does not match signature_snippet. Fix the locator and retry. No files were modified.\n",
+				input.Locator.SymbolName, input.NewName)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
+	plan, err := planDocumentChanges(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &api.OApplyRenameResult{}
+	result.Conflicts = plan.conflicts(input.ExpectedHashes)
+	if len(result.Conflicts) > 0 {
+		result.Summary = formatApplyRename(input, result)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
+	modifications, written, err := plan.apply()
+	result.FilesChanged = written
+	if len(modifications) > 0 {
+		// Notify the session even after a partial failure, so that its view
+		// of the files that were written stays accurate.
+		if _, nerr := h.session.DidModifyFiles(ctx, modifications); nerr != nil && err == nil {
+			err = fmt.Errorf("rename written but failed to notify session: %w", nerr)
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to apply rename (files already written: %s): %w", strings.Join(written, ", "), err)
+	}
+
+	result.Applied = true
+	result.Summary = formatApplyRename(input, result)
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// plannedChange is a single document change with its new content resolved
+// against the snapshot it was computed from.
+type plannedChange struct {
+	change protocol.DocumentChange
+	// For text edits: the path, the hash of the content the edits were
+	// computed from, and the resulting content.
+	path    string
+	hash    file.Hash
+	content []byte
+}
+
+type changePlan []plannedChange
+
+// planDocumentChanges resolves the text edits in changes against snapshot.
+func planDocumentChanges(ctx context.Context, snapshot *cache.Snapshot, changes []protocol.DocumentChange) (changePlan, error) {
+	var plan changePlan
+	for _, change := range changes {
+		pc := plannedChange{change: change}
+		if edit := change.TextDocumentEdit; edit != nil {
+			fh, err := snapshot.ReadFile(ctx, edit.TextDocument.URI)
+			if err != nil {
+				return nil, err
+			}
+			content, err := fh.Content()
+			if err != nil {
+				return nil, err
+			}
+			newContent, _, err := protocol.ApplyEdits(protocol.NewMapper(fh.URI(), content), protocol.AsTextEdits(edit.Edits))
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply edits to %s: %w", fh.URI().Path(), err)
+			}
+			pc.path = fh.URI().Path()
+			pc.hash = fh.Identity().Hash
+			pc.content = newContent
+		}
+		plan = append(plan, pc)
+	}
+	return plan, nil
+}
+
+// conflicts reports every planned change that can no longer be applied
+// safely: text edits whose file changed on disk (or since the caller's
+// preview, per expected), and creations or moves onto existing paths.
+// A non-empty expected pins the set of edited files too: a file edited
+// without an expected hash, or an expected file that is no longer edited,
+// means the rename differs from the preview.
+func (plan changePlan) conflicts(expected map[string]string) []api.RenameConflict {
+	pinned := make(map[string]string, len(expected))
+	for path, hash := range expected {
+		pinned[filepath.Clean(path)] = hash
+	}
+	planned := make(map[string]bool)
+
+	var conflicts []api.RenameConflict
+	for _, pc := range plan {
+		switch {
+		case pc.change.TextDocumentEdit != nil:
+			planned[pc.path] = true
+			want := pc.hash.String()
+			exp, ok := pinned[pc.path]
+			if !ok && len(pinned) > 0 {
+				conflicts = append(conflicts, api.RenameConflict{
+					File:       pc.path,
+					Reason:     "file is edited by the rename but was not in the preview",
+					ActualHash: want,
+				})
+				continue
+			}
+			if ok && !strings.EqualFold(exp, want) {
+				conflicts = append(conflicts, api.RenameConflict{
+					File:         pc.path,
+					Reason:       "file changed since the preview",
+					ExpectedHash: exp,
+					ActualHash:   want,
+				})
+				continue
+			}
+			data, err := os.ReadFile(pc.path)
+			if err != nil {
+				conflicts = append(conflicts, api.RenameConflict{
+					File:         pc.path,
+					Reason:       fmt.Sprintf("file cannot be read: %v", err),
+					ExpectedHash: want,
+				})
+				continue
+			}
+			if got := file.HashOf(data).String(); got != want {
+				conflicts = append(conflicts, api.RenameConflict{
+					File:         pc.path,
+					Reason:       "file on disk differs from the content the rename was computed from",
+					ExpectedHash: want,
+					ActualHash:   got,
+				})
+			}
+		case pc.change.RenameFile != nil:
+			if dst := pc.change.RenameFile.NewURI.Path(); pathExists(dst) {
+				conflicts = append(conflicts, api.RenameConflict{
+					File:   dst,
+					Reason: "move destination already exists",
+				})
+			}
+		case pc.change.CreateFile != nil:
+			if dst := pc.change.CreateFile.URI.Path(); pathExists(dst) {
+				conflicts = append(conflicts, api.RenameConflict{
+					File:   dst,
+					Reason: "file to create already exists",
+				})
+			}
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(pinned)) {
+		if !planned[path] {
+			conflicts = append(conflicts, api.RenameConflict{
+				File:         path,
+				Reason:       "file was in the preview but is no longer edited by the rename",
+				ExpectedHash: pinned[path],
+			})
+		}
+	}
+	return conflicts
+}
+
+// apply performs the planned changes in order. It returns the on-disk
+// modifications to report to the session and the paths that were touched,
+// including those written before an error.
+func (plan changePlan) apply() ([]file.Modification, []string, error) {
+	var (
+		mods    []file.Modification
+		written []string
+	)
+	for _, pc := range plan {
+		switch c := pc.change; {
+		case c.TextDocumentEdit != nil:
+			mode := fs.FileMode(0644)
+			if info, err := os.Stat(pc.path); err == nil {
+				mode = info.Mode().Perm()
+			}
+			if err := os.WriteFile(pc.path, pc.content, mode); err != nil {
+				return mods, written, err
+			}
+			mods = append(mods, diskModification(c.TextDocumentEdit.TextDocument.URI, file.Change))
+			written = append(written, pc.path)
+
+		case c.CreateFile != nil:
+			path := c.CreateFile.URI.Path()
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return mods, written, err
+			}
+			if err := os.WriteFile(path, nil, 0644); err != nil {
+				return mods, written, err
+			}
+			mods = append(mods, diskModification(c.CreateFile.URI, file.Create))
+			written = append(written, path)
+
+		case c.DeleteFile != nil:
+			path := c.DeleteFile.URI.Path()
+			files := filesUnder(path)
+			remove := os.Remove
+			if c.DeleteFile.Options != nil && c.DeleteFile.Options.Recursive {
+				remove = os.RemoveAll
+			}
+			if err := remove(path); err != nil {
+				return mods, written, err
+			}
+			for _, f := range files {
+				mods = append(mods, diskModification(protocol.URIFromPath(f), file.Delete))
+			}
+			written = append(written, path)
+
+		case c.RenameFile != nil:
+			src, dst := c.RenameFile.OldURI.Path(), c.RenameFile.NewURI.Path()
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return mods, written, err
+			}
+			files := filesUnder(src)
+			if err := os.Rename(src, dst); err != nil {
+				return mods, written, err
+			}
+			// A moved directory is reported file by file, like the watcher would.
+			for _, f := range files {
+				rel, _ := filepath.Rel(src, f)
+				mods = append(mods,
+					diskModification(protocol.URIFromPath(f), file.Delete),
+					diskModification(protocol.URIFromPath(filepath.Join(dst, rel)), file.Create))
+			}
+			written = append(written, src+" -> "+dst)
+		}
+	}
+	return mods, written, nil
+}
+
+// diskModification describes an on-disk change, as the file watcher would report it.
+func diskModification(uri protocol.DocumentURI, action file.Action) file.Modification {
+	return file.Modification{URI: uri, Action: action, OnDisk: true}
+}
+
+// filesUnder returns path itself if it is a file, or every file beneath it
+// if it is a directory.
+func filesUnder(path string) []string {
+	var files []string
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// fileHashes returns the content hash of each file in changes, as seen by snapshot.
+func fileHashes(ctx context.Context, snapshot *cache.Snapshot, changes []api.RenameChange) map[string]string {
+	hashes := make(map[string]string)
+	for _, c := range changes {
+		if _, ok := hashes[c.File]; ok {
+			continue
+		}
+		fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(c.File))
+		if err != nil {
+			continue
+		}
+		hashes[c.File] = fh.Identity().Hash.String()
+	}
+	return hashes
+}
+
+func formatApplyRename(input api.IApplyRenameParams, result *api.OApplyRenameResult) string {
+	var b strings.Builder
+
+	if !result.Applied {
+		fmt.Fprintf(&b, "Rename %q to %q NOT applied: %d file(s) are stale. No files were modified.\n\n",
+			input.Locator.SymbolName, input.NewName, len(result.Conflicts))
+		b.WriteString("Conflicts:\n")
+		for _, c := range result.Conflicts {
+			fmt.Fprintf(&b, "  %s: %s\n", c.File, c.Reason)
+		}
+		b.WriteString("\nRe-read the files and retry (re-run go_dryrun_rename_symbol first if you pinned expected_hashes).\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "Renamed %q to %q in %d file(s):\n", input.Locator.SymbolName, input.NewName, len(result.FilesChanged))
+	files := slices.Clone(result.FilesChanged)
+	slices.Sort(files)
+	for _, f := range files {
+		fmt.Fprintf(&b, "  %s\n", f)
+	}
+	return b.String()
+}
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// Recursive expansion for go_get_call_hierarchy.
+// Origin: gopls/internal/golang/call_hierarchy.go IncomingCalls(), OutgoingCalls()
+
+const (
+	// maxCallHierarchyDepth caps max_depth to keep responses bounded.
+	maxCallHierarchyDepth = 10
+	// defaultCallHierarchyNodes is the per-direction node budget when max_nodes is unset.
+	defaultCallHierarchyNodes = 100
+	// maxCallHierarchyNodes caps max_nodes.
+	maxCallHierarchyNodes = 500
+)
+
+// callHierarchyWalker expands call hierarchy items in one direction.
+type callHierarchyWalker struct {
+	ctx            context.Context
+	snapshot       *cache.Snapshot
+	maxDepth       int
+	maxNodes       int
+	includeDynamic bool // tag the calls of concrete methods as static or through interfaces
+}
+
+// callEdge is a caller or callee of a function, with the calls in between.
+type callEdge struct {
+	item  protocol.CallHierarchyItem
+	calls []api.CallRange
+}
+
+// calls returns the direct callers (incoming=true) or callees of item.
+func (w *callHierarchyWalker) calls(item protocol.CallHierarchyItem, incoming bool) []callEdge {
+	fh, err := w.snapshot.ReadFile(w.ctx, item.URI)
+	if err != nil {
+		return nil
+	}
+	pos := item.SelectionRange.Start
+
+	var edges []callEdge
+	if incoming {
+		calls, err := golang.IncomingCalls(w.ctx, w.snapshot, fh, protocol.Range{Start: pos, End: pos})
+		if err != nil {
+			return nil
+		}
+		tagged := w.includeDynamic && concreteMethodAt(w.ctx, w.snapshot, protocol.Location{URI: item.URI, Range: item.SelectionRange})
+		for _, call := range calls {
+			ranges := buildCallRanges(call.From.URI.Path(), call.FromRanges)
+			if tagged {
+				ranges = dispatchCallRanges(w.ctx, w.snapshot, call.From.URI, call.FromRanges)
+			}
+			edges = append(edges, callEdge{call.From, ranges})
+		}
+	} else {
+		calls, err := golang.OutgoingCalls(w.ctx, w.snapshot, fh, pos)
+		if err != nil {
+			return nil
+		}
+		for _, call := range calls {
+			edges = append(edges, callEdge{call.To, buildCallRanges(item.URI.Path(), call.FromRanges)})
+		}
+	}
+	return edges
+}
+
+// direct converts the direct calls of a function, as returned by calls,
+// into the one-level form of the result.
+func (w *callHierarchyWalker) direct(edges []callEdge) []api.CallHierarchyCall {
+	var calls []api.CallHierarchyCall
+	for _, edge := range edges {
+		calls = append(calls, api.CallHierarchyCall{
+			From:       w.symbol(edge.item),
+			CallRanges: edge.calls,
+		})
+	}
+	return calls
+}
+
+// walk expands the callers (incoming=true) or callees of root breadth-first,
+// up to maxDepth levels and maxNodes nodes, and reports whether it stopped
+// at maxNodes. rootEdges are the direct calls of root, as returned by
+// calls. A function is expanded only at its first (shallowest) occurrence;
+// later ones refer back to it, and are marked as cycles if they recurse
+// into one of their own ancestors.
+func (w *callHierarchyWalker) walk(root protocol.CallHierarchyItem, rootEdges []callEdge, incoming bool) ([]api.CallHierarchyNode, bool) {
+	var (
+		nodes []api.CallHierarchyNode
+		// items holds the item of each node, by ID; 0 is the root.
+		items = []protocol.CallHierarchyItem{root}
+		// expanded maps each function to the ID of the node where it is expanded.
+		expanded = map[string]int{callItemKey(root): 0}
+		queue    = []int{0}
+	)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		depth := 0
+		if id > 0 {
+			depth = nodes[id-1].Depth
+		}
+		if depth >= w.maxDepth {
+			continue
+		}
+		edges := rootEdges
+		if id > 0 {
+			edges = w.calls(items[id], incoming)
+		}
+		for _, edge := range edges {
+			if len(nodes) >= w.maxNodes {
+				return nodes, true
+			}
+			node := api.CallHierarchyNode{
+				ID:         len(nodes) + 1,
+				ParentID:   id,
+				Depth:      depth + 1,
+				Symbol:     w.symbol(edge.item),
+				CallRanges: edge.calls,
+			}
+			key := callItemKey(edge.item)
+			if first, ok := expanded[key]; ok {
+				node.RepeatOf = first
+				node.Cycle = isCallAncestor(nodes, first, id)
+			} else if expandable(edge.item) {
+				expanded[key] = node.ID
+				queue = append(queue, node.ID)
+			}
+			nodes = append(nodes, node)
+			items = append(items, edge.item)
+		}
+	}
+	return nodes, false
+}
+
+// isCallAncestor reports whether the node ancestor is id or one of its
+// ancestors; 0 is the root, the ancestor of all nodes.
+func isCallAncestor(nodes []api.CallHierarchyNode, ancestor, id int) bool {
+	for {
+		if id == ancestor {
+			return true
+		}
+		if id == 0 {
+			return false
+		}
+		id = nodes[id-1].ParentID
+	}
+}
+
+// expandable reports whether item is a function whose calls can be
+// followed further, as opposed to e.g. a package-level var initializer.
+func expandable(item protocol.CallHierarchyItem) bool {
+	return item.Kind == protocol.Function || item.Kind == protocol.Method
+}
+
+// symbol converts a call hierarchy item into a rich api.Symbol.
+func (w *callHierarchyWalker) symbol(item protocol.CallHierarchyItem) api.Symbol {
+	return buildRichSymbol(w.ctx, w.snapshot, item.Name, item.Kind, item.URI, item.Range, pkgPathForFile(w.ctx, w.snapshot, item.URI))
+}
+
+// callItemKey identifies a call hierarchy item by its declaring position.
+func callItemKey(item protocol.CallHierarchyItem) string {
+	return fmt.Sprintf("%s:%d:%d", item.URI, item.SelectionRange.Start.Line, item.SelectionRange.Start.Character)
+}
+
+// formatCallHierarchyTree formats one direction of a recursive call hierarchy
+// as an indented tree, each node under its parent.
+func formatCallHierarchyTree(title string, nodes []api.CallHierarchyNode) string {
+	if len(nodes) == 0 {
+		return title + ": None\n"
+	}
+
+	children := make(map[int][]api.CallHierarchyNode)
+	for _, node := range nodes {
+		children[node.ParentID] = append(children[node.ParentID], node)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%d nodes):\n", title, len(nodes))
+	var visit func(parent int)
+	visit = func(parent int) {
+		for _, node := range children[parent] {
+			indent := strings.Repeat("  ", node.Depth)
+			fmt.Fprintf(&b, "%s- #%d %s", indent, node.ID, node.Symbol.Name)
+			if node.Symbol.PackagePath != "" {
+				fmt.Fprintf(&b, " in %s", node.Symbol.PackagePath)
+			}
+			fmt.Fprintf(&b, " at %s:%d", node.Symbol.FilePath, node.Symbol.Line)
+			if len(node.CallRanges) > 1 {
+				fmt.Fprintf(&b, " (called %d times)", len(node.CallRanges))
+			}
+			b.WriteString(dispatchNote(node.CallRanges))
+			switch {
+			case node.Cycle && node.RepeatOf == 0:
+				b.WriteString(" (recursion into the root)")
+			case node.Cycle:
+				fmt.Fprintf(&b, " (recursion into #%d)", node.RepeatOf)
+			case node.RepeatOf != 0:
+				fmt.Fprintf(&b, " (already shown as #%d)", node.RepeatOf)
+			}
+			b.WriteString("\n")
+			visit(node.ID)
+		}
+	}
+	visit(0)
+	return b.String()
+}
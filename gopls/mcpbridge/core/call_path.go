@@ -0,0 +1,470 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/cha"
+	"golang.org/x/tools/go/callgraph/static"
+	"golang.org/x/tools/go/callgraph/vta"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_call_path =====
+// Origin: golang.org/x/tools/go/callgraph/{static,cha,vta}
+//
+// gopls type-checks each package on its own, so its packages cannot be
+// combined into one SSA program. go_call_path therefore loads the
+// workspace packages afresh with go/packages, with the snapshot's unsaved
+// overlays, builds their SSA form and a whole-program call graph, and
+// searches it for the shortest paths. The graph is kept until the view's
+// snapshot changes, one for each algorithm.
+
+const (
+	// maxCallPaths caps max_paths.
+	maxCallPaths = 10
+	// maxCallPathExpansions bounds the search for paths, which can grow
+	// exponentially in graphs with many equally short paths.
+	maxCallPathExpansions = 100000
+)
+
+func handleGoCallPath(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.ICallPathParams) (*mcp.CallToolResult, *api.OCallPathResult, error) {
+	algorithm := input.Algorithm
+	if algorithm == "" {
+		algorithm = "vta"
+	}
+	if algorithm != "static" && algorithm != "cha" && algorithm != "vta" {
+		return nil, nil, fmt.Errorf("unknown algorithm %q: want static, cha or vta", input.Algorithm)
+	}
+	maxPaths := max(input.MaxPaths, 1)
+	maxPaths = min(maxPaths, maxCallPaths)
+
+	from, viewDir, err := h.resolveLocator(ctx, input.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, toViewDir, err := h.resolveLocator(ctx, input.To)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The call graph covers the packages of one view.
+	view, err := h.getView(viewDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if toView, err := h.getView(toViewDir); err != nil {
+		return nil, nil, err
+	} else if toView != view {
+		return nil, nil, fmt.Errorf("'%s' and '%s' are in different workspace views (%s and %s); a call path can only be found within one view", from.SymbolName, to.SymbolName, view.Root().Path(), toView.Root().Path())
+	}
+	snapshot, release, err := view.Snapshot()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fromFunc, fromWarning, err := resolveFunc(ctx, snapshot, from)
+	if err != nil {
+		return nil, nil, err
+	}
+	toFunc, toWarning, err := resolveFunc(ctx, snapshot, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph, fset, err := h.callGraph(ctx, snapshot, algorithm)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build call graph: %w", err)
+	}
+
+	sources := graphNodesFor(graph, fromFunc)
+	if len(sources) == 0 {
+		return nil, nil, fmt.Errorf("'%s' has no body in the workspace packages, so its calls are unknown", from.SymbolName)
+	}
+	targets := graphNodesFor(graph, toFunc)
+
+	result := &api.OCallPathResult{
+		From:      funcSymbol(ctx, snapshot, fset, sources[0].Func),
+		Algorithm: algorithm,
+	}
+	if len(targets) > 0 {
+		result.To = funcSymbol(ctx, snapshot, fset, targets[0].Func)
+	} else {
+		// Never called from workspace code, so there is nothing to build on.
+		result.To = api.Symbol{Name: toFunc.Name(), PackagePath: toFunc.Pkg().Path()}
+	}
+	for _, w := range []*api.ResolutionWarning{fromWarning, toWarning} {
+		result.Warnings = append(result.Warnings, resolutionWarnings(w)...)
+	}
+
+	for _, path := range shortestCallPaths(sources, targets, maxPaths) {
+		var hops []api.CallPathHop
+		for _, edge := range path {
+			posn := fset.Position(edge.Pos())
+			hops = append(hops, api.CallPathHop{
+				Symbol:   funcSymbol(ctx, snapshot, fset, edge.Callee.Func),
+				CallSite: api.CallRange{File: posn.Filename, StartLine: posn.Line, EndLine: posn.Line},
+				Dispatch: callDispatch(edge),
+			})
+		}
+		result.Paths = append(result.Paths, api.CallPath{Hops: hops})
+	}
+
+	var summary strings.Builder
+	summary.WriteString(formatResolutionWarnings(result.Warnings))
+	if len(result.Paths) == 0 {
+		fmt.Fprintf(&summary, "No call path from %s to %s (algorithm: %s).\n", result.From.Name, result.To.Name, algorithm)
+	} else {
+		fmt.Fprintf(&summary, "Found %d call path(s) from %s to %s (algorithm: %s):\n", len(result.Paths), result.From.Name, result.To.Name, algorithm)
+		for i, path := range result.Paths {
+			fmt.Fprintf(&summary, "\nPath %d (%d calls):\n", i+1, len(path.Hops))
+			fmt.Fprintf(&summary, "  %s at %s:%d\n", result.From.Name, result.From.FilePath, result.From.Line)
+			for _, hop := range path.Hops {
+				fmt.Fprintf(&summary, "  -> %s at %s:%d (called at %s:%d", hop.Symbol.Name, hop.Symbol.FilePath, hop.Symbol.Line, hop.CallSite.File, hop.CallSite.StartLine)
+				if hop.Dispatch != "static" {
+					fmt.Fprintf(&summary, ", %s call", hop.Dispatch)
+				}
+				summary.WriteString(")\n")
+			}
+		}
+	}
+	result.Summary = summary.String()
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// resolveFunc resolves locator to the function or method it denotes.
+func resolveFunc(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator) (*types.Func, *api.ResolutionWarning, error) {
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(locator.ContextFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	nodeResult, err := golang.ResolveNode(ctx, snapshot, fh, locator)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve symbol '%s': %w", locator.SymbolName, err)
+	}
+	fn, ok := nodeResult.Object.(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return nil, nil, fmt.Errorf("'%s' is not a function or method", locator.SymbolName)
+	}
+	if recv := fn.Signature().Recv(); recv != nil && types.IsInterface(recv.Type()) {
+		return nil, nil, fmt.Errorf("'%s' is an interface method; locate one of its implementations instead", locator.SymbolName)
+	}
+	return fn, nodeResult.Warning, nil
+}
+
+// callGraphKey identifies the call graphs kept by Handler.callGraph.
+type callGraphKey struct {
+	view      string // view ID
+	algorithm string
+}
+
+// cachedCallGraph is a call graph built for the snapshot with sequence ID seq.
+type cachedCallGraph struct {
+	seq   uint64
+	graph *callgraph.Graph
+	fset  *token.FileSet
+}
+
+// callGraph returns the call graph of snapshot's view with the given
+// algorithm, building it unless it was built for the same snapshot before.
+// Builds are serialized, so that concurrent calls share one.
+func (h *Handler) callGraph(ctx context.Context, snapshot *cache.Snapshot, algorithm string) (*callgraph.Graph, *token.FileSet, error) {
+	h.callGraphMu.Lock()
+	defer h.callGraphMu.Unlock()
+	key := callGraphKey{snapshot.View().ID(), algorithm}
+	if cached, ok := h.callGraphs[key]; ok && cached.seq == snapshot.SequenceID() {
+		return cached.graph, cached.fset, nil
+	}
+	graph, fset, err := buildCallGraph(ctx, snapshot, algorithm)
+	if err != nil {
+		return nil, nil, err
+	}
+	h.callGraphs[key] = cachedCallGraph{snapshot.SequenceID(), graph, fset}
+	return graph, fset, nil
+}
+
+// buildCallGraph loads the workspace packages of snapshot's view and builds
+// their call graph with the given algorithm. Dependencies are loaded from
+// export data, so calls are only followed through workspace code.
+func buildCallGraph(ctx context.Context, snapshot *cache.Snapshot, algorithm string) (*callgraph.Graph, *token.FileSet, error) {
+	mps, err := snapshot.WorkspaceMetadata(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var patterns []string
+	for _, mp := range mps {
+		if mp.ForTest != "" || metadata.IsCommandLineArguments(mp.ID) {
+			continue
+		}
+		if !slices.Contains(patterns, string(mp.PkgPath)) {
+			patterns = append(patterns, string(mp.PkgPath))
+		}
+	}
+	if len(patterns) == 0 {
+		return nil, nil, fmt.Errorf("no workspace packages in %s", snapshot.View().Root().Path())
+	}
+
+	overlay := make(map[string][]byte)
+	for _, o := range snapshot.Overlays() {
+		if content, err := o.Content(); err == nil {
+			overlay[o.URI().Path()] = content
+		}
+	}
+	cfg := &packages.Config{
+		Context:    ctx,
+		Mode:       packages.LoadSyntax,
+		Dir:        snapshot.View().Root().Path(),
+		Env:        snapshot.View().Env(),
+		BuildFlags: snapshot.Options().BuildFlags,
+		Overlay:    overlay,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, nil, fmt.Errorf("no packages loaded from %s", cfg.Dir)
+	}
+
+	// The workspace packages share their types with each other but not
+	// with their dependencies, which come from export data; create them
+	// first, with syntax, and then every other package they import.
+	fset := pkgs[0].Fset
+	prog := ssa.NewProgram(fset, ssa.InstantiateGenerics)
+	created := make(map[*types.Package]bool)
+	var initial []*types.Package
+	for _, pkg := range pkgs {
+		if pkg.Types != nil && !pkg.IllTyped {
+			prog.CreatePackage(pkg.Types, pkg.Syntax, pkg.TypesInfo, true)
+			created[pkg.Types] = true
+			initial = append(initial, pkg.Types)
+		}
+	}
+	var createImports func(*types.Package)
+	createImports = func(pkg *types.Package) {
+		for _, imp := range pkg.Imports() {
+			if !created[imp] {
+				created[imp] = true
+				prog.CreatePackage(imp, nil, nil, true)
+				createImports(imp)
+			}
+		}
+	}
+	for _, pkg := range initial {
+		createImports(pkg)
+	}
+	prog.Build()
+
+	var graph *callgraph.Graph
+	switch algorithm {
+	case "static":
+		graph = static.CallGraph(prog)
+	case "cha":
+		graph = cha.CallGraph(prog)
+	case "vta":
+		graph = vta.CallGraph(ssautil.AllFunctions(prog), cha.CallGraph(prog))
+	}
+	graph.DeleteSyntheticNodes()
+	return graph, fset, nil
+}
+
+// graphNodesFor returns the call graph nodes of fn, one per instantiation
+// if fn is generic, ordered by position.
+func graphNodesFor(graph *callgraph.Graph, fn *types.Func) []*callgraph.Node {
+	key := funcKey(fn)
+	var nodes []*callgraph.Node
+	for f, node := range graph.Nodes {
+		if f == nil {
+			continue
+		}
+		if f.Origin() != nil {
+			f = f.Origin()
+		}
+		if obj, ok := f.Object().(*types.Func); ok && funcKey(obj) == key {
+			nodes = append(nodes, node)
+		}
+	}
+	slices.SortFunc(nodes, func(a, b *callgraph.Node) int { return strings.Compare(a.Func.String(), b.Func.String()) })
+	return nodes
+}
+
+// funcKey identifies a function or method independently of the type
+// checker run that produced it.
+func funcKey(fn *types.Func) string {
+	name := fn.Name()
+	if recv := fn.Signature().Recv(); recv != nil {
+		t := recv.Type()
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if named, ok := types.Unalias(t).(*types.Named); ok {
+			name = named.Obj().Name() + "." + name
+		}
+	}
+	return fn.Pkg().Path() + "." + name
+}
+
+// shortestCallPaths returns up to k shortest call paths, without repeated
+// functions, from one of sources to one of targets. Calls between the same
+// two functions at several sites count as one edge, the first call site.
+//
+// The search is best-first on the path length plus the distance that is
+// left to the nearest target, so paths are found shortest first.
+func shortestCallPaths(sources, targets []*callgraph.Node, k int) [][]*callgraph.Edge {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	// dist is the length of the shortest path from each node to a target.
+	dist := make(map[*callgraph.Node]int)
+	queue := slices.Clone(targets)
+	for _, t := range targets {
+		dist[t] = 0
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for _, e := range n.In {
+			if _, ok := dist[e.Caller]; !ok {
+				dist[e.Caller] = dist[n] + 1
+				queue = append(queue, e.Caller)
+			}
+		}
+	}
+
+	isTarget := make(map[*callgraph.Node]bool)
+	for _, t := range targets {
+		isTarget[t] = true
+	}
+
+	// Partial paths are kept in buckets by their estimated total length,
+	// which never decreases as they are extended.
+	buckets := make(map[int][][]*callgraph.Edge)
+	push := func(path []*callgraph.Edge) {
+		last := path[len(path)-1].Callee
+		f := len(path) + dist[last]
+		buckets[f] = append(buckets[f], path)
+	}
+	for _, source := range sources {
+		for _, e := range calleeEdges(source) {
+			if _, ok := dist[e.Callee]; ok {
+				push([]*callgraph.Edge{e})
+			}
+		}
+	}
+
+	var paths [][]*callgraph.Edge
+	expansions := 0
+	maxLen := len(dist) + 1
+	for f := 1; f <= maxLen && len(paths) < k; f++ {
+		for len(buckets[f]) > 0 && len(paths) < k && expansions < maxCallPathExpansions {
+			path := buckets[f][0]
+			buckets[f] = buckets[f][1:]
+			last := path[len(path)-1].Callee
+			if isTarget[last] {
+				paths = append(paths, path)
+				continue
+			}
+			expansions++
+			for _, e := range calleeEdges(last) {
+				if _, ok := dist[e.Callee]; !ok || onCallPath(path, e.Callee) {
+					continue
+				}
+				push(append(slices.Clip(path), e))
+			}
+		}
+		delete(buckets, f)
+	}
+	return paths
+}
+
+// calleeEdges returns the outgoing edges of n, one per callee, ordered by
+// call site.
+func calleeEdges(n *callgraph.Node) []*callgraph.Edge {
+	edges := slices.Clone(n.Out)
+	slices.SortStableFunc(edges, func(a, b *callgraph.Edge) int {
+		if a.Pos() != b.Pos() {
+			return int(a.Pos() - b.Pos())
+		}
+		return strings.Compare(a.Callee.Func.String(), b.Callee.Func.String())
+	})
+	var unique []*callgraph.Edge
+	seen := make(map[*callgraph.Node]bool)
+	for _, e := range edges {
+		if !seen[e.Callee] {
+			seen[e.Callee] = true
+			unique = append(unique, e)
+		}
+	}
+	return unique
+}
+
+// onCallPath reports whether n is a function on path.
+func onCallPath(path []*callgraph.Edge, n *callgraph.Node) bool {
+	if path[0].Caller == n {
+		return true
+	}
+	return slices.ContainsFunc(path, func(e *callgraph.Edge) bool { return e.Callee == n })
+}
+
+// callDispatch describes how the call of edge is made.
+func callDispatch(edge *callgraph.Edge) string {
+	if edge.Site == nil {
+		return "static"
+	}
+	call := edge.Site.Common()
+	switch {
+	case call.IsInvoke():
+		return "interface"
+	case call.StaticCallee() == nil:
+		return "dynamic"
+	default:
+		return "static"
+	}
+}
+
+// funcSymbol describes an SSA function as an api.Symbol. Named functions
+// get the rich symbol of their declaration; function literals are named
+// after their enclosing function, as in "handler$1".
+func funcSymbol(ctx context.Context, snapshot *cache.Snapshot, fset *token.FileSet, fn *ssa.Function) api.Symbol {
+	if fn.Origin() != nil {
+		fn = fn.Origin()
+	}
+	kind := protocol.Function
+	if fn.Signature.Recv() != nil {
+		kind = protocol.Method
+	}
+	posn := fset.Position(fn.Pos())
+	pkgPath := ""
+	if fn.Pkg != nil {
+		pkgPath = fn.Pkg.Pkg.Path()
+	} else if obj := fn.Object(); obj != nil && obj.Pkg() != nil {
+		pkgPath = obj.Pkg().Path()
+	}
+	if fn.Object() == nil || !posn.IsValid() {
+		return api.Symbol{
+			Name:        fn.Name(),
+			Kind:        golang.ConvertLSPSymbolKind(kind),
+			PackagePath: pkgPath,
+			FilePath:    posn.Filename,
+			Line:        posn.Line,
+		}
+	}
+	pos := protocol.Position{Line: uint32(posn.Line - 1), Character: uint32(posn.Column - 1)}
+	return buildRichSymbol(ctx, snapshot, fn.Name(), kind, protocol.URIFromPath(posn.Filename), protocol.Range{Start: pos, End: pos}, pkgPath)
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// TestCallPath_CachedGraphFollowsOverlays verifies that go_call_path reuses
+// its call graph for the same snapshot, and builds it again, with the
+// unsaved overlays, once they change.
+func TestCallPath_CachedGraphFollowsOverlays(t *testing.T) {
+	ctx := context.Background()
+	h, dir := newTestSession(t, map[string]string{
+		"go.mod":  "module example.com/callpath\n\ngo 1.21\n",
+		"main.go": "package main\n\nfunc a() {}\n\nfunc b() {}\n\nfunc main() { a(); b() }\n",
+	})
+	path := filepath.Join(dir, "main.go")
+	input := api.ICallPathParams{
+		From: api.SymbolLocator{SymbolName: "a", ContextFile: path},
+		To:   api.SymbolLocator{SymbolName: "b", ContextFile: path},
+	}
+	callPath := func() (*api.OCallPathResult, cachedCallGraph) {
+		t.Helper()
+		_, result, err := handleGoCallPath(ctx, h, nil, input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(h.callGraphs) != 1 {
+			t.Fatalf("%d call graphs cached, want 1", len(h.callGraphs))
+		}
+		for _, cached := range h.callGraphs {
+			return result, cached
+		}
+		panic("unreachable")
+	}
+
+	result, first := callPath()
+	if len(result.Paths) != 0 {
+		t.Errorf("found %d paths from a to b on disk, want none", len(result.Paths))
+	}
+	if _, again := callPath(); again.graph != first.graph {
+		t.Error("call graph was built again for the same snapshot")
+	}
+
+	if _, err := h.session.DidModifyFiles(ctx, []file.Modification{{
+		URI:        protocol.URIFromPath(path),
+		Action:     file.Open,
+		Version:    1,
+		Text:       []byte("package main\n\nfunc a() { b() }\n\nfunc b() {}\n\nfunc main() { a() }\n"),
+		LanguageID: protocol.LangGo,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	result, changed := callPath()
+	if changed.graph == first.graph {
+		t.Error("call graph was not built again after the overlay changed")
+	}
+	if len(result.Paths) != 1 {
+		t.Errorf("found %d paths from a to b in the overlay, want 1:\n%s", len(result.Paths), result.Summary)
+	}
+}
+
+// TestCallPath_DifferentViews verifies that go_call_path rejects functions
+// of two different views.
+func TestCallPath_DifferentViews(t *testing.T) {
+	h, dirA := newTestSession(t, map[string]string{
+		"go.mod": "module example.com/a\n\ngo 1.21\n",
+		"a.go":   "package a\n\nfunc A() {}\n",
+	})
+	dirB := addTestView(t, h, map[string]string{
+		"go.mod": "module example.com/b\n\ngo 1.21\n",
+		"b.go":   "package b\n\nfunc B() {}\n",
+	})
+	_, _, err := handleGoCallPath(context.Background(), h, nil, api.ICallPathParams{
+		From: api.SymbolLocator{SymbolName: "A", ContextFile: filepath.Join(dirA, "a.go")},
+		To:   api.SymbolLocator{SymbolName: "B", ContextFile: filepath.Join(dirB, "b.go")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "different workspace views") {
+		t.Errorf("go_call_path across views: got error %v, want one about different views", err)
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_dryrun_change_signature =====
+// Origin: gopls/internal/golang/change_signature.go ChangeSignature(), via
+// golang.LLMChangeSignature
+
+func handleGoDryrunChangeSignature(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IChangeSignatureParams) (*mcp.CallToolResult, *api.OChangeSignatureResult, error) {
+	locator, dir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fh, rng, err := locatorRange(ctx, snapshot, locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, function, skipped, err := golang.LLMChangeSignature(ctx, snapshot, fh, rng, input.Params)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &api.OChangeSignatureResult{
+		Function:     function,
+		Diff:         diff,
+		FilesChanged: changedFiles(changes),
+		Skipped:      skipped,
+	}
+	result.Summary = formatChangeSignature(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+func formatChangeSignature(result *api.OChangeSignatureResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "=== DRY RUN: Change Signature of %s Preview ===\n", result.Function)
+	fmt.Fprintf(&b, "Files: %d\n", len(result.FilesChanged))
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(&b, "Calls not rewritten (%d), fix them by hand:\n", len(result.Skipped))
+		for _, s := range result.Skipped {
+			fmt.Fprintf(&b, "  %s:%d: %s\n", s.File, s.Line, s.Reason)
+		}
+	}
+	b.WriteString("NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&b, "%s\n", result.Diff)
+
+	return b.String()
+}
@@ -0,0 +1,338 @@
+package core
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_check_edit =====
+// Origin: gopls/internal/server/text_synchronization.go DidOpen()/DidClose()
+//
+// The proposed content is pushed into the session as an unsaved overlay, the
+// same way an editor's didOpen would, diagnosed, and then dropped again with
+// a didClose-style modification, or replaced by the overlay that the file
+// had before. Nothing is written to disk.
+
+func handleGoCheckEdit(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.ICheckEditParams) (*mcp.CallToolResult, *api.OCheckEditResult, error) {
+	if !filepath.IsAbs(input.FilePath) {
+		return nil, nil, fmt.Errorf("file_path must be absolute: %s", input.FilePath)
+	}
+	if filepath.Ext(input.FilePath) != ".go" {
+		return nil, nil, fmt.Errorf("file_path must be a Go file: %s", input.FilePath)
+	}
+	if input.NewContent != "" && len(input.Edits) > 0 {
+		return nil, nil, fmt.Errorf("new_content and edits are mutually exclusive")
+	}
+	if input.NewContent == "" && len(input.Edits) == 0 {
+		return nil, nil, fmt.Errorf("one of new_content or edits is required")
+	}
+
+	uri := protocol.URIFromPath(input.FilePath)
+	dir := filepath.Dir(input.FilePath)
+
+	// The overlay is visible session-wide: hold the write lock, which the
+	// other tools' read locks exclude, until it is dropped again.
+	h.overlayMu.Lock()
+	defer h.overlayMu.Unlock()
+
+	content := []byte(input.NewContent)
+	if len(input.Edits) > 0 {
+		snapshot, release, err := h.snapshotForDir(dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", dir, err)
+		}
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			release()
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		old, err := fh.Content()
+		release()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read file: %w", err)
+		}
+		content, err = applyLineEdits(old, input.Edits)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// A file already open in the session gets its overlay back afterwards,
+	// rather than being closed.
+	restore := file.Modification{URI: uri, Action: file.Close, Version: -1}
+	for _, o := range h.session.Overlays() {
+		if o.URI() == uri {
+			text, err := o.Content()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read overlay: %w", err)
+			}
+			restore = file.Modification{URI: uri, Action: file.Open, Version: o.Version(), Text: text, LanguageID: protocol.LangGo}
+		}
+	}
+
+	if _, err := h.session.DidModifyFiles(ctx, []file.Modification{{
+		URI:        uri,
+		Action:     file.Open,
+		Version:    1,
+		Text:       content,
+		LanguageID: protocol.LangGo,
+	}}); err != nil {
+		return nil, nil, fmt.Errorf("failed to apply overlay: %w", err)
+	}
+	defer func() {
+		// Use a fresh context: the overlay must be reverted even if the
+		// request was cancelled while diagnosing.
+		if _, err := h.session.DidModifyFiles(context.Background(), []file.Modification{restore}); err != nil {
+			log.Printf("[gopls-mcp] failed to revert overlay for %s: %v", input.FilePath, err)
+		}
+	}()
+
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", dir, err)
+	}
+	defer release()
+
+	pkgs, err := affectedPackages(ctx, snapshot, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	diags, err := diagnosePackages(ctx, snapshot, pkgs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &api.OCheckEditResult{
+		FilePath:    input.FilePath,
+		Diagnostics: diags,
+	}
+	for _, mp := range pkgs {
+		result.Packages = append(result.Packages, string(mp.PkgPath))
+	}
+	slices.Sort(result.Packages)
+	result.Packages = slices.Compact(result.Packages)
+	for _, d := range diags {
+		switch d.Severity {
+		case "error":
+			result.ErrorCount++
+		case "warning":
+			result.WarningCount++
+		}
+	}
+	result.Summary = formatCheckEdit(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// applyLineEdits applies non-overlapping line replacements to content.
+// All edits refer to line numbers in the original content.
+func applyLineEdits(content []byte, edits []api.LineEdit) ([]byte, error) {
+	lines := strings.SplitAfter(string(content), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	sorted := slices.Clone(edits)
+	slices.SortStableFunc(sorted, func(a, b api.LineEdit) int {
+		return cmp.Compare(a.StartLine, b.StartLine)
+	})
+
+	var buf bytes.Buffer
+	next := 1 // next original line to copy (1-indexed)
+	for _, e := range sorted {
+		if e.StartLine < 1 || e.StartLine > len(lines)+1 {
+			return nil, fmt.Errorf("edit start_line %d out of range (file has %d lines)", e.StartLine, len(lines))
+		}
+		if e.EndLine < e.StartLine-1 || e.EndLine > len(lines) {
+			return nil, fmt.Errorf("edit end_line %d out of range for start_line %d (file has %d lines)", e.EndLine, e.StartLine, len(lines))
+		}
+		if e.StartLine < next {
+			return nil, fmt.Errorf("edit at line %d overlaps a previous edit", e.StartLine)
+		}
+		for _, l := range lines[next-1 : e.StartLine-1] {
+			buf.WriteString(l)
+		}
+		if e.NewText != "" {
+			buf.WriteString(e.NewText)
+			if !strings.HasSuffix(e.NewText, "\n") {
+				buf.WriteByte('\n')
+			}
+		}
+		next = e.EndLine + 1
+	}
+	for _, l := range lines[next-1:] {
+		buf.WriteString(l)
+	}
+	return buf.Bytes(), nil
+}
+
+// affectedPackages returns the packages containing uri together with the
+// workspace packages that directly import them.
+func affectedPackages(ctx context.Context, snapshot *cache.Snapshot, uri protocol.DocumentURI) (map[metadata.PackageID]*metadata.Package, error) {
+	mps, err := snapshot.MetadataForFile(ctx, uri, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages for %s: %w", uri.Path(), err)
+	}
+	if len(mps) == 0 {
+		return nil, fmt.Errorf("no package found for %s", uri.Path())
+	}
+	pkgs := make(map[metadata.PackageID]*metadata.Package)
+	for _, mp := range mps {
+		pkgs[mp.ID] = mp
+		rdeps, err := snapshot.ReverseDependencies(ctx, mp.ID, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load importers of %s: %w", mp.PkgPath, err)
+		}
+		for id, rdep := range rdeps {
+			if !rdep.IsIntermediateTestVariant() {
+				pkgs[id] = rdep
+			}
+		}
+	}
+	return pkgs, nil
+}
+
+// diagnosePackages returns the type-check and analyzer diagnostics of pkgs,
+// merged the way gopls reports them to an editor and sorted by position.
+// Hint-severity diagnostics are omitted.
+func diagnosePackages(ctx context.Context, snapshot *cache.Snapshot, pkgs map[metadata.PackageID]*metadata.Package) ([]api.Diagnostic, error) {
+	cdiags, err := packageDiagnostics(ctx, snapshot, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+	var diags []api.Diagnostic
+	for _, d := range cdiags {
+		if d.Severity != protocol.SeverityHint {
+			diags = append(diags, toAPIDiagnostic(d))
+		}
+	}
+	return diags, nil
+}
+
+// packageDiagnostics returns the type-check diagnostics of pkgs together
+// with those of the analyzers selected by enabled, or of the analyzers
+// enabled in the snapshot's options if enabled is nil. They are merged the
+// way gopls reports them to an editor and sorted by position.
+func packageDiagnostics(ctx context.Context, snapshot *cache.Snapshot, pkgs map[metadata.PackageID]*metadata.Package, enabled func(*settings.Analyzer) bool) ([]*cache.Diagnostic, error) {
+	ids := make([]metadata.PackageID, 0, len(pkgs))
+	for id := range pkgs {
+		ids = append(ids, id)
+	}
+	pkgDiags, err := snapshot.PackageDiagnostics(ctx, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to type-check packages: %w", err)
+	}
+	var analysisDiags map[protocol.DocumentURI][]*cache.Diagnostic
+	switch {
+	case enabled == nil:
+		analysisDiags, err = golang.Analyze(ctx, snapshot, pkgs, nil)
+	case slices.ContainsFunc(settings.AllAnalyzers, enabled):
+		var diags []*cache.Diagnostic
+		diags, err = snapshot.AnalyzeWith(ctx, pkgs, enabled, nil)
+		analysisDiags = moremaps.Group(diags, func(d *cache.Diagnostic) protocol.DocumentURI { return d.URI })
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze packages: %w", err)
+	}
+
+	uris := make(map[protocol.DocumentURI]bool)
+	for uri := range pkgDiags {
+		uris[uri] = true
+	}
+	for uri := range analysisDiags {
+		uris[uri] = true
+	}
+
+	type key struct {
+		uri     protocol.DocumentURI
+		rng     protocol.Range
+		message string
+	}
+	seen := make(map[key]bool) // a file in several packages is diagnosed once per package
+	var diags []*cache.Diagnostic
+	for uri := range uris {
+		for _, d := range golang.CombineDiagnostics(pkgDiags[uri], analysisDiags[uri]) {
+			k := key{d.URI, d.Range, d.Message}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			diags = append(diags, d)
+		}
+	}
+	slices.SortFunc(diags, func(a, b *cache.Diagnostic) int {
+		return cmp.Or(
+			cmp.Compare(a.URI.Path(), b.URI.Path()),
+			cmp.Compare(a.Range.Start.Line, b.Range.Start.Line),
+			cmp.Compare(a.Range.Start.Character, b.Range.Start.Character),
+		)
+	})
+	return diags, nil
+}
+
+func toAPIDiagnostic(d *cache.Diagnostic) api.Diagnostic {
+	return api.Diagnostic{
+		File:     d.URI.Path(),
+		Line:     int(d.Range.Start.Line) + 1,
+		Column:   int(d.Range.Start.Character) + 1,
+		Severity: severityName(d.Severity),
+		Source:   string(d.Source),
+		Message:  d.Message,
+	}
+}
+
+func severityName(s protocol.DiagnosticSeverity) string {
+	switch s {
+	case protocol.SeverityError:
+		return "error"
+	case protocol.SeverityWarning:
+		return "warning"
+	case protocol.SeverityInformation:
+		return "info"
+	case protocol.SeverityHint:
+		return "hint"
+	default:
+		return "error"
+	}
+}
+
+func formatCheckEdit(result *api.OCheckEditResult) string {
+	var b strings.Builder
+
+	if result.ErrorCount == 0 {
+		fmt.Fprintf(&b, "Edit to %s type-checks cleanly", result.FilePath)
+	} else {
+		fmt.Fprintf(&b, "Edit to %s has %d error(s)", result.FilePath, result.ErrorCount)
+	}
+	fmt.Fprintf(&b, " (checked %d package(s): %s)\n", len(result.Packages), strings.Join(result.Packages, ", "))
+	b.WriteString("No changes were written to disk.\n")
+
+	if len(result.Diagnostics) > 0 {
+		fmt.Fprintf(&b, "\nDiagnostics (%d):\n", len(result.Diagnostics))
+		for _, d := range result.Diagnostics {
+			fmt.Fprintf(&b, "  %s:%d:%d: [%s] %s", d.File, d.Line, d.Column, d.Severity, d.Message)
+			if d.Source != "" {
+				fmt.Fprintf(&b, " (%s)", d.Source)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
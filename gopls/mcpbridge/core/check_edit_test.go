@@ -0,0 +1,154 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+func TestApplyLineEdits(t *testing.T) {
+	content := "one\ntwo\nthree\nfour\n"
+
+	tests := []struct {
+		name    string
+		edits   []api.LineEdit
+		want    string
+		wantErr string // substring in error message
+	}{
+		{
+			name:  "replace single line",
+			edits: []api.LineEdit{{StartLine: 2, EndLine: 2, NewText: "TWO"}},
+			want:  "one\nTWO\nthree\nfour\n",
+		},
+		{
+			name:  "replace range with more lines",
+			edits: []api.LineEdit{{StartLine: 2, EndLine: 3, NewText: "a\nb\nc\n"}},
+			want:  "one\na\nb\nc\nfour\n",
+		},
+		{
+			name:  "insert before line",
+			edits: []api.LineEdit{{StartLine: 3, EndLine: 2, NewText: "inserted"}},
+			want:  "one\ntwo\ninserted\nthree\nfour\n",
+		},
+		{
+			name:  "append at end",
+			edits: []api.LineEdit{{StartLine: 5, EndLine: 4, NewText: "five"}},
+			want:  "one\ntwo\nthree\nfour\nfive\n",
+		},
+		{
+			name:  "delete lines",
+			edits: []api.LineEdit{{StartLine: 1, EndLine: 2}},
+			want:  "three\nfour\n",
+		},
+		{
+			name: "multiple edits use original line numbers",
+			edits: []api.LineEdit{
+				{StartLine: 4, EndLine: 4, NewText: "FOUR"},
+				{StartLine: 1, EndLine: 1, NewText: "ONE\nONE-AND-A-HALF"},
+			},
+			want: "ONE\nONE-AND-A-HALF\ntwo\nthree\nFOUR\n",
+		},
+		{
+			name: "overlapping edits",
+			edits: []api.LineEdit{
+				{StartLine: 1, EndLine: 3, NewText: "x"},
+				{StartLine: 2, EndLine: 2, NewText: "y"},
+			},
+			wantErr: "overlaps",
+		},
+		{
+			name:    "start line out of range",
+			edits:   []api.LineEdit{{StartLine: 9, EndLine: 9, NewText: "x"}},
+			wantErr: "start_line 9 out of range",
+		},
+		{
+			name:    "end line before start",
+			edits:   []api.LineEdit{{StartLine: 3, EndLine: 1, NewText: "x"}},
+			wantErr: "end_line 1 out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyLineEdits([]byte(content), tt.edits)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyLineEdits() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyLineEdits() unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("applyLineEdits() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckEdit_RestoresOverlay verifies that go_check_edit puts back the
+// overlay of a file that was already open, instead of closing it.
+func TestCheckEdit_RestoresOverlay(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/a\n\ngo 1.21\n",
+		"a.go":   "package a\n\nfunc A() {}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	h := NewHandler(nil, WithOptions(settings.DefaultOptions()))
+	h.session = cache.NewSession(ctx, cache.New(nil))
+	defer h.session.Shutdown(ctx)
+
+	dirURI := protocol.URIFromPath(dir)
+	env, err := cache.FetchGoEnv(ctx, dirURI, h.options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, release, err := h.session.NewView(ctx, &cache.Folder{Dir: dirURI, Options: h.options, Env: *env})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	path := filepath.Join(dir, "a.go")
+	uri := protocol.URIFromPath(path)
+	open := "package a\n\nfunc A() {}\n\nfunc B() {}\n"
+	if _, err := h.session.DidModifyFiles(ctx, []file.Modification{{
+		URI: uri, Action: file.Open, Version: 7, Text: []byte(open), LanguageID: protocol.LangGo,
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, result, err := handleGoCheckEdit(ctx, h, nil, api.ICheckEditParams{
+		FilePath:   path,
+		NewContent: "package a\n\nfunc A() { undefined() }\n",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ErrorCount == 0 {
+		t.Errorf("go_check_edit reported no errors for the proposed content")
+	}
+
+	fh, err := h.session.ReadFile(ctx, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := fh.Content()
+	if string(got) != open || fh.Version() != 7 {
+		t.Errorf("overlay after go_check_edit = %q (version %d), want %q (version 7)", got, fh.Version(), open)
+	}
+}
@@ -0,0 +1,411 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/protocol/command"
+	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_code_actions / go_dryrun_code_action =====
+// Origin: gopls/internal/server/code_action.go CodeAction() and ResolveCodeAction()
+//
+// The actions are computed as for an editor that asks for every kind: the
+// fixes of the diagnostics in the selection, then those of
+// golang.CodeActions. Actions that only show something (source.doc,
+// source.assembly, ...) are left out.
+//
+// IDs are not stored anywhere. An ID names the kind, the selection and a
+// hash of the title; go_dryrun_code_action computes the actions at the
+// selection again and resolves the one that matches.
+
+// displayKinds are the kinds of the actions that make no changes.
+var displayKinds = []protocol.CodeActionKind{
+	settings.GoAssembly,
+	settings.GoDoc,
+	settings.GoFreeSymbols,
+	settings.GoSplitPackage,
+	settings.GoTest,
+	settings.GoToggleCompilerOptDetails,
+	settings.GoplsDocFeatures,
+}
+
+// previewCommands computes the changes of the commands of the actions
+// that have no edits, from the command's arguments, without applying them.
+var previewCommands = map[command.Command]func(ctx context.Context, snapshot *cache.Snapshot, args []json.RawMessage) ([]protocol.DocumentChange, error){
+	command.ApplyFix: func(ctx context.Context, snapshot *cache.Snapshot, args []json.RawMessage) ([]protocol.DocumentChange, error) {
+		var a command.ApplyFixArgs
+		if err := command.UnmarshalArgs(args, &a); err != nil {
+			return nil, err
+		}
+		fh, err := snapshot.ReadFile(ctx, a.Location.URI)
+		if err != nil {
+			return nil, err
+		}
+		return golang.ApplyFix(ctx, a.Fix, snapshot, fh, a.Location.Range)
+	},
+	command.ExtractToNewFile: func(ctx context.Context, snapshot *cache.Snapshot, args []json.RawMessage) ([]protocol.DocumentChange, error) {
+		var loc protocol.Location
+		if err := command.UnmarshalArgs(args, &loc); err != nil {
+			return nil, err
+		}
+		fh, err := snapshot.ReadFile(ctx, loc.URI)
+		if err != nil {
+			return nil, err
+		}
+		return golang.ExtractToNewFile(ctx, snapshot, fh, loc.Range)
+	},
+}
+
+func handleGoCodeActions(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.ICodeActionsParams) (*mcp.CallToolResult, *api.OCodeActionsResult, error) {
+	var (
+		dir  string
+		path string // of the file, for a line range
+	)
+	switch {
+	case input.Locator != nil && input.FilePath != "":
+		return nil, nil, fmt.Errorf("locator and file_path are mutually exclusive")
+	case input.Locator != nil:
+		locator, viewDir, err := h.resolveLocator(ctx, *input.Locator)
+		if err != nil {
+			return nil, nil, err
+		}
+		input.Locator, dir = &locator, viewDir
+	case input.FilePath != "":
+		if !filepath.IsAbs(input.FilePath) {
+			return nil, nil, fmt.Errorf("file_path must be absolute: %s", input.FilePath)
+		}
+		if input.StartLine < 1 {
+			return nil, nil, fmt.Errorf("start_line is required with file_path (1-indexed)")
+		}
+		dir, path = filepath.Dir(input.FilePath), input.FilePath
+	default:
+		return nil, nil, fmt.Errorf("either locator, or file_path and start_line, is required")
+	}
+
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	var (
+		fh  file.Handle
+		rng protocol.Range
+	)
+	if path != "" {
+		fh, err = snapshot.ReadFile(ctx, protocol.URIFromPath(path))
+		if err != nil {
+			return nil, nil, err
+		}
+		rng, err = lineRange(fh, input.StartLine, cmp.Or(input.EndLine, input.StartLine))
+	} else {
+		fh, rng, err = locatorRange(ctx, snapshot, *input.Locator)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actions, err := codeActionsAt(ctx, snapshot, fh, rng, codeActionKinds(input.Kinds))
+	if err != nil {
+		return nil, nil, err
+	}
+	result := &api.OCodeActionsResult{}
+	for _, act := range actions {
+		ca := api.CodeAction{
+			ID:          codeActionID(fh.URI(), rng, act),
+			Title:       act.Title,
+			Kind:        string(act.Kind),
+			Previewable: act.Edit != nil || act.Command != nil && previewCommands[command.Command(act.Command.Command)] != nil,
+		}
+		for _, d := range act.Diagnostics {
+			ca.Fixes = append(ca.Fixes, d.Message)
+		}
+		result.Actions = append(result.Actions, ca)
+	}
+	result.Summary = formatCodeActions(fh.URI().Path(), rng, result.Actions)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+func handleGoDryrunCodeAction(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IDryrunCodeActionParams) (*mcp.CallToolResult, *api.ODryrunCodeActionResult, error) {
+	kind, path, rng, err := parseCodeActionID(input.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	snapshot, release, err := h.snapshotForDir(filepath.Dir(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(path))
+	if err != nil {
+		return nil, nil, err
+	}
+	actions, err := codeActionsAt(ctx, snapshot, fh, rng, codeActionKinds([]string{string(kind)}))
+	if err != nil {
+		return nil, nil, err
+	}
+	i := slices.IndexFunc(actions, func(act protocol.CodeAction) bool {
+		return codeActionID(fh.URI(), rng, act) == input.ID
+	})
+	if i < 0 {
+		return nil, nil, fmt.Errorf("code action %s is no longer available (the file may have changed); call go_code_actions again", input.ID)
+	}
+	act := actions[i]
+
+	changes, err := resolveCodeAction(ctx, snapshot, act)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compute %q: %w", act.Title, err)
+	}
+	diff, err := toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &api.ODryrunCodeActionResult{
+		Title:        act.Title,
+		Kind:         string(act.Kind),
+		Diff:         diff,
+		FilesChanged: changedFiles(changes),
+	}
+	result.Summary = formatCodeActionChanges(act, diff)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// codeActionKinds returns the kind filter for golang.CodeActions that
+// enables kinds and their sub-kinds, or every kind that makes changes if
+// kinds is empty.
+func codeActionKinds(kinds []string) func(protocol.CodeActionKind) bool {
+	return func(kind protocol.CodeActionKind) bool {
+		if slices.Contains(displayKinds, kind) {
+			return false
+		}
+		if len(kinds) == 0 {
+			return true
+		}
+		return slices.ContainsFunc(kinds, func(k string) bool {
+			return string(kind) == k || strings.HasPrefix(string(kind), k+".")
+		})
+	}
+}
+
+// codeActionsAt returns the enabled code actions for rng of fh: the fixes
+// suggested for the diagnostics that intersect it, then the actions of
+// golang.CodeActions.
+func codeActionsAt(ctx context.Context, snapshot *cache.Snapshot, fh file.Handle, rng protocol.Range, enabled func(protocol.CodeActionKind) bool) ([]protocol.CodeAction, error) {
+	mps, err := snapshot.MetadataForFile(ctx, fh.URI(), true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages for %s: %w", fh.URI().Path(), err)
+	}
+	if len(mps) == 0 {
+		return nil, fmt.Errorf("no package found for %s", fh.URI().Path())
+	}
+	pkgs := make(map[metadata.PackageID]*metadata.Package)
+	for _, mp := range mps {
+		pkgs[mp.ID] = mp
+	}
+	diags, err := packageDiagnostics(ctx, snapshot, pkgs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		actions []protocol.CodeAction
+		pdiags  []protocol.Diagnostic // for golang.CodeActions
+	)
+	for _, d := range diags {
+		if d.URI != fh.URI() || !protocol.Intersect(d.Range, rng) {
+			continue
+		}
+		pd := protocol.Diagnostic{
+			Range:    d.Range,
+			Severity: d.Severity,
+			Code:     d.Code,
+			Source:   string(d.Source),
+			Message:  d.Message,
+			Tags:     d.Tags,
+		}
+		pdiags = append(pdiags, pd)
+		for _, fix := range d.SuggestedFixes {
+			act := protocol.CodeAction{
+				Title:       fix.Title,
+				Kind:        cmp.Or(fix.ActionKind, protocol.QuickFix),
+				Diagnostics: []protocol.Diagnostic{pd},
+				Command:     fix.Command,
+			}
+			if !enabled(act.Kind) {
+				continue
+			}
+			if fix.Edits != nil {
+				changes, err := fixChanges(ctx, snapshot, fix)
+				if err != nil {
+					return nil, err
+				}
+				act.Edit = protocol.NewWorkspaceEdit(changes...)
+			}
+			actions = append(actions, act)
+		}
+	}
+
+	more, err := golang.CodeActions(ctx, snapshot, fh, rng, pdiags, enabled, protocol.CodeActionInvoked)
+	if err != nil {
+		return nil, err
+	}
+	return append(actions, more...), nil
+}
+
+// resolveCodeAction returns the changes act would make: its edits, or
+// those its command would apply.
+func resolveCodeAction(ctx context.Context, snapshot *cache.Snapshot, act protocol.CodeAction) ([]protocol.DocumentChange, error) {
+	if act.Edit != nil {
+		return act.Edit.DocumentChanges, nil
+	}
+	if act.Command == nil {
+		return nil, fmt.Errorf("the action has neither edits nor a command")
+	}
+	preview := previewCommands[command.Command(act.Command.Command)]
+	if preview == nil {
+		return nil, fmt.Errorf("the action runs the %s command, which cannot be previewed", act.Command.Command)
+	}
+	return preview(ctx, snapshot, act.Command.Arguments)
+}
+
+// locatorRange returns the file of locator's context file and the range of
+// the identifier it resolves to.
+func locatorRange(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator) (file.Handle, protocol.Range, error) {
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(locator.ContextFile))
+	if err != nil {
+		return nil, protocol.Range{}, err
+	}
+	node, err := golang.ResolveNode(ctx, snapshot, fh, locator)
+	if err != nil {
+		return nil, protocol.Range{}, err
+	}
+	_, pgf, err := golang.NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, protocol.Range{}, err
+	}
+	rng, err := pgf.PosRange(node.Pos, node.Pos+token.Pos(len(locator.SymbolName)))
+	if err != nil {
+		return nil, protocol.Range{}, err
+	}
+	return fh, rng, nil
+}
+
+// lineRange returns the range of lines start through end (1-indexed,
+// inclusive) of fh, without the final newline.
+func lineRange(fh file.Handle, start, end int) (protocol.Range, error) {
+	content, err := fh.Content()
+	if err != nil {
+		return protocol.Range{}, err
+	}
+	lines := strings.SplitAfter(string(content), "\n")
+	if end < start || end > len(lines) {
+		return protocol.Range{}, fmt.Errorf("invalid line range %d-%d: %s has %d lines", start, end, fh.URI().Path(), len(lines))
+	}
+	startOffset := len(strings.Join(lines[:start-1], ""))
+	endOffset := len(strings.Join(lines[:end], ""))
+	endOffset -= len(lines[end-1]) - len(strings.TrimRight(lines[end-1], "\r\n"))
+	return protocol.NewMapper(fh.URI(), content).OffsetRange(startOffset, endOffset)
+}
+
+// codeActionID returns the ID of act, offered for rng of the file at uri:
+//
+//	kind@path:line:col-line:col#hash
+//
+// Lines and columns are 1-indexed, and the hash is that of the title.
+func codeActionID(uri protocol.DocumentURI, rng protocol.Range, act protocol.CodeAction) string {
+	sum := sha256.Sum256([]byte(act.Title))
+	return fmt.Sprintf("%s@%s:%d:%d-%d:%d#%s", act.Kind, uri.Path(),
+		rng.Start.Line+1, rng.Start.Character+1, rng.End.Line+1, rng.End.Character+1,
+		hex.EncodeToString(sum[:4]))
+}
+
+var codeActionIDRx = regexp.MustCompile(`^([^@]+)@(.+):(\d+):(\d+)-(\d+):(\d+)#[0-9a-f]+$`)
+
+// parseCodeActionID returns the kind, file and range of a codeActionID.
+func parseCodeActionID(id string) (protocol.CodeActionKind, string, protocol.Range, error) {
+	m := codeActionIDRx.FindStringSubmatch(id)
+	if m == nil {
+		return "", "", protocol.Range{}, fmt.Errorf("invalid code action ID %q: IDs are returned by go_code_actions", id)
+	}
+	var pos [4]uint32
+	for i := range pos {
+		n, err := strconv.ParseUint(m[3+i], 10, 32)
+		if err != nil || n == 0 {
+			return "", "", protocol.Range{}, fmt.Errorf("invalid code action ID %q: bad position", id)
+		}
+		pos[i] = uint32(n - 1)
+	}
+	rng := protocol.Range{
+		Start: protocol.Position{Line: pos[0], Character: pos[1]},
+		End:   protocol.Position{Line: pos[2], Character: pos[3]},
+	}
+	return protocol.CodeActionKind(m[1]), m[2], rng, nil
+}
+
+// changedFiles returns the files that changes modify, create or rename.
+func changedFiles(changes []protocol.DocumentChange) []string {
+	var files []string
+	for _, c := range changes {
+		var path string
+		switch {
+		case c.TextDocumentEdit != nil:
+			path = c.TextDocumentEdit.TextDocument.URI.Path()
+		case c.CreateFile != nil:
+			path = c.CreateFile.URI.Path()
+		case c.RenameFile != nil:
+			path = c.RenameFile.NewURI.Path()
+		case c.DeleteFile != nil:
+			path = c.DeleteFile.URI.Path()
+		}
+		if path != "" && !slices.Contains(files, path) {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+func formatCodeActions(path string, rng protocol.Range, actions []api.CodeAction) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Found %d code action(s) at %s:%d:%d-%d:%d\n", len(actions), path,
+		rng.Start.Line+1, rng.Start.Character+1, rng.End.Line+1, rng.End.Character+1)
+	for _, act := range actions {
+		fmt.Fprintf(&b, "\n- %s (%s)\n", act.Title, act.Kind)
+		fmt.Fprintf(&b, "  id: %s\n", act.ID)
+		for _, msg := range act.Fixes {
+			fmt.Fprintf(&b, "  fixes: %s\n", msg)
+		}
+		if !act.Previewable {
+			b.WriteString("  (cannot be previewed)\n")
+		}
+	}
+	if len(actions) > 0 {
+		b.WriteString("\nPass an id to go_dryrun_code_action to preview its changes.\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package core
+
+import (
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+func TestCodeActionID(t *testing.T) {
+	uri := protocol.URIFromPath("/work/a b/main.go")
+	rng := protocol.Range{
+		Start: protocol.Position{Line: 9, Character: 0},
+		End:   protocol.Position{Line: 10, Character: 12},
+	}
+	act := protocol.CodeAction{Title: "Extract function", Kind: "refactor.extract.function"}
+
+	id := codeActionID(uri, rng, act)
+	if want := "refactor.extract.function@/work/a b/main.go:10:1-11:13#"; id[:len(want)] != want {
+		t.Errorf("codeActionID = %q, want prefix %q", id, want)
+	}
+	kind, path, got, err := parseCodeActionID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind != act.Kind || path != uri.Path() || got != rng {
+		t.Errorf("parseCodeActionID(%q) = %q, %q, %v, want %q, %q, %v", id, kind, path, got, act.Kind, uri.Path(), rng)
+	}
+
+	// The title is part of the ID.
+	if other := codeActionID(uri, rng, protocol.CodeAction{Title: "Extract method", Kind: act.Kind}); other == id {
+		t.Errorf("actions with different titles have the same ID %q", id)
+	}
+	for _, bad := range []string{"", "quickfix", "quickfix@/a.go:0:1-1:1#00", "quickfix@/a.go:1:1#00"} {
+		if _, _, _, err := parseCodeActionID(bad); err == nil {
+			t.Errorf("parseCodeActionID(%q) succeeded, want an error", bad)
+		}
+	}
+}
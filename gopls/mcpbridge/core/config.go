@@ -38,6 +38,7 @@ type MCPConfig struct {
 	// IdleTimeout is the duration of inactivity before gopls session resources
 	// are released. Accepts Go duration strings: "5m", "30s", "500ms".
 	// On next tool call the session is re-initialized automatically.
+	// Resources are kept while a client is subscribed to any resource.
 	// Default: "5m".
 	IdleTimeout string `json:"idle_timeout,omitempty"`
 }
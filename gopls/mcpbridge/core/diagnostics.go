@@ -0,0 +1,265 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_diagnostics =====
+// Origin: gopls/internal/mcp/workspace_diagnostics.go and file_diagnostics.go
+//
+// Type errors come from snapshot.PackageDiagnostics and analyzer findings
+// from snapshot.AnalyzeWith, combined as for an editor (see
+// packageDiagnostics). Suggested fixes are rendered as unified diffs and
+// never applied.
+
+// severities orders the severity names from the most to the least severe.
+var severities = []string{"error", "warning", "info", "hint"}
+
+func handleGoDiagnostics(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IDiagnosticsParams) (*mcp.CallToolResult, *api.ODiagnosticsResult, error) {
+	minSeverity := cmp.Or(input.MinSeverity, "info")
+	if !slices.Contains(severities, minSeverity) {
+		return nil, nil, fmt.Errorf("invalid min_severity %q: want one of %s", input.MinSeverity, strings.Join(severities, ", "))
+	}
+	enabled, analyzers, err := selectAnalyzers(input.Analyzers, input.TypeErrorsOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	if input.FilePath != "" && !filepath.IsAbs(input.FilePath) {
+		return nil, nil, fmt.Errorf("file_path must be absolute: %s", input.FilePath)
+	}
+
+	var dirs []string // the directories of the views to diagnose
+	switch {
+	case input.FilePath != "":
+		dirs = []string{filepath.Dir(input.FilePath)}
+	case input.Cwd != "":
+		dirs = []string{input.Cwd}
+	default:
+		for _, view := range h.session.Views() {
+			dirs = append(dirs, view.Root().Path())
+		}
+	}
+
+	result := &api.ODiagnosticsResult{Analyzers: analyzers}
+	byPackage := make(map[string][]api.Diagnostic)
+	for _, dir := range dirs {
+		snapshot, release, err := h.snapshotForDir(dir)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", dir, err)
+		}
+		pkgs, err := diagnosticsScope(ctx, snapshot, input)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		if len(pkgs) == 0 {
+			release()
+			continue
+		}
+		diags, err := packageDiagnostics(ctx, snapshot, pkgs, enabled)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+		// Each file goes to its package; test variants share the path of
+		// the package they test.
+		pkgOf := make(map[protocol.DocumentURI]string)
+		for _, mp := range pkgs {
+			result.PackageCount++
+			for _, uri := range mp.CompiledGoFiles {
+				pkgOf[uri] = string(mp.PkgPath)
+			}
+		}
+		for _, d := range diags {
+			if input.FilePath != "" && d.URI.Path() != filepath.Clean(input.FilePath) {
+				continue
+			}
+			severity := severityName(d.Severity)
+			if slices.Index(severities, severity) > slices.Index(severities, minSeverity) {
+				continue
+			}
+			ad := toAPIDiagnostic(d)
+			if ad.Fixes, err = suggestedFixes(ctx, snapshot, d); err != nil {
+				release()
+				return nil, nil, err
+			}
+			pkg := cmp.Or(pkgOf[d.URI], "(no package)")
+			byPackage[pkg] = append(byPackage[pkg], ad)
+			switch severity {
+			case "error":
+				result.ErrorCount++
+			case "warning":
+				result.WarningCount++
+			}
+		}
+		release()
+	}
+	if result.PackageCount == 0 {
+		if input.PackagePath != "" {
+			return nil, nil, fmt.Errorf("package not found: %s", input.PackagePath)
+		}
+		return nil, nil, fmt.Errorf("no packages to diagnose")
+	}
+	for pkg, diags := range moremaps.Sorted(byPackage) {
+		result.Packages = append(result.Packages, api.PackageDiagnostics{Package: pkg, Diagnostics: diags})
+	}
+	result.Summary = formatDiagnostics(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// selectAnalyzers returns the analyzer selection for the analyzers named
+// in names, and the names of the analyzers it selects. No names select
+// those enabled in the options (a nil selection); typeErrorsOnly selects
+// none.
+func selectAnalyzers(names []string, typeErrorsOnly bool) (func(*settings.Analyzer) bool, []string, error) {
+	if typeErrorsOnly {
+		if len(names) > 0 {
+			return nil, nil, fmt.Errorf("analyzers and type_errors_only are mutually exclusive")
+		}
+		return func(*settings.Analyzer) bool { return false }, nil, nil
+	}
+	if len(names) == 0 {
+		return nil, nil, nil
+	}
+	known := make(map[string]bool)
+	for _, a := range settings.AllAnalyzers {
+		known[a.Analyzer().Name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return nil, nil, fmt.Errorf("unknown analyzer %q; gopls analyzers include %s", name, strings.Join(slices.Sorted(maps.Keys(known)), ", "))
+		}
+	}
+	names = slices.Clone(names)
+	slices.Sort(names)
+	names = slices.Compact(names)
+	return func(a *settings.Analyzer) bool { return slices.Contains(names, a.Analyzer().Name) }, names, nil
+}
+
+// diagnosticsScope returns the packages of snapshot to diagnose: those
+// containing the file, or the package and its test variants, or all
+// workspace packages.
+func diagnosticsScope(ctx context.Context, snapshot *cache.Snapshot, input api.IDiagnosticsParams) (map[metadata.PackageID]*metadata.Package, error) {
+	pkgs := make(map[metadata.PackageID]*metadata.Package)
+	switch {
+	case input.FilePath != "":
+		mps, err := snapshot.MetadataForFile(ctx, protocol.URIFromPath(input.FilePath), true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load packages for %s: %w", input.FilePath, err)
+		}
+		if len(mps) == 0 {
+			return nil, fmt.Errorf("no package found for %s", input.FilePath)
+		}
+		for _, mp := range mps {
+			pkgs[mp.ID] = mp
+		}
+	case input.PackagePath != "":
+		md, err := snapshot.LoadMetadataGraph(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata graph: %w", err)
+		}
+		for _, path := range []string{input.PackagePath, input.PackagePath + "_test"} {
+			for _, mp := range md.ForPackagePath[metadata.PackagePath(path)] {
+				if !mp.IsIntermediateTestVariant() {
+					pkgs[mp.ID] = mp
+				}
+			}
+		}
+	default:
+		md, err := snapshot.LoadMetadataGraph(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load metadata graph: %w", err)
+		}
+		for id := range snapshot.WorkspacePackages().All() {
+			if mp := md.Packages[id]; mp != nil && !mp.IsIntermediateTestVariant() {
+				pkgs[id] = mp
+			}
+		}
+	}
+	return pkgs, nil
+}
+
+// suggestedFixes returns the fixes suggested for d, with their edits as a
+// unified diff.
+func suggestedFixes(ctx context.Context, snapshot *cache.Snapshot, d *cache.Diagnostic) ([]api.SuggestedFix, error) {
+	var fixes []api.SuggestedFix
+	for _, fix := range d.SuggestedFixes {
+		changes, err := fixChanges(ctx, snapshot, fix)
+		if err != nil {
+			return nil, err
+		}
+		diff, err := toUnifiedDiff(ctx, snapshot, changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render fix %q: %w", fix.Title, err)
+		}
+		fixes = append(fixes, api.SuggestedFix{Title: fix.Title, Diff: diff})
+	}
+	return fixes, nil
+}
+
+// fixChanges returns the edits of fix as document changes, by file.
+func fixChanges(ctx context.Context, snapshot *cache.Snapshot, fix cache.SuggestedFix) ([]protocol.DocumentChange, error) {
+	var changes []protocol.DocumentChange
+	for uri, edits := range moremaps.Sorted(fix.Edits) {
+		fh, err := snapshot.ReadFile(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, protocol.DocumentChangeEdit(fh, edits))
+	}
+	return changes, nil
+}
+
+func formatDiagnostics(result *api.ODiagnosticsResult) string {
+	var b strings.Builder
+
+	total := 0
+	for _, pkg := range result.Packages {
+		total += len(pkg.Diagnostics)
+	}
+	fmt.Fprintf(&b, "Found %d diagnostic(s) (%d error(s), %d warning(s)) in %d package(s) checked", total, result.ErrorCount, result.WarningCount, result.PackageCount)
+	if len(result.Analyzers) > 0 {
+		fmt.Fprintf(&b, " with analyzers %s", strings.Join(result.Analyzers, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, pkg := range result.Packages {
+		fmt.Fprintf(&b, "\n%s (%d):\n", pkg.Package, len(pkg.Diagnostics))
+		for _, d := range pkg.Diagnostics {
+			fmt.Fprintf(&b, "  %s:%d:%d: [%s] %s", d.File, d.Line, d.Column, d.Severity, d.Message)
+			if d.Source != "" {
+				fmt.Fprintf(&b, " (%s)", d.Source)
+			}
+			b.WriteString("\n")
+			for _, fix := range d.Fixes {
+				fmt.Fprintf(&b, "    Suggested fix: %s\n", fix.Title)
+				if fix.Diff != "" {
+					for line := range strings.Lines(strings.TrimRight(fix.Diff, "\n")) {
+						fmt.Fprintf(&b, "      %s", line)
+					}
+					b.WriteString("\n")
+				}
+			}
+		}
+	}
+	if len(result.Packages) > 0 {
+		b.WriteString("\nNo fixes were applied.\n")
+	}
+
+	return b.String()
+}
@@ -30,13 +30,27 @@ func formatRenameChanges(ctx context.Context, snapshot *cache.Snapshot, changes
 	return builder.String(), nil
 }
 
+// formatCodeActionChanges formats the diff of the changes of a code action.
+//
+// DRY RUN: This only formats proposed changes - no files are modified.
+func formatCodeActionChanges(act protocol.CodeAction, diffText string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "=== DRY RUN: Code Action Preview ===\n")
+	fmt.Fprintf(&builder, "The following changes would be made by %q (%s):\n", act.Title, act.Kind)
+	fmt.Fprintf(&builder, "NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&builder, "%s\n", diffText)
+	return builder.String()
+}
+
 // toUnifiedDiff converts a list of DocumentChange operations into a unified diff format.
 // Adapted from gopls/internal/mcp/file_diagnostics.go
 func toUnifiedDiff(ctx context.Context, snapshot *cache.Snapshot, changes []protocol.DocumentChange) (string, error) {
 	var res strings.Builder
+	created := make(map[protocol.DocumentURI]bool) // files that edits fill in
 	for _, change := range changes {
 		switch {
 		case change.CreateFile != nil:
+			created[change.CreateFile.URI] = true
 			res.WriteString(diff.Unified("/dev/null", change.CreateFile.URI.Path(), "", ""))
 		case change.DeleteFile != nil:
 			fh, err := snapshot.ReadFile(ctx, change.DeleteFile.URI)
@@ -75,9 +89,13 @@ func toUnifiedDiff(ctx context.Context, snapshot *cache.Snapshot, changes []prot
 			if err != nil {
 				return "", err
 			}
+			oldName := filepath.ToSlash(fh.URI().Path())
 			content, err := fh.Content()
 			if err != nil {
-				return "", err
+				if !created[fh.URI()] {
+					return "", err
+				}
+				oldName, content = "/dev/null", nil
 			}
 
 			var newSrc bytes.Buffer
@@ -99,7 +117,7 @@ func toUnifiedDiff(ctx context.Context, snapshot *cache.Snapshot, changes []prot
 				newSrc.Write(content[start:])
 			}
 
-			res.WriteString(diff.Unified(filepath.ToSlash(fh.URI().Path()), filepath.ToSlash(fh.URI().Path()), string(content), newSrc.String()))
+			res.WriteString(diff.Unified(oldName, filepath.ToSlash(fh.URI().Path()), string(content), newSrc.String()))
 		default:
 			continue // this shouldn't happen
 		}
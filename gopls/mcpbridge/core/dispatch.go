@@ -0,0 +1,165 @@
+package core
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== Interface dispatch =====
+//
+// For a concrete method, gopls also reports the references to the interface
+// methods it implements (found through the methodsets index, as for
+// go_implementation), since a call through the interface may reach it.
+// go_symbol_references and go_get_call_hierarchy report them all; when
+// include_dynamic is set, each one is also tagged with how it reaches the
+// method.
+
+// Dispatch kinds of a reference to a concrete method.
+const (
+	dispatchStatic    = "static"
+	dispatchInterface = "interface"
+)
+
+// isConcreteMethod reports whether obj is a method of a non-interface type.
+func isConcreteMethod(obj types.Object) bool {
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	recv := fn.Signature().Recv()
+	return recv != nil && !types.IsInterface(recv.Type())
+}
+
+// identAt returns the identifier at loc, with the type information of its
+// package. The file must belong to snapshot's view.
+func identAt(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) (*types.Info, *ast.Ident) {
+	info, cur, ok := identCursorAt(ctx, snapshot, loc)
+	if !ok {
+		return nil, nil
+	}
+	return info, cur.Node().(*ast.Ident)
+}
+
+// identCursorAt is like identAt, but returns a cursor for the identifier.
+func identCursorAt(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) (*types.Info, inspector.Cursor, bool) {
+	pkg, pgf, err := golang.NarrowestPackageForFile(ctx, snapshot, loc.URI)
+	if err != nil {
+		return nil, inspector.Cursor{}, false
+	}
+	pos, err := pgf.PositionPos(loc.Range.Start)
+	if err != nil {
+		return nil, inspector.Cursor{}, false
+	}
+	cur, ok := pgf.Cursor().FindByPos(pos, pos)
+	if !ok {
+		return nil, inspector.Cursor{}, false
+	}
+	if _, ok := cur.Node().(*ast.Ident); !ok {
+		return nil, inspector.Cursor{}, false
+	}
+	return pkg.TypesInfo(), cur, true
+}
+
+// referenceInterface returns the interface, such as "io.Writer", whose method
+// the identifier at loc refers to, or "" if it refers to something else,
+// e.g. to the concrete method itself.
+func referenceInterface(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) string {
+	info, id := identAt(ctx, snapshot, loc)
+	if id == nil {
+		return ""
+	}
+	fn, ok := info.Uses[id].(*types.Func)
+	if !ok {
+		return ""
+	}
+	recv := fn.Signature().Recv()
+	if recv == nil || !types.IsInterface(recv.Type()) {
+		return ""
+	}
+	return types.TypeString(recv.Type(), (*types.Package).Name)
+}
+
+// classifyReferences records in dispatch the interface of each of locations
+// that refers to an interface method; see referenceInterface.
+func classifyReferences(ctx context.Context, snapshot *cache.Snapshot, locations []protocol.Location, dispatch map[protocol.Location]string) {
+	for _, loc := range locations {
+		if iface := referenceInterface(ctx, snapshot, loc); iface != "" {
+			dispatch[loc] = iface
+		}
+	}
+}
+
+// concreteMethodAt reports whether loc is the name of a concrete method in
+// its declaration.
+func concreteMethodAt(ctx context.Context, snapshot *cache.Snapshot, loc protocol.Location) bool {
+	info, id := identAt(ctx, snapshot, loc)
+	return id != nil && isConcreteMethod(info.Defs[id])
+}
+
+// dispatchCallRanges converts the ranges of calls from file to a concrete
+// method, tagging each as static or through an interface.
+func dispatchCallRanges(ctx context.Context, snapshot *cache.Snapshot, file protocol.DocumentURI, ranges []protocol.Range) []api.CallRange {
+	calls := buildCallRanges(file.Path(), ranges)
+	for i, rng := range ranges {
+		calls[i].Dispatch = dispatchStatic
+		if iface := referenceInterface(ctx, snapshot, protocol.Location{URI: file, Range: rng}); iface != "" {
+			calls[i].Dispatch = dispatchInterface
+			calls[i].Interface = iface
+		}
+	}
+	return calls
+}
+
+// dispatchNote describes how the calls of calls are made, as in
+// " [static and via io.Writer]", or returns "" if they are not tagged.
+func dispatchNote(calls []api.CallRange) string {
+	var (
+		static bool
+		ifaces []string
+	)
+	for _, call := range calls {
+		switch call.Dispatch {
+		case dispatchStatic:
+			static = true
+		case dispatchInterface:
+			if !slices.Contains(ifaces, call.Interface) {
+				ifaces = append(ifaces, call.Interface)
+			}
+		}
+	}
+	switch {
+	case len(ifaces) == 0 && static:
+		return " [static]"
+	case len(ifaces) == 0:
+		return ""
+	case static:
+		return " [static and via " + strings.Join(ifaces, ", ") + "]"
+	default:
+		return " [via " + strings.Join(ifaces, ", ") + "]"
+	}
+}
+
+// referenceNote describes a single reference: its kind and, if tagged, how
+// it reaches the method, as in " [call, via io.Writer]".
+func referenceNote(ref api.SymbolReference) string {
+	note := ref.Kind
+	switch ref.Dispatch {
+	case dispatchStatic:
+		note += ", static"
+	case dispatchInterface:
+		note += ", via " + ref.Interface
+	}
+	if note == "" {
+		return ""
+	}
+	return " [" + strings.TrimPrefix(note, ", ") + "]"
+}
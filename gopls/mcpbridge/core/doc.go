@@ -17,7 +17,13 @@ var docMap = map[string]string{
 
 **Input**: Use semantic locator (symbol_name + context_file). The context_file is where you see the symbol used.
 
-**Output**: Reference locations (file, line, column) plus rich symbol information.
+**Output**: Reference locations (file, line, column) plus rich symbol information. In a workspace with one view per module, references from every module that depends on the symbol's package are included.
+
+**Kinds**: Each reference is classified as read, write (assignment, increment, composite literal key, address taken), call, type (type expression or conversion) or embed (embedded field). Set only_writes or only_calls to keep those kinds, and exclude_tests or exclude_generated to drop references in _test.go or generated files.
+
+**Concrete methods**: The references include the calls through the interface methods it implements. Set include_dynamic to tag each reference static or via the interface.
+
+**Paging**: At most page_size references (default 100) are returned at a time, fewer if they would not fit in the response size limit. When there are more, pass the returned next_cursor as cursor to get the next page.
 
 **See also**: go_dryrun_rename_symbol to preview rename operations.
 `,
@@ -28,9 +34,18 @@ var docMap = map[string]string{
 
 **Output**: Unified diff showing all proposed modifications.
 
-**Workflow**: Use go_symbol_references first to assess impact, then this to preview changes.
+**Workflow**: Use go_symbol_references first to assess impact, then this to preview changes, then go_apply_rename to write them.
 
-**Note**: This is a dry run - no changes are applied.
+**Note**: This is a dry run - no changes are applied. The file_hashes in the result pin the previewed content for go_apply_rename.
+`,
+
+	ToolGoApplyRename: `Rename a symbol across all files and write the changes to disk.
+
+**When to use**: After previewing with go_dryrun_rename_symbol, to apply the rename without rewriting every file by hand.
+
+**Preconditions**: Every touched file must still have the content the rename was computed from. If expected_hashes (the file_hashes from the preview) is given, the rename must still edit exactly those files, and they must be unchanged since the preview. Otherwise nothing is written and a conflict report lists the stale files. If signature_snippet is given and the resolved symbol does not match it, nothing is written either.
+
+**Output**: The files that were modified (or moved, for package renames). The session is updated immediately, so follow-up queries see the new name.
 `,
 
 	ToolGoImplementation: `Find all implementations of an interface or all interfaces implemented by a type.
@@ -39,13 +54,16 @@ var docMap = map[string]string{
 
 **Use this instead of**: Grep + manual file reading for interface implementations.
 
+**Scope**: The interface may come from the standard library or a dependency (io.Reader, http.Handler, json.Marshaler). Its implementations are reported from the workspace only, unless include_dependencies is set. For a type, the interfaces it satisfies are reported wherever they are declared; set exclude_trivial to leave out error, fmt.Stringer and fmt.GoStringer.
+
 **Common pitfalls**:
-- Standard library interfaces (io.Reader, error, fmt.Stringer) are not supported
-- context_file should point to the definition, not usage
+- context_file should point to the definition, not usage (for an imported interface, use qualified_name such as "io.Reader")
 - For methods, set parent_scope to the interface name
 - Empty result may mean no implementations exist (not an error)
 
-**See also**: go_symbol_references for finding usages.
+**Paging**: At most page_size implementations (default 50) are returned at a time, fewer if they would not fit in the response size limit; pass next_cursor as cursor for the next page.
+
+**See also**: go_symbol_references for finding usages, go_type_hierarchy for multi-level hierarchies.
 `,
 
 	ToolGoDefinition: `Jump to the definition of a symbol.
@@ -67,7 +85,147 @@ var docMap = map[string]string{
 
 **Direction**: "incoming" (what calls this), "outgoing" (what this calls), or "both".
 
+**Depth**: max_depth (default 1, max 10) expands callers of callers or callees of callees as a tree. Functions already shown are marked and not expanded again; recursion is flagged as a cycle. max_nodes (default 100, max 500) bounds each direction and the result is marked truncated when it is reached.
+
+**Concrete methods**: Incoming calls include those through the interface methods it implements. Set include_dynamic to tag each call static or via the interface.
+
+**Paging**: With max_depth 1, at most page_size direct calls (default 100), incoming first, are returned at a time, fewer if they would not fit in the response size limit; pass next_cursor as cursor for the next page.
+
 **See also**: go_symbol_references for finding usages.
+`,
+
+	ToolGoCallPath: `Find the shortest call paths between two functions.
+
+**When to use**: Answering "can this handler ever reach that function, and through which chain?".
+
+**Use this instead of**: Expanding go_get_call_hierarchy level by level.
+
+**Algorithm**: "static" follows direct calls only; "cha" lets an interface call reach every implementation; "vta" (default) only those whose values can flow to the call. Calls are followed through workspace code, not through dependencies.
+
+**Output**: Up to max_paths (default 1, max 10) paths, shortest first. Each hop gives the called function, the call site, and whether the call is static, through an interface, or through a function value.
+
+**See also**: go_get_call_hierarchy for the direct callers and callees of one function.
+`,
+
+	ToolGoTypeHierarchy: `Get the type hierarchy for a type, expanded recursively.
+
+**When to use**: Understanding embedding chains and interface layering, e.g. "what does this type sit under and what sits under it".
+
+**Use this instead of**: Repeated go_implementation calls, which only report one level of interface satisfaction.
+
+**Direction**: "supertypes" (interfaces it implements, types it embeds), "subtypes" (types that implement or embed it), or "both".
+
+**Output**: A tree per direction. Each edge is tagged implements, implemented-by, embeds, or embedded-by. Types already shown elsewhere in the tree are marked and not expanded again.
+
+**See also**: go_implementation for a flat, single-level list with method bodies.
+`,
+
+	ToolGoCodeActions: `List the quick fixes and refactorings available at a location.
+
+**When to use**: Before fixing a compile error or restructuring code by hand: gopls may already offer the edit (add a missing import, declare an undefined name or missing methods, fill a struct literal, extract a function, inline a call, invert an if, ...).
+
+**Input**: Either a locator (the identifier it resolves to, e.g. a call site in context_file) or file_path with start_line and end_line (whole lines, 1-indexed). kinds restricts the actions, e.g. ["quickfix"] or ["refactor.extract"].
+
+**Output**: Each action's title, kind, the diagnostics it fixes, and an ID for go_dryrun_code_action. Actions that run a command gopls-mcp cannot preview are marked.
+`,
+
+	ToolGoDryrunCodeAction: `Preview the changes of a code action (DRY RUN).
+
+**When to use**: After go_code_actions, to see exactly what an action would change before writing it yourself.
+
+**Input**: The ID of an action, as returned by go_code_actions. The actions are computed again, so an ID stops working once the code at its location changes; list the actions again then.
+
+**Output**: A unified diff of every file the action would modify or create. Nothing is written to disk.
+`,
+
+	ToolGoDryrunExtract: `Preview an extract refactoring (DRY RUN).
+
+**When to use**: Moving a block of statements into a new function or method, naming an expression, or moving declarations to their own file, without hand-copying code.
+
+**Input**: file_path, kind (function, method, variable, constant or to-new-file), and the selection: start_line and end_line (whole lines, 1-indexed), or snippet (exact text, e.g. an expression; use start_line to pick among several occurrences).
+
+**Output**: A unified diff, and for function and method the free variables of the selection that became parameters. Nothing is written to disk.
+
+**Note**: A selection that cannot be extracted as the kind requested (not whole statements, not an expression, not in a method) is an error that says why.
+`,
+
+	ToolGoDryrunInline: `Preview inlining function calls (DRY RUN).
+
+**When to use**: Replacing a call by the body of the function it calls, or getting rid of a small helper or a deprecated wrapper by inlining all its calls.
+
+**Input**: A locator of the called function. With line_hint on a call, that call is inlined; at the function's declaration (or with qualified_name), every static call of it is.
+
+**Output**: A unified diff and the caveats of the inlining: arguments bound to variables (var params = args) to keep their evaluation order, parameters kept and why, calls replaced by function literals. Nothing is written to disk.
+
+**Note**: Inlining all calls leaves the declaration in place, and fails if the function is used other than by calling it.
+`,
+
+	ToolGoDryrunChangeSignature: `Preview changing the parameters of a function or method (DRY RUN).
+
+**When to use**: Removing, reordering or adding parameters of a function that has callers, such as threading a context.Context through, without editing each call by hand.
+
+**Input**: A locator of the function (its declaration or a call), and params, the new parameter list in order. Each entry is an old parameter, {"old_index": 1}, or a new one, {"field": "ctx context.Context", "default": "context.TODO()"}; an old parameter left out is removed. The type and default of a new parameter are resolved in the file that declares the function; a package they name that the file does not import, such as context, is found among the dependencies of the package and imported.
+
+**Output**: A unified diff of the declaration and every call, and the calls that could not be rewritten safely (with type errors, or uses of the function as a value), which must be fixed by hand. Nothing is written to disk.
+
+**Note**: The package that declares the function must be free of errors. Results cannot be changed.
+`,
+
+	ToolGoDryrunImplementInterface: `Preview making a type implement an interface (DRY RUN).
+
+**When to use**: After adding a method to an interface, to fix every implementation and fake it broke; or to start a new implementation or a test fake of an interface.
+
+**Input**: interface, a locator of the interface, and either concrete, a locator of the type to add the missing methods to, or fake_file, the Go file to generate a fake in (fake_name defaults to Fake<Interface>).
+
+**Output**: A unified diff and the methods added. Stubs have pointer receivers and panic("unimplemented"). A fake has, for each method M, an MFunc field it delegates to if set (zero results otherwise) and an MCalls slice recording the arguments of each call; it is safe for concurrent use. Imports are added as needed. Nothing is written to disk.
+
+**Note**: Generic interfaces are not supported, nor changes that would add an import cycle or need unexported methods of another package.
+`,
+
+	ToolGoDryrunAddTest: `Preview adding a test of a function or method (DRY RUN).
+
+**When to use**: Starting the tests of a function, or adding a case to its existing table-driven test, without getting the imports, the package clause or the receiver setup wrong.
+
+**Input**: A locator of the function or method, and optionally test_case, a table element such as {name: "empty", in: "", want: 0} to add to the existing test.
+
+**Output**: A unified diff. Without a test of the function (TestF, TestT_M for methods), a table-driven skeleton is added to the _test.go file of the function's file, created if needed as an external _test package when the function and its signature are exported. Otherwise the case, or a skeleton case with every field of the table, is added to the first table of test cases in the existing test. Nothing is written to disk.
+
+**Note**: The package must be free of errors. Fill in the TODOs of the skeleton.
+`,
+
+	ToolGoDryrunModifyTags: `Preview changing the struct tags of a struct type (DRY RUN).
+
+**When to use**: Adding json, yaml or db tags to a struct, renaming them to another case, or toggling options such as omitempty, without hand-editing backquoted tags.
+
+**Input**: A locator of the struct type, optionally fields (the names of the fields to change; default all), and at least one operation: add (tag keys, with values from the field names by transform: snakecase by default, camelcase, lispcase, pascalcase, titlecase or keep), remove (tag keys), add_options and remove_options (tag=option, e.g. json=omitempty), clear and clear_options. overwrite replaces the values of existing keys when adding; skip_unexported leaves unexported fields alone.
+
+**Output**: A unified diff and the fields whose tags change. Nothing is written to disk.
+
+**Note**: Tags are cleared before they are added, so clear with add rewrites them from scratch.
+`,
+
+	ToolGoCheckEdit: `Type-check a proposed edit to a Go file without writing it to disk.
+
+**When to use**: Before writing generated or refactored code, to find out whether it compiles without a full go build round trip.
+
+**Input**: file_path plus either new_content (the complete file) or edits (line replacements against the current content, 1-indexed, inclusive). The file does not need to exist yet.
+
+**Output**: Compiler and analyzer diagnostics for the file's package and the workspace packages that directly import it, with error/warning counts.
+
+**Note**: The edit is applied as a temporary unsaved overlay and discarded afterwards - nothing is written to disk.
+`,
+
+	ToolGoDiagnostics: `Report compile errors and analyzer findings, grouped by package.
+
+**When to use**: After editing files on disk, to find out what no longer compiles or what vet would flag.
+
+**Use this instead of**: go build and go vet, which reload the packages from scratch.
+
+**Scope**: file_path (the packages containing the file, diagnostics of that file only), package_path (the package and its tests), or the whole workspace when neither is given.
+
+**Filtering**: analyzers restricts the analyzers to those named (default: the analyzers gopls enables); type_errors_only skips analysis. min_severity is error, warning, info (default) or hint.
+
+**Output**: Diagnostics as file:line:col with severity and source, and each suggested fix as a unified diff. Fixes are never applied.
 `,
 
 	ToolGetDependencyGraph: `Get the dependency graph for a package.
@@ -75,6 +233,8 @@ var docMap = map[string]string{
 **When to use**: Understanding architectural relationships, analyzing coupling, visualizing the package's place in the codebase.
 
 **Output**: Both dependencies (what it imports) and dependents (what imports it).
+
+**Paging**: At most page_size packages (default 200), dependencies first, are returned at a time, fewer if they would not fit in the response size limit; pass next_cursor as cursor for the next page.
 `,
 
 	ToolListTools: `List all available semantic analysis tools with documentation.
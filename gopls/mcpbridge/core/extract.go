@@ -0,0 +1,119 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_dryrun_extract =====
+// Origin: gopls/internal/golang/extract.go and extracttofile.go, via
+// golang.LLMExtract
+
+func handleGoDryrunExtract(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IExtractParams) (*mcp.CallToolResult, *api.OExtractResult, error) {
+	if !filepath.IsAbs(input.FilePath) {
+		return nil, nil, fmt.Errorf("file_path must be absolute: %s", input.FilePath)
+	}
+	if input.Snippet == "" && input.StartLine < 1 {
+		return nil, nil, fmt.Errorf("either snippet or start_line is required to select the code to extract")
+	}
+
+	snapshot, release, err := h.snapshotForDir(filepath.Dir(input.FilePath))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(input.FilePath))
+	if err != nil {
+		return nil, nil, err
+	}
+	var rng protocol.Range
+	if input.Snippet != "" {
+		rng, err = snippetRange(fh, input.Snippet, input.StartLine)
+	} else {
+		rng, err = lineRange(fh, input.StartLine, cmp.Or(input.EndLine, input.StartLine))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes, params, err := golang.LLMExtract(ctx, snapshot, fh, rng, input.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result := &api.OExtractResult{
+		Kind:         input.Kind,
+		Diff:         diff,
+		FilesChanged: changedFiles(changes),
+		Parameters:   params,
+	}
+	result.Summary = formatExtract(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// snippetRange returns the range of the only occurrence of snippet in fh,
+// or of the one that starts on line if line is positive.
+func snippetRange(fh file.Handle, snippet string, line int) (protocol.Range, error) {
+	content, err := fh.Content()
+	if err != nil {
+		return protocol.Range{}, err
+	}
+	m := protocol.NewMapper(fh.URI(), content)
+
+	var offsets []int
+	for i := 0; ; {
+		j := strings.Index(string(content[i:]), snippet)
+		if j < 0 {
+			break
+		}
+		offset := i + j
+		i = offset + 1
+		if line > 0 {
+			pos, err := m.OffsetPosition(offset)
+			if err != nil || int(pos.Line)+1 != line {
+				continue
+			}
+		}
+		offsets = append(offsets, offset)
+	}
+	switch {
+	case len(offsets) == 0 && line > 0:
+		return protocol.Range{}, fmt.Errorf("snippet not found on line %d of %s", line, fh.URI().Path())
+	case len(offsets) == 0:
+		return protocol.Range{}, fmt.Errorf("snippet not found in %s", fh.URI().Path())
+	case len(offsets) > 1:
+		return protocol.Range{}, fmt.Errorf("snippet occurs %d times in %s; set start_line to the line of the one to extract", len(offsets), fh.URI().Path())
+	}
+	return m.OffsetRange(offsets[0], offsets[0]+len(snippet))
+}
+
+func formatExtract(result *api.OExtractResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "=== DRY RUN: Extract %s Preview ===\n", result.Kind)
+	if len(result.Parameters) > 0 {
+		b.WriteString("Free variables passed as parameters:\n")
+		for _, p := range result.Parameters {
+			fmt.Fprintf(&b, "  %s %s\n", p.Name, p.Type)
+		}
+	}
+	b.WriteString("NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&b, "%s\n", result.Diff)
+
+	return b.String()
+}
@@ -1,11 +1,15 @@
 package core
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/golang"
@@ -54,6 +58,73 @@ func pkgPathForFile(ctx context.Context, snapshot *cache.Snapshot, uri protocol.
 	return ""
 }
 
+// resolutionWarnings converts an optional resolver warning into the list form
+// used by tool results.
+func resolutionWarnings(w *api.ResolutionWarning) []api.ResolutionWarning {
+	if w == nil {
+		return nil
+	}
+	return []api.ResolutionWarning{*w}
+}
+
+// formatResolutionWarnings renders warnings as a preamble for a tool summary,
+// so that the caller sees them before the results.
+func formatResolutionWarnings(warnings []api.ResolutionWarning) string {
+	var b strings.Builder
+	for _, w := range warnings {
+		fmt.Fprintf(&b, "WARNING (%s): %s\n", w.Code, w.Message)
+		if w.ExpectedSnippet != "" {
+			fmt.Fprintf(&b, "  signature_snippet: %s\n", w.ExpectedSnippet)
+		}
+		if w.ActualSignature != "" {
+			fmt.Fprintf(&b, "  resolved:          %s\n", w.ActualSignature)
+		}
+		if w.Code == api.WarningSignatureMismatch {
+			fmt.Fprintf(&b, "  similarity:        %.2f\n", w.Similarity)
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatResolutionError renders a symbol resolution failure for the agent:
+// the error itself, the candidates or suggestions to retry with, and the
+// structured payload as JSON.
+func formatResolutionError(err error, rerr *api.ResolutionError) string {
+	var b strings.Builder
+	b.WriteString(err.Error())
+	b.WriteString("\n")
+
+	writeSymbols := func(title string, syms []api.Symbol) {
+		if len(syms) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s\n", title)
+		for _, sym := range syms {
+			fmt.Fprintf(&b, "  %s %s", sym.Kind, sym.Name)
+			if scope := cmp.Or(sym.Receiver, sym.Parent); scope != "" {
+				fmt.Fprintf(&b, " (parent_scope: %s)", scope)
+			}
+			if sym.FilePath != "" {
+				fmt.Fprintf(&b, " at %s:%d", sym.FilePath, sym.Line)
+			}
+			if sym.Signature != "" {
+				fmt.Fprintf(&b, ": %s", sym.Signature)
+			}
+			b.WriteString("\n")
+		}
+	}
+	writeSymbols("Candidates:", rerr.Candidates)
+	writeSymbols("Did you mean:", rerr.Suggestions)
+
+	if data, jerr := json.Marshal(rerr); jerr == nil {
+		fmt.Fprintf(&b, "\nError details (JSON):\n%s\n", data)
+	}
+	return b.String()
+}
+
 // buildCallRanges converts protocol ranges to api.CallRange slice.
 func buildCallRanges(file string, ranges []protocol.Range) []api.CallRange {
 	callRanges := make([]api.CallRange, 0, len(ranges))
@@ -68,15 +139,19 @@ func buildCallRanges(file string, ranges []protocol.Range) []api.CallRange {
 }
 
 // formatCallHierarchySection formats a list of call hierarchy entries into a summary string.
-func formatCallHierarchySection(title string, calls []api.CallHierarchyCall) string {
-	if len(calls) == 0 {
+// calls is the page of the total entries that starts at entry first.
+func formatCallHierarchySection(title string, calls []api.CallHierarchyCall, first, total int) string {
+	if total == 0 {
 		return title + ": None\n"
 	}
+	if len(calls) == 0 {
+		return "" // on another page
+	}
 
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("%s (%d):\n", title, len(calls)))
+	b.WriteString(fmt.Sprintf("%s (%d):\n", title, total))
 	for i, call := range calls {
-		b.WriteString(fmt.Sprintf("  %d. %s at %s:%d\n", i+1, call.From.Name, call.From.FilePath, call.From.Line))
+		b.WriteString(fmt.Sprintf("  %d. %s at %s:%d%s\n", first+i+1, call.From.Name, call.From.FilePath, call.From.Line, dispatchNote(call.CallRanges)))
 
 		if call.From.PackagePath != "" {
 			b.WriteString(fmt.Sprintf("     package: %s\n", call.From.PackagePath))
@@ -110,10 +185,15 @@ func formatCallHierarchySection(title string, calls []api.CallHierarchyCall) str
 // Origin: gopls/internal/golang/definition.go Definition()
 
 func handleGoDefinition(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IDefinitionParams) (*mcp.CallToolResult, *api.ODefinitionResult, error) {
-	dir := filepath.Dir(input.Locator.ContextFile)
-	snapshot, release, err := h.snapshotForDir(dir)
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", dir, err)
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", viewDir, err)
 	}
 	defer release()
 
@@ -126,8 +206,9 @@ func handleGoDefinition(ctx context.Context, h *Handler, req *mcp.CallToolReques
 	}
 
 	if len(info.Locations) == 0 {
-		summary := fmt.Sprintf("No definition found for symbol '%s' in %s", input.Locator.SymbolName, input.Locator.ContextFile)
-		result := &api.ODefinitionResult{Summary: summary}
+		warnings := resolutionWarnings(info.Warning)
+		summary := formatResolutionWarnings(warnings) + fmt.Sprintf("No definition found for symbol '%s' in %s", input.Locator.SymbolName, input.Locator.ContextFile)
+		result := &api.ODefinitionResult{Summary: summary, Warnings: warnings}
 		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary}}}, result, nil
 	}
 
@@ -156,9 +237,13 @@ func handleGoDefinition(ctx context.Context, h *Handler, req *mcp.CallToolReques
 		summary += golang.FormatSymbolSummary(sym)
 	}
 
+	warnings := resolutionWarnings(info.Warning)
+	summary = formatResolutionWarnings(warnings) + summary
+
 	result := &api.ODefinitionResult{
-		Symbol:  sym,
-		Summary: summary,
+		Symbol:   sym,
+		Summary:  summary,
+		Warnings: warnings,
 	}
 	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary}}}, result, nil
 }
@@ -167,7 +252,21 @@ func handleGoDefinition(ctx context.Context, h *Handler, req *mcp.CallToolReques
 // Origin: gopls/internal/mcp/symbol_references.go symbolReferencesHandler()
 
 func handleGoSymbolReferences(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.ISymbolReferencesParams) (*mcp.CallToolResult, *api.OSymbolReferencesResult, error) {
-	snapshot, release, err := h.snapshot()
+	if input.Cursor != "" {
+		result, err := nextPage[api.OSymbolReferencesResult](h, ToolGoSymbolReferences, input.Cursor, input.PageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -204,6 +303,49 @@ func handleGoSymbolReferences(ctx context.Context, h *Handler, req *mcp.CallTool
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find references: %w", err)
 	}
+	// For a concrete method, the references include those to the interface
+	// methods it implements; if asked, find out which, in the view that saw
+	// them.
+	tagged := isConcreteMethod(nodeResult.Object) && input.IncludeDynamic
+	dispatch := make(map[protocol.Location]string)
+	kinds := make(map[protocol.Location]string)
+	if tagged {
+		classifyReferences(ctx, snapshot, locations, dispatch)
+	}
+	classifyReferenceKinds(ctx, snapshot, locations, kinds)
+	h.forEachDependentView(ctx, snapshot, input.Locator, func(other *cache.Snapshot, decl api.SymbolLocator) error {
+		more, err := declarationReferences(ctx, other, decl)
+		if tagged {
+			classifyReferences(ctx, other, more, dispatch)
+		}
+		classifyReferenceKinds(ctx, other, more, kinds)
+		locations = append(locations, more...)
+		return err
+	})
+	locations = mergeLocations(locations)
+	found := len(locations)
+	locations = slices.DeleteFunc(locations, func(loc protocol.Location) bool {
+		return !keepReference(ctx, snapshot, input, loc, kinds[loc])
+	})
+	excluded := found - len(locations)
+
+	references := make([]api.SymbolReference, 0, len(locations))
+	for _, loc := range locations {
+		ref := api.SymbolReference{
+			File:   loc.URI.Path(),
+			Line:   int(loc.Range.Start.Line + 1),
+			Column: int(loc.Range.Start.Character + 1),
+			Kind:   kinds[loc],
+		}
+		if tagged {
+			ref.Dispatch = dispatchStatic
+			if iface := dispatch[loc]; iface != "" {
+				ref.Dispatch = dispatchInterface
+				ref.Interface = iface
+			}
+		}
+		references = append(references, ref)
+	}
 
 	var symbols []*api.Symbol
 	if defLocs, err := golang.Definition(ctx, snapshot, fh, protocol.Range{Start: position, End: position}); err == nil && len(defLocs) > 0 {
@@ -212,16 +354,38 @@ func handleGoSymbolReferences(ctx context.Context, h *Handler, req *mcp.CallTool
 		}
 	}
 
-	var summary strings.Builder
+	warnings := resolutionWarnings(nodeResult.Warning)
+
+	// The header and footer frame every page; entries holds the lines of
+	// each reference.
+	var header, footer strings.Builder
+	entries := make([]string, len(locations))
+	header.WriteString(formatResolutionWarnings(warnings))
 	if len(locations) == 0 {
-		summary.WriteString(fmt.Sprintf("No references found for %q in %s",
+		header.WriteString(fmt.Sprintf("No references found for %q in %s",
 			input.Locator.SymbolName, input.Locator.ContextFile))
+		if excluded > 0 {
+			header.WriteString(fmt.Sprintf(" (%d excluded by filters)", excluded))
+		}
 	} else {
-		summary.WriteString(fmt.Sprintf("Found %d reference(s) to %q:\n",
-			len(locations), input.Locator.SymbolName))
+		header.WriteString(fmt.Sprintf("Found %d reference(s) to %q", len(locations), input.Locator.SymbolName))
+		if tagged {
+			dynamic := 0
+			for _, ref := range references {
+				if ref.Dispatch == dispatchInterface {
+					dynamic++
+				}
+			}
+			header.WriteString(fmt.Sprintf(" (%d static, %d via interface)", len(references)-dynamic, dynamic))
+		}
+		if excluded > 0 {
+			header.WriteString(fmt.Sprintf(" (%d excluded by filters)", excluded))
+		}
+		header.WriteString(":\n")
 		for i, loc := range locations {
-			summary.WriteString(fmt.Sprintf("%d. %s:%d:%d",
-				i+1, loc.URI.Path(), loc.Range.Start.Line+1, loc.Range.Start.Character+1))
+			var entry strings.Builder
+			entry.WriteString(fmt.Sprintf("%d. %s:%d:%d%s",
+				i+1, loc.URI.Path(), loc.Range.Start.Line+1, loc.Range.Start.Character+1, referenceNote(references[i])))
 
 			fh, err := snapshot.ReadFile(ctx, loc.URI)
 			if err == nil {
@@ -232,57 +396,97 @@ func handleGoSymbolReferences(ctx context.Context, h *Handler, req *mcp.CallTool
 					if lineIdx >= 0 && lineIdx < len(lines) {
 						line := strings.TrimSpace(lines[lineIdx])
 						if len(line) > 0 && len(line) < 100 {
-							summary.WriteString(fmt.Sprintf("\n   %s", line))
+							entry.WriteString(fmt.Sprintf("\n   %s", line))
 						}
 					}
 				}
 			}
-			summary.WriteString("\n")
+			entry.WriteString("\n")
+			entries[i] = entry.String()
 		}
 		if len(symbols) > 0 {
 			sym := symbols[0]
 			if sym.Signature != "" {
-				summary.WriteString(fmt.Sprintf("\nReferenced Symbol: %s\n", sym.Signature))
+				footer.WriteString(fmt.Sprintf("\nReferenced Symbol: %s\n", sym.Signature))
 			}
 			if sym.Doc != "" {
-				summary.WriteString(fmt.Sprintf("Documentation: %s\n", sym.Doc))
+				footer.WriteString(fmt.Sprintf("Documentation: %s\n", sym.Doc))
 			}
 		}
 	}
 
-	result := &api.OSymbolReferencesResult{
-		Summary:    summary.String(),
-		Symbols:    symbols,
-		TotalCount: len(locations),
-		Returned:   len(locations),
-		Truncated:  false,
+	page := func(start, end int, next string) *api.OSymbolReferencesResult {
+		var summary strings.Builder
+		summary.WriteString(pageNote(start, end, len(references), next))
+		summary.WriteString(header.String())
+		for _, entry := range entries[start:end] {
+			summary.WriteString(entry)
+		}
+		summary.WriteString(footer.String())
+		result := &api.OSymbolReferencesResult{
+			Summary:    summary.String(),
+			Symbols:    symbols,
+			References: references[start:end],
+			TotalCount: len(references),
+			Returned:   end - start,
+			Truncated:  next != "",
+			NextCursor: next,
+			Warnings:   warnings,
+		}
+		if next != "" {
+			result.Hint = "pass next_cursor as cursor to get the next page"
+		}
+		return result
 	}
+	result := firstPage(h, ToolGoSymbolReferences, snapshot, len(references), pageSize(input.PageSize, defaultReferencesPageSize), page)
 
-	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary.String()}}}, result, nil
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
 }
 
 // ===== go_dryrun_rename_symbol =====
 // Origin: gopls/internal/mcp/rename_symbol.go renameSymbolHandler()
 
 func handleGoRenameSymbol(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IRenameSymbolParams) (*mcp.CallToolResult, *api.ORenameSymbolResult, error) {
-	snapshot, release, err := h.snapshot()
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer release()
 
-	unifiedDiff, lineChanges, err := golang.LLMRename(ctx, snapshot, input.Locator, input.NewName)
+	changes, warning, err := h.renameChanges(ctx, snapshot, input.Locator, input.NewName)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to compute rename: %w", err)
 	}
+	unifiedDiff, lineChanges, err := golang.FormatRenameChanges(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := fileHashes(ctx, snapshot, lineChanges)
+	warnings := resolutionWarnings(warning)
 
 	var summary strings.Builder
+	summary.WriteString(formatResolutionWarnings(warnings))
 	summary.WriteString(fmt.Sprintf("DRY RUN: Preview rename %q to %q\n\n", input.Locator.SymbolName, input.NewName))
 	summary.WriteString(unifiedDiff)
+	if len(hashes) > 0 {
+		summary.WriteString("\nFile hashes (pass as expected_hashes to go_apply_rename to apply exactly this preview):\n")
+		for _, f := range slices.Sorted(maps.Keys(hashes)) {
+			fmt.Fprintf(&summary, "  %s: %s\n", f, hashes[f])
+		}
+	}
 
 	result := &api.ORenameSymbolResult{
-		Summary: summary.String(),
-		Changes: lineChanges,
+		Summary:    summary.String(),
+		Changes:    lineChanges,
+		FileHashes: hashes,
+		Warnings:   warnings,
 	}
 
 	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary.String()}}}, result, nil
@@ -292,27 +496,59 @@ func handleGoRenameSymbol(ctx context.Context, h *Handler, req *mcp.CallToolRequ
 // Origin: gopls/internal/golang/implementation.go Implementation()
 
 func handleGoImplementation(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IImplementationParams) (*mcp.CallToolResult, *api.OImplementationResult, error) {
-	snapshot, release, err := h.snapshot()
+	if input.Cursor != "" {
+		result, err := nextPage[api.OImplementationResult](h, ToolGoImplementation, input.Cursor, input.PageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
 	if err != nil {
 		return nil, nil, err
 	}
 	defer release()
 
-	sourceContexts, err := golang.LLMImplementation(ctx, snapshot, input.Locator)
+	// Same as golang.LLMImplementation, but keeps the resolution warning.
+	options := golang.ResolveOptions{
+		FindImplementations: true,
+		IncludeDocs:         true,
+		IncludeBodies:       true,
+		IncludeDependencies: input.IncludeDependencies,
+		ExcludeTrivial:      input.ExcludeTrivial,
+	}
+	info, err := golang.ResolveSymbol(ctx, snapshot, input.Locator, options)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to find implementations for '%s': %w", input.Locator.SymbolName, err)
 	}
+	sourceContexts := info.Implementations
+	h.forEachDependentView(ctx, snapshot, input.Locator, func(other *cache.Snapshot, decl api.SymbolLocator) error {
+		more, err := golang.ResolveSymbol(ctx, other, decl, options)
+		if err != nil {
+			return err
+		}
+		sourceContexts = mergeSourceContexts(sourceContexts, more.Implementations)
+		return nil
+	})
 
 	symbols := make([]*api.Symbol, 0, len(sourceContexts))
 
 	for _, srcCtx := range sourceContexts {
 		sym := &api.Symbol{
-			Name:      srcCtx.Symbol,
-			Kind:      api.SymbolKind(srcCtx.Kind),
-			Signature: srcCtx.Signature,
-			FilePath:  srcCtx.File,
-			Line:      srcCtx.StartLine,
-			Doc:       srcCtx.DocComment,
+			Name:        srcCtx.Symbol,
+			Kind:        api.SymbolKind(srcCtx.Kind),
+			Signature:   srcCtx.Signature,
+			PackagePath: srcCtx.PackagePath,
+			FilePath:    srcCtx.File,
+			Line:        srcCtx.StartLine,
+			Doc:         srcCtx.DocComment,
 		}
 		if input.IncludeBody {
 			sym.Body = srcCtx.Snippet
@@ -320,27 +556,36 @@ func handleGoImplementation(ctx context.Context, h *Handler, req *mcp.CallToolRe
 		symbols = append(symbols, sym)
 	}
 
-	var summary string
+	warnings := resolutionWarnings(info.Warning)
+	header := formatResolutionWarnings(warnings)
 	if len(symbols) == 0 {
-		summary = fmt.Sprintf("No implementations found for symbol '%s' in %s",
+		header += fmt.Sprintf("No implementations found for symbol '%s' in %s",
 			input.Locator.SymbolName, input.Locator.ContextFile)
 	} else {
-		summary = fmt.Sprintf("Found %d implementation(s) for symbol '%s':\n",
+		header += fmt.Sprintf("Found %d implementation(s) for symbol '%s':\n",
 			len(symbols), input.Locator.SymbolName)
-		for i, sym := range symbols {
+	}
+
+	page := func(start, end int, next string) *api.OImplementationResult {
+		summary := pageNote(start, end, len(symbols), next) + header
+		for i := start; i < end; i++ {
+			sym := symbols[i]
 			summary += fmt.Sprintf("%d. %s at %s:%d",
 				i+1, sym.Name, sym.FilePath, sym.Line)
 			summary += golang.FormatSymbolSummary(sym)
 			summary += "\n"
 		}
+		return &api.OImplementationResult{
+			Symbols:    symbols[start:end],
+			Summary:    summary,
+			TotalCount: len(symbols),
+			NextCursor: next,
+			Warnings:   warnings,
+		}
 	}
+	result := firstPage(h, ToolGoImplementation, snapshot, len(symbols), pageSize(input.PageSize, defaultImplementationsPageSize), page)
 
-	result := &api.OImplementationResult{
-		Symbols: symbols,
-		Summary: summary,
-	}
-
-	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary}}}, result, nil
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
 }
 
 // handleListTools returns documentation for all available MCP tools.
@@ -363,12 +608,15 @@ func handleListTools(ctx context.Context, h *Handler, req *mcp.CallToolRequest,
 		}
 
 		if input.IncludeInputSchema || input.IncludeOutputSchema {
-			schemas := getToolSchemas(name)
+			in, out, err := tool.Schemas()
+			if err != nil {
+				return nil, nil, fmt.Errorf("schemas of %s: %w", name, err)
+			}
 			if input.IncludeInputSchema {
-				doc.InputSchema = schemas["input"]
+				doc.InputSchema = schemaMap(in)
 			}
 			if input.IncludeOutputSchema {
-				doc.OutputSchema = schemas["output"]
+				doc.OutputSchema = schemaMap(out)
 			}
 		}
 
@@ -414,88 +662,65 @@ func categorizeTool(name string) string {
 	switch name {
 	case "go_list_tools":
 		return "meta"
-	case "go_get_dependency_graph":
+	case "go_get_dependency_graph",
+		"go_check_edit",
+		"go_diagnostics":
 		return "analysis"
 	case "go_symbol_references",
 		"go_implementation",
 		"go_definition",
-		"go_get_call_hierarchy":
+		"go_get_call_hierarchy",
+		"go_call_path",
+		"go_type_hierarchy":
 		return "navigation"
-	case "go_dryrun_rename_symbol":
+	case "go_dryrun_rename_symbol",
+		"go_apply_rename",
+		"go_code_actions",
+		"go_dryrun_code_action",
+		"go_dryrun_extract",
+		"go_dryrun_inline",
+		"go_dryrun_change_signature",
+		"go_dryrun_implement_interface",
+		"go_dryrun_add_test",
+		"go_dryrun_modify_tags":
 		return "refactoring"
 	default:
 		return "other"
 	}
 }
 
-// getToolSchemas returns the input and output schemas for a tool.
-func getToolSchemas(toolName string) map[string]map[string]any {
-	schemas := map[string]map[string]any{}
-
-	switch toolName {
-	case "go_implementation":
-		schemas["input"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"locator": map[string]any{
-					"type":        "object",
-					"description": "Semantic symbol locator",
-				},
-			},
-		}
-		schemas["output"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"symbols": map[string]any{
-					"type":        "array",
-					"description": "Implementation symbols",
-				},
-			},
-		}
-
-	case "go_list_tools":
-		schemas["input"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"includeInputSchema": map[string]any{
-					"type":        "boolean",
-					"description": "Include input schemas",
-				},
-				"includeOutputSchema": map[string]any{
-					"type":        "boolean",
-					"description": "Include output schemas",
-				},
-			},
-		}
-		schemas["output"] = map[string]any{
-			"type": "object",
-			"properties": map[string]any{
-				"tools": map[string]any{
-					"type":        "array",
-					"description": "Tool documentation",
-				},
-			},
-		}
-
-	default:
-		schemas["input"] = map[string]any{
-			"type":        "object",
-			"description": "Tool-specific input parameters",
-		}
-		schemas["output"] = map[string]any{
-			"type":        "object",
-			"description": "Tool-specific output",
-		}
+// schemaMap returns the JSON object form of schema.
+func schemaMap(schema *jsonschema.Schema) map[string]any {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil
 	}
-
-	return schemas
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil
+	}
+	return m
 }
 
 // ===== go_get_call_hierarchy =====
 // New tool for call hierarchy analysis
 
 func handleGoCallHierarchy(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.ICallHierarchyParams) (*mcp.CallToolResult, *api.OCallHierarchyResult, error) {
-	snapshot, release, err := h.snapshotForDir(input.Cwd)
+	if input.Cursor != "" {
+		result, err := nextPage[api.OCallHierarchyResult](h, ToolGetCallHierarchy, input.Cursor, input.PageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -554,53 +779,76 @@ func handleGoCallHierarchy(ctx context.Context, h *Handler, req *mcp.CallToolReq
 	symbol := buildRichSymbol(ctx, snapshot, item.Name, item.Kind, item.URI, item.Range, pkgPath)
 
 	result := &api.OCallHierarchyResult{
-		Symbol: symbol,
+		Symbol:   symbol,
+		Warnings: resolutionWarnings(nodeResult.Warning),
 	}
 
 	var summary strings.Builder
+	summary.WriteString(formatResolutionWarnings(result.Warnings))
 	summary.WriteString(fmt.Sprintf("Call hierarchy for %s at %s:%d\n\n", symbol.Name, symbol.FilePath, symbol.Line))
 
-	if direction == "incoming" || direction == "both" {
-		incoming, err := golang.IncomingCalls(ctx, snapshot, fh, protocol.Range{Start: position, End: position})
-		if err == nil && len(incoming) > 0 {
-			incomingCalls := make([]api.CallHierarchyCall, 0, len(incoming))
-			for _, call := range incoming {
-				fromPkgPath := pkgPathForFile(ctx, snapshot, call.From.URI)
-				from := buildRichSymbol(ctx, snapshot, call.From.Name, call.From.Kind, call.From.URI, call.From.Range, fromPkgPath)
-
-				incomingCalls = append(incomingCalls, api.CallHierarchyCall{
-					From:       from,
-					CallRanges: buildCallRanges(call.From.URI.Path(), call.FromRanges),
-				})
-			}
-			result.IncomingCalls = incomingCalls
-			result.TotalIncoming = len(incomingCalls)
+	maxDepth := max(input.MaxDepth, 1)
+	maxDepth = min(maxDepth, maxCallHierarchyDepth)
+	maxNodes := input.MaxNodes
+	if maxNodes <= 0 {
+		maxNodes = defaultCallHierarchyNodes
+	}
+	maxNodes = min(maxNodes, maxCallHierarchyNodes)
+	w := &callHierarchyWalker{ctx: ctx, snapshot: snapshot, maxDepth: maxDepth, maxNodes: maxNodes, includeDynamic: input.IncludeDynamic}
+
+	// expand returns the direct calls in one direction and, when max_depth
+	// asks for more than one level, their recursive expansion.
+	expand := func(incoming bool) ([]api.CallHierarchyCall, []api.CallHierarchyNode) {
+		edges := w.calls(item, incoming)
+		calls := w.direct(edges)
+		if maxDepth == 1 {
+			return calls, nil
 		}
+		tree, truncated := w.walk(item, edges, incoming)
+		result.Truncated = result.Truncated || truncated
+		return calls, tree
+	}
+	if direction == "incoming" || direction == "both" {
+		result.IncomingCalls, result.IncomingTree = expand(true)
+		result.TotalIncoming = len(result.IncomingCalls)
 	}
-
 	if direction == "outgoing" || direction == "both" {
-		outgoing, err := golang.OutgoingCalls(ctx, snapshot, fh, position)
-		if err == nil && len(outgoing) > 0 {
-			outgoingCalls := make([]api.CallHierarchyCall, 0, len(outgoing))
-			for _, call := range outgoing {
-				toPkgPath := pkgPathForFile(ctx, snapshot, call.To.URI)
-				to := buildRichSymbol(ctx, snapshot, call.To.Name, call.To.Kind, call.To.URI, call.To.Range, toPkgPath)
-
-				outgoingCalls = append(outgoingCalls, api.CallHierarchyCall{
-					From:       to,
-					CallRanges: buildCallRanges(symbol.FilePath, call.FromRanges),
-				})
-			}
-			result.OutgoingCalls = outgoingCalls
-			result.TotalOutgoing = len(outgoingCalls)
-		}
+		result.OutgoingCalls, result.OutgoingTree = expand(false)
+		result.TotalOutgoing = len(result.OutgoingCalls)
 	}
 
-	summary.WriteString(formatCallHierarchySection("Incoming Calls", result.IncomingCalls))
-	summary.WriteString("\n")
-	summary.WriteString(formatCallHierarchySection("Outgoing Calls", result.OutgoingCalls))
-
-	result.Summary = summary.String()
-
-	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary.String()}}}, result, nil
+	if maxDepth > 1 {
+		// The trees are bounded by max_nodes instead of paged.
+		summary.WriteString(formatCallHierarchyTree("Incoming Calls", result.IncomingTree))
+		summary.WriteString("\n")
+		summary.WriteString(formatCallHierarchyTree("Outgoing Calls", result.OutgoingTree))
+		if result.Truncated {
+			fmt.Fprintf(&summary, "\n(Stopped at max_nodes=%d; raise max_nodes or lower max_depth to see the rest.)\n", maxNodes)
+		}
+		result.Summary = summary.String()
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
+	// The pages run through the incoming calls, then the outgoing ones.
+	header := summary.String()
+	full := *result
+	nIn := len(full.IncomingCalls)
+	page := func(start, end int, next string) *api.OCallHierarchyResult {
+		result := full
+		result.IncomingCalls = full.IncomingCalls[min(start, nIn):min(end, nIn)]
+		result.OutgoingCalls = full.OutgoingCalls[max(start-nIn, 0):max(end-nIn, 0)]
+		result.NextCursor = next
+
+		var summary strings.Builder
+		summary.WriteString(pageNote(start, end, full.TotalIncoming+full.TotalOutgoing, next))
+		summary.WriteString(header)
+		summary.WriteString(formatCallHierarchySection("Incoming Calls", result.IncomingCalls, min(start, nIn), full.TotalIncoming))
+		summary.WriteString("\n")
+		summary.WriteString(formatCallHierarchySection("Outgoing Calls", result.OutgoingCalls, max(start-nIn, 0), full.TotalOutgoing))
+		result.Summary = summary.String()
+		return &result
+	}
+	result = firstPage(h, ToolGetCallHierarchy, snapshot, full.TotalIncoming+full.TotalOutgoing, pageSize(input.PageSize, defaultCallsPageSize), page)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
 }
@@ -3,12 +3,15 @@ package core
 import (
 	"context"
 	"io"
+	"maps"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
 )
 
 // mockCloser tracks Close() calls for testing.
@@ -126,6 +129,49 @@ func TestHandler_IdleTimeout_ReleasesResources(t *testing.T) {
 	}
 }
 
+// TestHandler_IdleTimeout_KeptForSubscribers verifies that the session and
+// the watcher outlive the idle timeout while a client is subscribed to a
+// resource, and are released once it disconnects.
+func TestHandler_IdleTimeout_KeptForSubscribers(t *testing.T) {
+	const timeout = 60 * time.Millisecond
+	h, watcher, _ := newTestHandler(t, timeout)
+	released := func() bool {
+		h.initMu.Lock()
+		defer h.initMu.Unlock()
+		return h.session == nil
+	}
+
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, h.ServerOptions())
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	st, ct := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Subscribe(ctx, &mcp.SubscribeParams{URI: packageResourcePrefix + "example.com/a"}); err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	time.Sleep(timeout * 3)
+	if released() || watcher.closed.Load() {
+		t.Fatal("resources were released while a client is subscribed")
+	}
+
+	cs.Close()
+	for deadline := time.Now().Add(5 * time.Second); !released(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("resources were not released after the subscriber disconnected")
+		}
+	}
+	if !watcher.closed.Load() {
+		t.Error("watcher.Close() should have been called after the subscriber disconnected")
+	}
+}
+
 func TestHandler_IdleTimeout_ReinitOnNextCall(t *testing.T) {
 	const timeout = 60 * time.Millisecond
 	h, _, initCount := newTestHandler(t, timeout)
@@ -213,3 +259,123 @@ func TestConfig_IdleTimeoutJSON(t *testing.T) {
 		}
 	}
 }
+
+// TestHandler_Roots verifies that client roots become folders of the session,
+// that folders follow root changes, and that they survive an idle shutdown.
+func TestHandler_Roots(t *testing.T) {
+	h, _, _ := newTestHandler(t, time.Minute)
+	live := trackFolders(h)
+	check := func(want map[string]int) {
+		t.Helper()
+		if got := live(); !maps.Equal(got, want) {
+			t.Errorf("live folders = %v, want %v", got, want)
+		}
+	}
+
+	// Roots reported before the session exists are applied on init.
+	h.setRoots("client1", []string{"/ws/a", "/ws/b"})
+	check(nil)
+	if err := h.ensureSession(context.Background()); err != nil {
+		t.Fatalf("ensureSession failed: %v", err)
+	}
+	check(map[string]int{"/ws/a": 1, "/ws/b": 1})
+
+	// A second client shares /ws/b; its folder is not duplicated.
+	h.setRoots("client2", []string{"/ws/b", "/ws/c"})
+	check(map[string]int{"/ws/a": 1, "/ws/b": 1, "/ws/c": 1})
+
+	// /ws/b stays while client2 still has it.
+	h.setRoots("client1", []string{"/ws/a"})
+	check(map[string]int{"/ws/a": 1, "/ws/b": 1, "/ws/c": 1})
+	h.setRoots("client2", nil)
+	check(map[string]int{"/ws/a": 1})
+
+	// A root inside another one is covered by the outer folder, until
+	// that one goes away.
+	h.setRoots("client2", []string{"/ws/a/sub"})
+	check(map[string]int{"/ws/a": 1})
+	h.setRoots("client1", nil)
+	check(map[string]int{"/ws/a/sub": 1})
+	h.setRoots("client1", []string{"/ws/a"})
+	h.setRoots("client2", nil)
+	check(map[string]int{"/ws/a": 1})
+
+	// Idle shutdown releases the folders; the next session restores them.
+	h.shutdownResources()
+	check(nil)
+	if err := h.ensureSession(context.Background()); err != nil {
+		t.Fatalf("ensureSession failed: %v", err)
+	}
+	check(map[string]int{"/ws/a": 1})
+}
+
+// TestHandler_RootsOfDisconnectedClient verifies that the folders of a
+// client's roots are removed when the client disconnects.
+func TestHandler_RootsOfDisconnectedClient(t *testing.T) {
+	h, _, _ := newTestHandler(t, time.Minute)
+	live := trackFolders(h)
+	if err := h.ensureSession(context.Background()); err != nil {
+		t.Fatalf("ensureSession failed: %v", err)
+	}
+	waitFor := func(want map[string]int) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if maps.Equal(live(), want) {
+				return
+			}
+		}
+		t.Fatalf("live folders = %v, want %v", live(), want)
+	}
+
+	ctx := context.Background()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, h.ServerOptions())
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil)
+	client.AddRoots(&mcp.Root{URI: string(protocol.URIFromPath("/ws/a"))})
+	st, ct := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitFor(map[string]int{"/ws/a": 1})
+
+	client.AddRoots(&mcp.Root{URI: string(protocol.URIFromPath("/ws/b"))})
+	waitFor(map[string]int{"/ws/a": 1, "/ws/b": 1})
+
+	cs.Close()
+	waitFor(nil)
+	h.rootsMu.Lock()
+	defer h.rootsMu.Unlock()
+	if len(h.rootsChanged) > 0 {
+		t.Errorf("roots of a disconnected client are still followed: %v", h.rootsChanged)
+	}
+}
+
+// trackFolders makes h record its workspace folders instead of creating
+// them, and returns a function that reports the number of live copies of
+// each folder that has any.
+func trackFolders(h *Handler) func() map[string]int {
+	var (
+		mu   sync.Mutex
+		live = make(map[string]int) // folder dir -> number of live copies
+	)
+	h.addFolder = func(ctx context.Context, session *cache.Session, dir string) (func(), error) {
+		mu.Lock()
+		defer mu.Unlock()
+		live[dir]++
+		return func() {
+			mu.Lock()
+			defer mu.Unlock()
+			live[dir]--
+		}, nil
+	}
+	return func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		got := maps.Clone(live)
+		maps.DeleteFunc(got, func(_ string, n int) bool { return n == 0 })
+		return got
+	}
+}
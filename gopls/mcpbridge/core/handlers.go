@@ -1,11 +1,14 @@
 package core
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -13,9 +16,11 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"golang.org/x/tools/gopls/internal/cache"
 	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/golang"
 	goplsmcp "golang.org/x/tools/gopls/internal/mcp"
 	"golang.org/x/tools/gopls/internal/protocol"
 	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
 	"golang.org/x/tools/gopls/mcpbridge/api"
 )
 
@@ -47,6 +52,43 @@ type Handler struct {
 	// dynamicViews tracks dynamically created views for cleanup in test mode.
 	dynamicViews   map[string]func()
 	dynamicViewsMu sync.Mutex
+
+	// overlayMu keeps the unsaved overlays that go_check_edit pushes into
+	// the session from the other tools and resources: they read the
+	// session's snapshots under the read lock, and go_check_edit holds the
+	// write lock while its overlay is in place.
+	overlayMu sync.RWMutex
+
+	// addFolder turns client roots into workspace folders (see roots.go).
+	addFolder FolderFunc
+	// roots holds the root directories reported by each client, by MCP
+	// session ID, and folders the removal function of each root's folder
+	// in the current gopls session. Both are protected by initMu.
+	roots   map[string][]string
+	folders map[string]func()
+	// rootsChanged wakes the goroutine that follows the roots of each
+	// connected client (see followRoots), by MCP session ID; protected by
+	// rootsMu.
+	rootsMu      sync.Mutex
+	rootsChanged map[string]chan struct{}
+
+	// callGraphs keeps the last call graph of each view and algorithm for
+	// go_call_path (see call_path.go); protected by callGraphMu.
+	callGraphMu sync.Mutex
+	callGraphs  map[callGraphKey]cachedCallGraph
+
+	// results keeps the results whose next pages are still to be
+	// fetched (see pagination.go).
+	results resultStore
+
+	// server is the MCP server, for change notifications (see
+	// notifications.go). subscriptions holds the sessions subscribed to
+	// each resource URI, and typeErrors holds the type errors last reported
+	// for each file; both are protected by notifyMu.
+	server        *mcp.Server
+	notifyMu      sync.Mutex
+	subscriptions map[string]map[*mcp.ServerSession]bool
+	typeErrors    map[protocol.DocumentURI][]string
 }
 
 // HandlerOption configures the Handler behavior.
@@ -82,10 +124,16 @@ type Symbler interface {
 // NewHandler creates a new Handler that initializes gopls lazily on first tool call.
 func NewHandler(initFn InitFunc, opts ...HandlerOption) *Handler {
 	h := &Handler{
-		initFn:       initFn,
-		options:      settings.DefaultOptions(),
-		config:       DefaultConfig(),
-		dynamicViews: make(map[string]func()),
+		initFn:        initFn,
+		options:       settings.DefaultOptions(),
+		config:        DefaultConfig(),
+		dynamicViews:  make(map[string]func()),
+		roots:         make(map[string][]string),
+		folders:       make(map[string]func()),
+		rootsChanged:  make(map[string]chan struct{}),
+		callGraphs:    make(map[callGraphKey]cachedCallGraph),
+		subscriptions: make(map[string]map[*mcp.ServerSession]bool),
+		typeErrors:    make(map[protocol.DocumentURI][]string),
 	}
 	for _, opt := range opts {
 		opt(h)
@@ -112,6 +160,7 @@ func (h *Handler) ensureSession(ctx context.Context) error {
 	h.session = session
 	h.symbler = symbler
 	h.watcher = watcher
+	h.syncFolders(context.Background())
 	h.timer = time.AfterFunc(h.idleTimeout, h.shutdownResources)
 	log.Printf("[gopls-mcp] Session initialized (idle timeout: %v)", h.idleTimeout)
 	return nil
@@ -126,35 +175,37 @@ func (h *Handler) resetIdleTimer() {
 	}
 }
 
-// shutdownResources releases all gopls resources.
+// shutdownResources releases all gopls resources, unless a client is
+// subscribed to a resource: the watcher must keep reporting changes to it,
+// so the shutdown is postponed instead.
 func (h *Handler) shutdownResources() {
 	h.initMu.Lock()
 	defer h.initMu.Unlock()
+	h.notifyMu.Lock()
+	subscribed := len(h.subscriptions) > 0
+	h.notifyMu.Unlock()
+	if subscribed && h.timer != nil {
+		h.timer.Reset(h.idleTimeout)
+		log.Printf("[gopls-mcp] Idle timeout reached; resources kept for resource subscribers")
+		return
+	}
 	if h.watcher != nil {
 		h.watcher.Close()
 		h.watcher = nil
 	}
+	h.removeFolders()
 	if h.session != nil {
 		h.session.Shutdown(context.Background())
 		h.session = nil
 		h.symbler = nil
 	}
+	h.callGraphMu.Lock()
+	clear(h.callGraphs)
+	h.callGraphMu.Unlock()
 	h.timer = nil
 	log.Printf("[gopls-mcp] All resources released (idle timeout reached)")
 }
 
-// snapshot returns the best default snapshot for workspace queries.
-func (h *Handler) snapshot() (*cache.Snapshot, func(), error) {
-	if h.session == nil {
-		return nil, nil, fmt.Errorf("no active session")
-	}
-	views := h.session.Views()
-	if len(views) == 0 {
-		return nil, nil, fmt.Errorf("no active views")
-	}
-	return views[0].Snapshot()
-}
-
 // snapshotForDir returns a snapshot for the given directory, or the default snapshot if dir is empty.
 func (h *Handler) snapshotForDir(dir string) (*cache.Snapshot, func(), error) {
 	view, err := h.getView(dir)
@@ -164,17 +215,108 @@ func (h *Handler) snapshotForDir(dir string) (*cache.Snapshot, func(), error) {
 	return view.Snapshot()
 }
 
-// viewForDir finds the view that contains the given directory.
+// resolveLocator returns locator in its context-file form, and the
+// directory whose view the caller must query: every locator-based tool
+// routes through here so that they all agree on the view.
+//
+// For a plain locator, that is the directory of context_file, so the view
+// that owns the file answers. A locator given by qualified_name is anchored
+// at the symbol's declaration, and viewDir is the root of the view that
+// resolved it. If the locator also has a context_file, that file's view is
+// searched; otherwise every view is, and of those that resolve it, the
+// innermost one that contains the declaration wins, so that the answer
+// does not depend on the order in which the views were created.
+func (h *Handler) resolveLocator(ctx context.Context, locator api.SymbolLocator) (_ api.SymbolLocator, viewDir string, _ error) {
+	if locator.QualifiedName == "" {
+		if locator.SymbolName == "" || locator.ContextFile == "" {
+			return locator, "", fmt.Errorf("locator needs either symbol_name and context_file, or qualified_name")
+		}
+		return locator, filepath.Dir(locator.ContextFile), nil
+	}
+
+	var views []*cache.View
+	if locator.ContextFile != "" {
+		view, err := h.getView(filepath.Dir(locator.ContextFile))
+		if err != nil {
+			return locator, "", err
+		}
+		views = []*cache.View{view}
+	} else {
+		if h.session == nil {
+			return locator, "", fmt.Errorf("no active session")
+		}
+		views = slices.SortedFunc(slices.Values(h.session.Views()), func(a, b *cache.View) int {
+			return strings.Compare(a.Root().Path(), b.Root().Path())
+		})
+	}
+
+	var (
+		best     api.SymbolLocator
+		bestView *cache.View
+		bestRank = -1
+		// err is the error of the first view that has the package, if
+		// none resolves it, or else the last error.
+		err      = fmt.Errorf("no views available to resolve %q", locator.QualifiedName)
+		errFinal bool
+	)
+	for _, view := range views {
+		snapshot, release, serr := view.Snapshot()
+		if serr != nil {
+			if !errFinal {
+				err = serr
+			}
+			continue
+		}
+		resolved, rerr := golang.ResolveQualifiedName(ctx, snapshot, locator)
+		release()
+		if rerr != nil {
+			if !errFinal {
+				err = rerr
+				var resErr *api.ResolutionError
+				errFinal = !errors.As(rerr, &resErr) || resErr.Code != api.ErrPackageNotLoaded
+			}
+			continue
+		}
+		// Rank the views that contain the declaration by depth, above
+		// those that see it as a dependency.
+		rank := 0
+		if root := view.Root().Path(); containsPath(root, resolved.ContextFile) {
+			rank = 1 + len(root)
+		}
+		if rank > bestRank {
+			best, bestView, bestRank = resolved, view, rank
+		}
+	}
+	if bestView == nil {
+		return locator, "", err
+	}
+	return best, bestView.Root().Path(), nil
+}
+
+// containsPath reports whether path is dir or inside it.
+func containsPath(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, strings.TrimSuffix(dir, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// viewForDir finds the view that contains the given directory. When views
+// are nested, as with a module inside another module's tree, the innermost
+// one wins.
 // If allowDynamicViews is enabled (TEST MODE), creates a new view if no existing view contains it.
 func (h *Handler) viewForDir(dir string) (*cache.View, error) {
 	dir = filepath.Clean(dir)
 
-	views := h.session.Views()
-	for _, v := range views {
+	var best *cache.View
+	for _, v := range h.session.Views() {
 		root := v.Root().Path()
-		if strings.HasPrefix(dir, root) || dir == root {
-			return v, nil
+		if !containsPath(root, dir) {
+			continue
 		}
+		if best == nil || len(root) > len(best.Root().Path()) {
+			best = v
+		}
+	}
+	if best != nil {
+		return best, nil
 	}
 
 	if !h.allowDynamicViews {
@@ -227,6 +369,14 @@ func (h *Handler) getView(dir string) (*cache.View, error) {
 // handleGetDependencyGraph returns the dependency graph for a package.
 // Uses: snapshot.LoadMetadataGraph() from gopls/internal/cache
 func handleGetDependencyGraph(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IDependencyGraphParams) (*mcp.CallToolResult, *api.ODependencyGraphResult, error) {
+	if input.Cursor != "" {
+		result, err := nextPage[api.ODependencyGraphResult](h, ToolGetDependencyGraph, input.Cursor, input.PageSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+	}
+
 	view, err := h.getView(input.Cwd)
 	if err != nil {
 		return nil, nil, err
@@ -309,16 +459,29 @@ func handleGetDependencyGraph(ctx context.Context, h *Handler, req *mcp.CallTool
 		})
 	}
 
-	result := &api.ODependencyGraphResult{
-		PackagePath:       targetPkgPath,
-		PackageName:       string(mp.Name),
-		Dependencies:      dependencies,
-		Dependents:        dependents,
-		TotalDependencies: len(dependencies),
-		TotalDependents:   len(dependents),
+	slices.SortFunc(dependents, func(x, y api.PackageDependent) int {
+		return cmp.Or(cmp.Compare(x.Path, y.Path), cmp.Compare(x.Name, y.Name))
+	})
+
+	// The pages run through the dependencies, then the dependents.
+	nDeps := len(dependencies)
+	page := func(start, end int, next string) *api.ODependencyGraphResult {
+		result := &api.ODependencyGraphResult{
+			PackagePath:       targetPkgPath,
+			PackageName:       string(mp.Name),
+			Dependencies:      dependencies[min(start, nDeps):min(end, nDeps)],
+			Dependents:        dependents[max(start-nDeps, 0):max(end-nDeps, 0)],
+			TotalDependencies: len(dependencies),
+			TotalDependents:   len(dependents),
+			Truncated:         next != "",
+			NextCursor:        next,
+		}
+		result.Summary = pageNote(start, end, len(dependencies)+len(dependents), next) + formatDependencyGraph(result)
+		return result
 	}
+	result := firstPage(h, ToolGetDependencyGraph, snapshot, len(dependencies)+len(dependents), pageSize(input.PageSize, defaultDependenciesPageSize), page)
 
-	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: formatDependencyGraph(result)}}}, result, nil
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
 }
 
 // collectDependencies recursively collects dependencies for a package.
@@ -327,7 +490,7 @@ func collectDependencies(mp *metadata.Package, md *metadata.Graph, mainModulePat
 		return
 	}
 
-	for depPath, depID := range mp.DepsByPkgPath {
+	for depPath, depID := range moremaps.Sorted(mp.DepsByPkgPath) {
 		depPathStr := string(depPath)
 
 		if seen[depPathStr] {
@@ -389,7 +552,11 @@ func formatDependencyGraph(result *api.ODependencyGraphResult) string {
 
 	fmt.Fprintf(&b, "Package: %s (%s)\n\n", result.PackagePath, result.PackageName)
 
-	if result.TotalDependencies > 0 {
+	// A page may hold the dependencies or the dependents only.
+	switch {
+	case result.TotalDependencies == 0:
+		fmt.Fprintf(&b, "Dependencies: None\n\n")
+	case len(result.Dependencies) > 0:
 		fmt.Fprintf(&b, "Dependencies (%d):\n", result.TotalDependencies)
 		for _, dep := range result.Dependencies {
 			indent := ""
@@ -409,11 +576,12 @@ func formatDependencyGraph(result *api.ODependencyGraphResult) string {
 			fmt.Fprintln(&b)
 		}
 		fmt.Fprintln(&b)
-	} else {
-		fmt.Fprintf(&b, "Dependencies: None\n\n")
 	}
 
-	if result.TotalDependents > 0 {
+	switch {
+	case result.TotalDependents == 0:
+		fmt.Fprintf(&b, "Imported By: None\n\n")
+	case len(result.Dependents) > 0:
 		fmt.Fprintf(&b, "Imported By (%d):\n", result.TotalDependents)
 		for _, dep := range result.Dependents {
 			fmt.Fprintf(&b, "  %s (%s)", dep.Path, dep.Name)
@@ -426,8 +594,6 @@ func formatDependencyGraph(result *api.ODependencyGraphResult) string {
 			fmt.Fprintln(&b)
 		}
 		fmt.Fprintln(&b)
-	} else {
-		fmt.Fprintf(&b, "Imported By: None\n\n")
 	}
 
 	if result.Summary != "" {
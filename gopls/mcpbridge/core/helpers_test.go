@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+)
+
+// newTestSession returns a handler whose session has a single view of a
+// temporary module with the given files. The session is shut down when
+// the test ends.
+func newTestSession(t *testing.T, files map[string]string) (*Handler, string) {
+	t.Helper()
+	ctx := context.Background()
+	h := NewHandler(nil, WithOptions(settings.DefaultOptions()))
+	h.session = cache.NewSession(ctx, cache.New(nil))
+	t.Cleanup(func() { h.session.Shutdown(ctx) })
+	return h, addTestView(t, h, files)
+}
+
+// addTestView writes the given files to a new temporary directory and
+// adds a view of it to the session of h, until the test ends. It returns
+// the directory.
+func addTestView(t *testing.T, h *Handler, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFiles(t, dir, files)
+	addTestFolder(t, h, dir)
+	return dir
+}
+
+// writeTestFiles writes files, by name relative to dir.
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// addTestFolder adds a view of dir to the session of h, until the test ends.
+func addTestFolder(t *testing.T, h *Handler, dir string) {
+	t.Helper()
+	ctx := context.Background()
+	dirURI := protocol.URIFromPath(dir)
+	env, err := cache.FetchGoEnv(ctx, dirURI, h.options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, release, err := h.session.NewView(ctx, &cache.Folder{Dir: dirURI, Options: h.options, Env: *env})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(release) // runs before the session is shut down
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_dryrun_implement_interface =====
+// Origin: gopls/internal/golang/implement_interface.go ImplementInterface(),
+// via golang.LLMImplementInterface and golang.LLMGenerateFake
+
+func handleGoDryrunImplementInterface(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IImplementInterfaceParams) (*mcp.CallToolResult, *api.OImplementInterfaceResult, error) {
+	switch {
+	case input.Concrete != nil && input.FakeFile != "":
+		return nil, nil, fmt.Errorf("give either concrete or fake_file, not both")
+	case input.Concrete == nil && input.FakeFile == "":
+		return nil, nil, fmt.Errorf("either concrete (to add the missing methods to a type) or fake_file (to generate a fake) is required")
+	case input.FakeFile != "" && !filepath.IsAbs(input.FakeFile):
+		return nil, nil, fmt.Errorf("fake_file must be absolute: %s", input.FakeFile)
+	}
+	ifaceLocator, dir, err := h.resolveLocator(ctx, input.Interface)
+	if err != nil {
+		return nil, nil, err
+	}
+	var concrete api.SymbolLocator
+	if input.Concrete != nil {
+		concrete, dir, err = h.resolveLocator(ctx, *input.Concrete)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		dir = filepath.Dir(input.FakeFile)
+	}
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	ifaceFH, ifaceRng, err := locatorRange(ctx, snapshot, ifaceLocator)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		changes []protocol.DocumentChange
+		result  *api.OImplementInterfaceResult
+	)
+	if input.Concrete != nil {
+		fh, rng, err := locatorRange(ctx, snapshot, concrete)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes, result, err = golang.LLMImplementInterface(ctx, snapshot, ifaceFH, ifaceRng, fh, rng)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		changes, result, err = golang.LLMGenerateFake(ctx, snapshot, ifaceFH, ifaceRng, input.FakeFile, input.FakeName)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	result.Diff, err = toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.FilesChanged = changedFiles(changes)
+	result.Summary = formatImplementInterface(result, input.FakeFile != "")
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+func formatImplementInterface(result *api.OImplementInterfaceResult, fake bool) string {
+	var b strings.Builder
+
+	if fake {
+		fmt.Fprintf(&b, "=== DRY RUN: Fake %s Preview ===\n", result.Interface)
+		fmt.Fprintf(&b, "Fake: %s, recording calls of %s\n", result.Type, strings.Join(result.Methods, ", "))
+	} else {
+		fmt.Fprintf(&b, "=== DRY RUN: Implement %s Preview ===\n", result.Interface)
+		fmt.Fprintf(&b, "Methods added to %s: %s\n", result.Type, strings.Join(result.Methods, ", "))
+	}
+	b.WriteString("NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&b, "%s\n", result.Diff)
+
+	return b.String()
+}
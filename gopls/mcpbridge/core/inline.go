@@ -0,0 +1,65 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_dryrun_inline =====
+// Origin: gopls/internal/golang/inline.go and inline_all.go, via
+// golang.LLMInline
+
+func handleGoDryrunInline(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IInlineParams) (*mcp.CallToolResult, *api.OInlineResult, error) {
+	locator, dir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fh, rng, err := locatorRange(ctx, snapshot, locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, result, err := golang.LLMInline(ctx, snapshot, fh, rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Diff, err = toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.FilesChanged = changedFiles(changes)
+	result.Summary = formatInline(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+func formatInline(result *api.OInlineResult) string {
+	var b strings.Builder
+
+	if result.AllCalls {
+		fmt.Fprintf(&b, "=== DRY RUN: Inline All Calls to %s Preview ===\n", result.Callee)
+		fmt.Fprintf(&b, "Files: %d (the declaration of %s is kept)\n", len(result.FilesChanged), result.Callee)
+	} else {
+		fmt.Fprintf(&b, "=== DRY RUN: Inline Call to %s Preview ===\n", result.Callee)
+	}
+	if len(result.Caveats) > 0 {
+		b.WriteString("Caveats:\n")
+		for _, c := range result.Caveats {
+			fmt.Fprintf(&b, "  - %s\n", c)
+		}
+	}
+	b.WriteString("NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&b, "%s\n", result.Diff)
+
+	return b.String()
+}
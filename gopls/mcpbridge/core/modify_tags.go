@@ -0,0 +1,55 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_dryrun_modify_tags =====
+// Origin: gopls/internal/golang/modify_tags.go ModifyTags(), via
+// golang.LLMModifyTags
+
+func handleGoDryrunModifyTags(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.IModifyTagsParams) (*mcp.CallToolResult, *api.OModifyTagsResult, error) {
+	locator, dir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot, release, err := h.snapshotForDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+
+	fh, rng, err := locatorRange(ctx, snapshot, locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes, result, err := golang.LLMModifyTags(ctx, snapshot, fh, rng, input)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.Diff, err = toUnifiedDiff(ctx, snapshot, changes)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.FilesChanged = changedFiles(changes)
+	result.Summary = formatModifyTags(result)
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+func formatModifyTags(result *api.OModifyTagsResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "=== DRY RUN: Modify Tags of %s Preview ===\n", result.Struct)
+	fmt.Fprintf(&b, "Fields: %s\n", strings.Join(result.Fields, "; "))
+	b.WriteString("NO FILES HAVE BEEN MODIFIED - this is a preview only.\n\n")
+	fmt.Fprintf(&b, "%s\n", result.Diff)
+
+	return b.String()
+}
@@ -0,0 +1,252 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
+)
+
+// ===== Change notifications =====
+//
+// The file watchers report each batch of on-disk changes to FilesChanged,
+// once the session has been invalidated. Clients hear about it in two ways:
+//
+//   - A client subscribed to the package or file outline resource of a
+//     changed file gets notifications/resources/updated for it.
+//   - The packages of the changed files and their direct importers are
+//     type-checked again, and the type errors that appeared or disappeared
+//     since the last batch that touched them are sent to every client as a
+//     log message (notifications/message, logger "gopls-mcp/diagnostics").
+//     Clients only receive log messages once they have set a log level.
+//     For a package the watcher has not touched before, every current error
+//     counts as new.
+//
+// The session and the watchers outlive the idle timeout as long as any
+// client is subscribed to a resource (see shutdownResources); the
+// subscriptions of a client end when it disconnects.
+
+// diagnosticsLogger is the logger name of the type error summaries.
+const diagnosticsLogger = "gopls-mcp/diagnostics"
+
+// subscribeResource records a subscription to a package or file outline
+// resource, and starts the session so that the files are watched.
+func (h *Handler) subscribeResource(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	if !strings.HasPrefix(uri, packageResourcePrefix) &&
+		!(strings.HasPrefix(uri, fileResourcePrefix) && strings.HasSuffix(uri, outlineResourceSuffix)) {
+		return fmt.Errorf("cannot subscribe to %s: only package and file outline resources can be subscribed to", uri)
+	}
+	if err := h.ensureSession(ctx); err != nil {
+		return err
+	}
+	h.notifyMu.Lock()
+	defer h.notifyMu.Unlock()
+	ss := req.Session
+	if !h.subscribed(ss) {
+		go func() {
+			ss.Wait()
+			h.unsubscribeSession(ss)
+		}()
+	}
+	if h.subscriptions[uri] == nil {
+		h.subscriptions[uri] = make(map[*mcp.ServerSession]bool)
+	}
+	h.subscriptions[uri][ss] = true
+	return nil
+}
+
+// unsubscribeResource drops a subscription recorded by subscribeResource.
+func (h *Handler) unsubscribeResource(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	h.notifyMu.Lock()
+	defer h.notifyMu.Unlock()
+	uri := req.Params.URI
+	if delete(h.subscriptions[uri], req.Session); len(h.subscriptions[uri]) == 0 {
+		delete(h.subscriptions, uri)
+	}
+	return nil
+}
+
+// unsubscribeSession drops the subscriptions of ss, whose client is gone.
+func (h *Handler) unsubscribeSession(ss *mcp.ServerSession) {
+	h.notifyMu.Lock()
+	defer h.notifyMu.Unlock()
+	for uri, sessions := range h.subscriptions {
+		if delete(sessions, ss); len(sessions) == 0 {
+			delete(h.subscriptions, uri)
+		}
+	}
+}
+
+// subscribed reports whether ss is subscribed to any resource.
+// h.notifyMu must be held.
+func (h *Handler) subscribed(ss *mcp.ServerSession) bool {
+	for _, sessions := range h.subscriptions {
+		if sessions[ss] {
+			return true
+		}
+	}
+	return false
+}
+
+// FilesChanged notifies the clients of the changes to files made on disk.
+// It must be called after the session has seen the changes.
+func (h *Handler) FilesChanged(ctx context.Context, uris []protocol.DocumentURI) {
+	session := h.session
+	if h.server == nil || session == nil {
+		return
+	}
+
+	// Keep the overlay of a go_check_edit in progress out of the errors:
+	// it is not a change on disk.
+	h.overlayMu.RLock()
+	updated := make(map[string]bool) // resource URIs
+	current := make(map[protocol.DocumentURI][]string)
+	for _, view := range session.Views() {
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			continue
+		}
+		pkgs := make(map[metadata.PackageID]*metadata.Package)
+		for _, uri := range uris {
+			if !strings.HasPrefix(uri.Path(), view.Root().Path()+string(filepath.Separator)) {
+				continue
+			}
+			updated[fileOutlineURI(uri.Path())] = true
+			if _, ok := current[uri]; !ok {
+				current[uri] = nil // clears the errors of a deleted file
+			}
+			affected, err := affectedPackages(ctx, snapshot, uri)
+			if err != nil {
+				continue // e.g. a deleted file, or not a Go file
+			}
+			mps, _ := snapshot.MetadataForFile(ctx, uri, true)
+			for _, mp := range mps {
+				updated[packageResourcePrefix+string(mp.PkgPath)] = true
+			}
+			for id, mp := range affected {
+				pkgs[id] = mp
+			}
+		}
+		if err := typeErrors(ctx, snapshot, pkgs, current); err != nil {
+			log.Printf("[gopls-mcp] Failed to type-check changed packages: %v", err)
+		}
+		release()
+	}
+	h.overlayMu.RUnlock()
+
+	h.notifyMu.Lock()
+	var notify []string
+	for uri := range moremaps.Sorted(updated) {
+		if len(h.subscriptions[uri]) > 0 {
+			notify = append(notify, uri)
+		}
+	}
+	added, cleared := diffTypeErrors(h.typeErrors, current)
+	for uri, errs := range current {
+		if len(errs) == 0 {
+			delete(h.typeErrors, uri)
+		} else {
+			h.typeErrors[uri] = errs
+		}
+	}
+	h.notifyMu.Unlock()
+
+	for _, uri := range notify {
+		if err := h.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+			log.Printf("[gopls-mcp] Failed to notify update of %s: %v", uri, err)
+		}
+	}
+	if len(added) == 0 && len(cleared) == 0 {
+		return
+	}
+	level := mcp.LoggingLevel("info")
+	if len(added) > 0 {
+		level = "error"
+	}
+	summary := formatTypeErrorChanges(added, cleared)
+	for ss := range h.server.Sessions() {
+		if err := ss.Log(ctx, &mcp.LoggingMessageParams{Level: level, Logger: diagnosticsLogger, Data: summary}); err != nil {
+			log.Printf("[gopls-mcp] Failed to send type error summary: %v", err)
+		}
+	}
+}
+
+// typeErrors adds the error-severity diagnostics of pkgs to errs, by file,
+// as "file:line:col: message" lines. Every Go file of pkgs gets an entry,
+// empty if the file has no errors.
+func typeErrors(ctx context.Context, snapshot *cache.Snapshot, pkgs map[metadata.PackageID]*metadata.Package, errs map[protocol.DocumentURI][]string) error {
+	if len(pkgs) == 0 {
+		return nil
+	}
+	for _, mp := range pkgs {
+		for _, uri := range mp.CompiledGoFiles {
+			if _, ok := errs[uri]; !ok {
+				errs[uri] = nil
+			}
+		}
+	}
+	diags, err := snapshot.PackageDiagnostics(ctx, slices.Collect(maps.Keys(pkgs))...)
+	if err != nil {
+		return err
+	}
+	for uri, ds := range diags {
+		for _, d := range ds {
+			if d.Severity != protocol.SeverityError {
+				continue
+			}
+			e := fmt.Sprintf("%s:%d:%d: %s", uri.Path(), d.Range.Start.Line+1, d.Range.Start.Character+1, d.Message)
+			if !slices.Contains(errs[uri], e) { // a file in several packages is diagnosed once per package
+				errs[uri] = append(errs[uri], e)
+			}
+		}
+	}
+	return nil
+}
+
+// diffTypeErrors returns the errors of current that are not in prev, and
+// those of prev that are no longer in current, for the files of current.
+func diffTypeErrors(prev, current map[protocol.DocumentURI][]string) (added, cleared []string) {
+	for uri, errs := range moremaps.Sorted(current) {
+		for _, e := range errs {
+			if !slices.Contains(prev[uri], e) {
+				added = append(added, e)
+			}
+		}
+		for _, e := range prev[uri] {
+			if !slices.Contains(errs, e) {
+				cleared = append(cleared, e)
+			}
+		}
+	}
+	return added, cleared
+}
+
+// formatTypeErrorChanges summarizes the type errors that appeared and
+// disappeared after a batch of file changes.
+func formatTypeErrorChanges(added, cleared []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Type errors after file changes: %d new, %d cleared\n", len(added), len(cleared))
+	if len(added) > 0 {
+		b.WriteString("\nNew:\n")
+		for _, e := range added {
+			fmt.Fprintf(&b, "  %s\n", e)
+		}
+	}
+	if len(cleared) > 0 {
+		b.WriteString("\nCleared:\n")
+		for _, e := range cleared {
+			fmt.Fprintf(&b, "  %s\n", e)
+		}
+	}
+	return b.String()
+}
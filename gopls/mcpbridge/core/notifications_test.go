@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+func TestDiffTypeErrors(t *testing.T) {
+	prev := map[protocol.DocumentURI][]string{
+		"file:///a.go": {"a.go:1:1: old", "a.go:2:1: kept"},
+		"file:///b.go": {"b.go:1:1: untouched"},
+		"file:///c.go": {"c.go:1:1: deleted file"},
+	}
+	current := map[protocol.DocumentURI][]string{
+		"file:///a.go": {"a.go:2:1: kept", "a.go:3:1: new"},
+		"file:///c.go": nil,
+		"file:///d.go": {"d.go:1:1: first seen"},
+	}
+	added, cleared := diffTypeErrors(prev, current)
+	if want := []string{"a.go:3:1: new", "d.go:1:1: first seen"}; !slices.Equal(added, want) {
+		t.Errorf("added = %q, want %q", added, want)
+	}
+	// b.go was not checked again, so its error stands.
+	if want := []string{"a.go:1:1: old", "c.go:1:1: deleted file"}; !slices.Equal(cleared, want) {
+		t.Errorf("cleared = %q, want %q", cleared, want)
+	}
+}
+
+// TestFilesChanged_DuringCheckEdit verifies that a batch of file changes
+// that arrives while go_check_edit has its overlay in place does not
+// report the errors of the proposed content.
+func TestFilesChanged_DuringCheckEdit(t *testing.T) {
+	ctx := context.Background()
+	h, dir := newTestSession(t, map[string]string{
+		"go.mod": "module example.com/a\n\ngo 1.21\n",
+		"a.go":   "package a\n\nfunc A() {}\n",
+	})
+	h.server = mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	uri := protocol.URIFromPath(filepath.Join(dir, "a.go"))
+
+	// Deliver watcher events for as long as go_check_edit keeps proposing
+	// broken content.
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		defer close(done)
+		for range 10 {
+			if _, _, err := handleGoCheckEdit(ctx, h, nil, api.ICheckEditParams{
+				FilePath:   uri.Path(),
+				NewContent: "package a\n\nfunc A() { undefined() }\n",
+			}); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	wg.Go(func() {
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			h.FilesChanged(ctx, []protocol.DocumentURI{uri})
+			h.notifyMu.Lock()
+			errs := h.typeErrors[uri]
+			h.notifyMu.Unlock()
+			if len(errs) > 0 {
+				t.Errorf("type errors after a watcher event = %q, want none", errs)
+				return
+			}
+		}
+	})
+	wg.Wait()
+}
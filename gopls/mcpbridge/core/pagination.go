@@ -0,0 +1,223 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/gopls/internal/cache"
+)
+
+// ===== Pagination =====
+//
+// Tools with potentially long lists of results (go_symbol_references,
+// go_implementation, go_get_call_hierarchy, go_get_dependency_graph) return
+// them a page at a time instead of letting the response limiter cut them
+// off. The first call computes the whole, deterministically ordered result
+// and, if it does not fit in one page, keeps it under the ID of the snapshot
+// it was computed in; the opaque next_cursor in the response leads to the
+// following page. Every page of a result therefore comes from the same
+// snapshot, even if the workspace changes in between.
+
+const (
+	// Default page sizes, by tool.
+	defaultReferencesPageSize      = 100
+	defaultImplementationsPageSize = 50
+	defaultCallsPageSize           = 100
+	defaultDependenciesPageSize    = 200
+
+	// maxPageSize bounds the page_size parameter of every tool.
+	maxPageSize = 1000
+	// pagedResultTTL is how long a result is kept after its last use.
+	pagedResultTTL = 10 * time.Minute
+	// maxPagedResults bounds the number of results kept; beyond it, the
+	// least recently used one is dropped.
+	maxPagedResults = 64
+)
+
+// errBadCursor is returned for a cursor that is malformed, belongs to
+// another tool, or whose result has expired.
+var errBadCursor = errors.New("invalid or expired cursor: run the query again without a cursor")
+
+// pageFunc renders the items [start, end) of a result as a tool output.
+// next is the cursor of the following page, or "" for the last page.
+type pageFunc[Out any] func(start, end int, next string) *Out
+
+// resultKey identifies a stored result: the snapshot it was computed in,
+// and a number that tells it from the other results of that snapshot.
+type resultKey struct {
+	view     string
+	snapshot uint64
+	id       uint64
+}
+
+// pagedResult is a result with pages left to return.
+type pagedResult struct {
+	tool     string
+	total    int
+	pageSize int
+	page     any // a pageFunc for the tool's output type
+	used     time.Time
+}
+
+// resultStore holds the paged results of a Handler. The zero value is
+// ready to use.
+type resultStore struct {
+	mu      sync.Mutex
+	lastID  uint64
+	results map[resultKey]*pagedResult
+}
+
+// pageSize returns the page size requested by size, or def if none.
+func pageSize(size, def int) int {
+	if size <= 0 {
+		return def
+	}
+	return min(size, maxPageSize)
+}
+
+// firstPage returns the first page of a result of total items computed in
+// snapshot, keeping the result if more pages are left.
+func firstPage[Out any](h *Handler, tool string, snapshot *cache.Snapshot, total, size int, page pageFunc[Out]) *Out {
+	maxBytes := h.maxResponseBytes()
+	if total <= size {
+		if out := page(0, total, ""); fits(out, maxBytes) {
+			return out
+		}
+	}
+	r := &pagedResult{tool: tool, total: total, pageSize: size, page: page}
+	key := h.results.add(snapshot.View().ID(), snapshot.SequenceID(), r)
+	return fitPage(page, key, 0, min(size, total), total, maxBytes)
+}
+
+// nextPage returns the page of a stored result at cursor. A size of 0
+// keeps the page size of the first page.
+func nextPage[Out any](h *Handler, tool, cursor string, size int) (*Out, error) {
+	key, start, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	r := h.results.get(key)
+	if r == nil || r.tool != tool || start > r.total {
+		return nil, errBadCursor
+	}
+	page, ok := r.page.(pageFunc[Out])
+	if !ok {
+		return nil, errBadCursor
+	}
+	end := min(start+pageSize(size, r.pageSize), r.total)
+	return fitPage(page, key, start, end, r.total, h.maxResponseBytes()), nil
+}
+
+// fitPage renders the longest page of the result at key that starts at
+// start, ends at or before end, and fits in maxBytes, so that the response
+// limiter leaves it whole and its cursor leads to the first item it does
+// not hold. A page holds at least one item, so that paging goes on even
+// past an item too large for a response by itself.
+func fitPage[Out any](page pageFunc[Out], key resultKey, start, end, total, maxBytes int) *Out {
+	render := func(end int) *Out {
+		next := ""
+		if end < total {
+			next = encodeCursor(key, end)
+		}
+		return page(start, end, next)
+	}
+	if out := render(end); end-start <= 1 || fits(out, maxBytes) {
+		return out
+	}
+	// n is the number of items of the first page that does not fit.
+	n := 1 + sort.Search(end-start, func(i int) bool { return !fits(render(start+i+1), maxBytes) })
+	return render(start + max(n-1, 1))
+}
+
+// fits reports whether the output of a tool fits in maxBytes. Its JSON,
+// which holds the summary and the items the summary is made from, is
+// measured: it is larger than the text, and limitOutput cuts it beyond
+// maxBytes.
+func fits(out any, maxBytes int) bool {
+	data, err := json.Marshal(out)
+	return err == nil && len(data) <= maxBytes
+}
+
+// add stores r, computed in the given snapshot of view, and returns its key.
+func (s *resultStore) add(view string, snapshot uint64, r *pagedResult) resultKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.results == nil {
+		s.results = make(map[resultKey]*pagedResult)
+	}
+	for key, old := range s.results {
+		if now.Sub(old.used) > pagedResultTTL {
+			delete(s.results, key)
+		}
+	}
+	for len(s.results) >= maxPagedResults {
+		var oldest resultKey
+		for key, old := range s.results {
+			if oldest == (resultKey{}) || old.used.Before(s.results[oldest].used) {
+				oldest = key
+			}
+		}
+		delete(s.results, oldest)
+	}
+	s.lastID++
+	key := resultKey{view: view, snapshot: snapshot, id: s.lastID}
+	r.used = now
+	s.results[key] = r
+	return key
+}
+
+// get returns the result stored under key, or nil if it has expired.
+func (s *resultStore) get(key resultKey) *pagedResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.results[key]
+	if r == nil || time.Since(r.used) > pagedResultTTL {
+		delete(s.results, key)
+		return nil
+	}
+	r.used = time.Now()
+	return r
+}
+
+// encodeCursor returns the cursor of the page of the result at key that
+// starts at item start.
+func encodeCursor(key resultKey, start int) string {
+	s := fmt.Sprintf("%d:%d:%d:%s", key.snapshot, key.id, start, key.view)
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// decodeCursor is the inverse of encodeCursor.
+func decodeCursor(cursor string) (resultKey, int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return resultKey{}, 0, errBadCursor
+	}
+	var (
+		key   resultKey
+		start int
+	)
+	if _, err := fmt.Sscanf(string(data), "%d:%d:%d:%s", &key.snapshot, &key.id, &start, &key.view); err != nil || start < 0 {
+		return resultKey{}, 0, errBadCursor
+	}
+	return key, start, nil
+}
+
+// pageNote describes the page [start, end) of total items, and how to get
+// the next one, or returns "" if the page holds them all. It goes at the
+// top of the summary, where no cut of an oversized response can reach it.
+func pageNote(start, end, total int, next string) string {
+	if start == 0 && end == total {
+		return ""
+	}
+	note := fmt.Sprintf("(Showing %d-%d of %d.", start+1, end, total)
+	if next != "" {
+		note += fmt.Sprintf(" Pass cursor %q for the next page.", next)
+	}
+	return note + ")\n\n"
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testPage is a page of a test result.
+type testPage struct {
+	items []string
+	next  string
+}
+
+func TestNextPage(t *testing.T) {
+	h := &Handler{}
+	items := []string{"a", "b", "c", "d", "e"}
+	var page pageFunc[testPage] = func(start, end int, next string) *testPage {
+		return &testPage{items[start:end], next}
+	}
+	key := h.results.add("1", 7, &pagedResult{tool: "t", total: len(items), pageSize: 2, page: page})
+
+	// Walk the pages after the first, as a client would.
+	var got []string
+	pages := 0
+	for cursor := encodeCursor(key, 2); cursor != ""; pages++ {
+		p, err := nextPage[testPage](h, "t", cursor, 0)
+		if err != nil {
+			t.Fatalf("nextPage(%q) failed: %v", cursor, err)
+		}
+		got = append(got, p.items...)
+		cursor = p.next
+	}
+	if want := []string{"c", "d", "e"}; !slices.Equal(got, want) || pages != 2 {
+		t.Errorf("%d pages after the first hold %v, want 2 pages holding %v", pages, got, want)
+	}
+
+	// A larger page size covers the rest at once.
+	p, err := nextPage[testPage](h, "t", encodeCursor(key, 1), 10)
+	if err != nil || !slices.Equal(p.items, []string{"b", "c", "d", "e"}) || p.next != "" {
+		t.Errorf("nextPage(page_size=10) = %+v, %v; want the last page [b c d e]", p, err)
+	}
+
+	for name, cursor := range map[string]string{
+		"garbage":         "not a cursor",
+		"unknown result":  encodeCursor(resultKey{view: "1", snapshot: 7, id: 99}, 2),
+		"other snapshot":  encodeCursor(resultKey{view: "1", snapshot: 8, id: key.id}, 2),
+		"past the end":    encodeCursor(key, 6),
+		"negative offset": encodeCursor(key, -1),
+	} {
+		if _, err := nextPage[testPage](h, "t", cursor, 0); err != errBadCursor {
+			t.Errorf("%s: nextPage() error = %v, want errBadCursor", name, err)
+		}
+	}
+	if _, err := nextPage[testPage](h, "other", encodeCursor(key, 2), 0); err != errBadCursor {
+		t.Errorf("nextPage() for another tool: error = %v, want errBadCursor", err)
+	}
+}
+
+// sizedPage is a page of a test result whose size counts.
+type sizedPage struct {
+	Items []string
+	Next  string
+}
+
+func TestNextPage_FitsResponseLimit(t *testing.T) {
+	h := &Handler{config: &MCPConfig{MaxResponseBytes: 1000}}
+	var items []string
+	for i := range 20 {
+		size := 200
+		if i == 7 {
+			size = 2000 // larger than a response by itself
+		}
+		items = append(items, fmt.Sprintf("%02d%s", i, strings.Repeat("x", size)))
+	}
+	var page pageFunc[sizedPage] = func(start, end int, next string) *sizedPage {
+		return &sizedPage{items[start:end], next}
+	}
+	key := h.results.add("1", 7, &pagedResult{tool: "t", total: len(items), pageSize: 10, page: page})
+
+	// Every page but the one of the large item fits, and together they
+	// hold every item once, in order.
+	var got []string
+	for cursor := encodeCursor(key, 0); cursor != ""; {
+		p, err := nextPage[sizedPage](h, "t", cursor, 0)
+		if err != nil {
+			t.Fatalf("nextPage(%q) failed: %v", cursor, err)
+		}
+		if len(p.Items) == 0 {
+			t.Fatalf("nextPage(%q) returned an empty page", cursor)
+		}
+		if data, _ := json.Marshal(p); len(data) > 1000 && len(p.Items) > 1 {
+			t.Errorf("page of %d items is %d bytes, over the limit of 1000", len(p.Items), len(data))
+		}
+		got = append(got, p.Items...)
+		cursor = p.Next
+	}
+	if !slices.Equal(got, items) {
+		t.Errorf("pages hold %d items, want all %d in order", len(got), len(items))
+	}
+}
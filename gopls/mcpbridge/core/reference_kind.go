@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/ast/edge"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== Reference kinds =====
+//
+// go_symbol_references classifies each reference by what the code does with
+// the symbol there, so that the agent need not re-read every line to tell a
+// field write from a read.
+
+// Kinds of reference, as reported in api.SymbolReference.Kind.
+const (
+	refRead  = "read"  // any other use of a value
+	refWrite = "write" // assigned, incremented, set in a literal, or address taken
+	refCall  = "call"  // called, possibly deferred or in a go statement
+	refType  = "type"  // a type in a type expression or conversion
+	refEmbed = "embed" // a type embedded in a struct or interface
+)
+
+// classifyReferenceKinds records in kinds the kind of each of locations;
+// see referenceKind. The files must belong to snapshot's view.
+func classifyReferenceKinds(ctx context.Context, snapshot *cache.Snapshot, locations []protocol.Location, kinds map[protocol.Location]string) {
+	for _, loc := range locations {
+		if info, cur, ok := identCursorAt(ctx, snapshot, loc); ok {
+			kinds[loc] = referenceKind(info, cur)
+		}
+	}
+}
+
+// referenceKind classifies the reference by the identifier at cur.
+//
+// Taking the address counts as a write, since the symbol may be modified
+// through the pointer, and so does a field key in a composite literal.
+func referenceKind(info *types.Info, cur inspector.Cursor) string {
+	id := cur.Node().(*ast.Ident)
+	obj := info.Uses[id]
+
+	// Move up to the expression that denotes the symbol: x.f, pkg.T,
+	// F[int] or (x).
+	if cur.ParentEdgeKind() == edge.SelectorExpr_Sel {
+		cur = cur.Parent()
+	}
+	for {
+		switch cur.ParentEdgeKind() {
+		case edge.ParenExpr_X:
+			cur = cur.Parent()
+			continue
+		case edge.IndexExpr_X, edge.IndexListExpr_X:
+			if _, ok := info.Instances[id]; ok {
+				cur = cur.Parent()
+				continue
+			}
+		}
+		break
+	}
+
+	_, isType := obj.(*types.TypeName)
+	switch cur.ParentEdgeKind() {
+	case edge.CallExpr_Fun:
+		if !isType { // a conversion uses a type
+			return refCall
+		}
+	case edge.AssignStmt_Lhs, edge.IncDecStmt_X, edge.RangeStmt_Key, edge.RangeStmt_Value:
+		return refWrite
+	case edge.UnaryExpr_X:
+		if cur.Parent().Node().(*ast.UnaryExpr).Op == token.AND {
+			return refWrite
+		}
+	case edge.KeyValueExpr_Key:
+		if v, ok := obj.(*types.Var); ok && v.IsField() && cur.Parent().ParentEdgeKind() == edge.CompositeLit_Elts {
+			return refWrite
+		}
+	}
+	if isType {
+		if isEmbeddedField(cur) {
+			return refEmbed
+		}
+		return refType
+	}
+	return refRead
+}
+
+// isEmbeddedField reports whether the type expression at cur is an
+// embedded field of a struct or interface type, as in struct{ *T }.
+func isEmbeddedField(cur inspector.Cursor) bool {
+	if cur.ParentEdgeKind() == edge.StarExpr_X {
+		cur = cur.Parent()
+	}
+	if cur.ParentEdgeKind() != edge.Field_Type {
+		return false
+	}
+	field := cur.Parent()
+	if len(field.Node().(*ast.Field).Names) > 0 {
+		return false
+	}
+	switch field.Parent().ParentEdgeKind() { // the enclosing FieldList
+	case edge.StructType_Fields, edge.InterfaceType_Methods:
+		return true
+	}
+	return false
+}
+
+// keepReference reports whether the reference at loc, of the given kind,
+// passes the filters of input. With both only_writes and only_calls,
+// writes and calls are kept.
+func keepReference(ctx context.Context, snapshot *cache.Snapshot, input api.ISymbolReferencesParams, loc protocol.Location, kind string) bool {
+	if input.OnlyWrites || input.OnlyCalls {
+		if !(input.OnlyWrites && kind == refWrite || input.OnlyCalls && kind == refCall) {
+			return false
+		}
+	}
+	if input.ExcludeTests && strings.HasSuffix(loc.URI.Path(), "_test.go") {
+		return false
+	}
+	if input.ExcludeGenerated && golang.IsGenerated(ctx, snapshot, loc.URI) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,346 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== MCP resources =====
+//
+// Besides tools, gopls-mcp exposes packages, symbols and file outlines as
+// MCP resources, so that clients can browse them and attach them as context:
+//
+//	gopls://package/{importpath}       exported API of a package
+//	gopls://symbol/{importpath}/{name} definition and doc of a symbol
+//	gopls://file/{path}/outline        declarations of a file
+//
+// The file path is absolute, written without its leading slash. A symbol
+// name is a package-level name or Type.Method. resources/list enumerates the
+// workspace packages and their files from the loaded metadata graph; symbols
+// are reachable through their template only.
+
+const (
+	packageResourcePrefix = "gopls://package/"
+	symbolResourcePrefix  = "gopls://symbol/"
+	fileResourcePrefix    = "gopls://file/"
+	outlineResourceSuffix = "/outline"
+
+	// resourcesPageSize is the number of resources per resources/list page.
+	resourcesPageSize = 500
+)
+
+// resourceTemplates are the templates of the resources, with their handlers.
+var resourceTemplates = []struct {
+	template *mcp.ResourceTemplate
+	read     func(ctx context.Context, h *Handler, uri string) (string, error)
+}{
+	{
+		&mcp.ResourceTemplate{
+			Name:        "package",
+			Title:       "Package API",
+			URITemplate: packageResourcePrefix + "{+importpath}",
+			Description: "Exported API of a Go package: its declarations and doc comments, without function bodies.",
+			MIMEType:    "text/markdown",
+		},
+		readPackageResource,
+	},
+	{
+		&mcp.ResourceTemplate{
+			Name:        "symbol",
+			Title:       "Symbol",
+			URITemplate: symbolResourcePrefix + "{+importpath}/{name}",
+			Description: "Definition, signature and doc comment of a package-level Go symbol or method (name is Name or Type.Method).",
+			MIMEType:    "text/markdown",
+		},
+		readSymbolResource,
+	},
+	{
+		&mcp.ResourceTemplate{
+			Name:        "file-outline",
+			Title:       "File Outline",
+			URITemplate: fileResourcePrefix + "{+path}" + outlineResourceSuffix,
+			Description: "Outline of a Go file: its declarations, nested fields and methods, with their lines. path is the absolute file path without its leading slash.",
+			MIMEType:    "text/markdown",
+		},
+		readFileOutlineResource,
+	},
+}
+
+// RegisterResources registers the resource templates with the MCP server,
+// serves resources/list from the workspace metadata, and lets the handler
+// notify the server's clients of changes (see notifications.go).
+func RegisterResources(server *mcp.Server, handler *Handler) {
+	maxBytes := handler.maxResponseBytes()
+	for _, rt := range resourceTemplates {
+		read := rt.read
+		server.AddResourceTemplate(rt.template, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			if err := handler.ensureSession(ctx); err != nil {
+				return nil, err
+			}
+			defer handler.resetIdleTimer()
+			handler.overlayMu.RLock()
+			defer handler.overlayMu.RUnlock()
+
+			uri := req.Params.URI
+			text, err := read(ctx, handler, uri)
+			if err != nil {
+				return nil, err
+			}
+			if truncated, ok := truncateByBytes(text, maxBytes); ok {
+				text = truncated + TruncationIndicator
+			}
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{{URI: uri, Text: text}},
+			}, nil
+		})
+	}
+	server.AddReceivingMiddleware(handler.listResourcesMiddleware)
+	handler.server = server
+}
+
+// listResourcesMiddleware answers resources/list with the resources of the
+// workspace packages; the SDK only knows about statically added resources.
+func (h *Handler) listResourcesMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		lr, ok := req.(*mcp.ListResourcesRequest)
+		if method != "resources/list" || !ok {
+			return next(ctx, method, req)
+		}
+		if err := h.ensureSession(ctx); err != nil {
+			return nil, err
+		}
+		defer h.resetIdleTimer()
+		h.overlayMu.RLock()
+		defer h.overlayMu.RUnlock()
+
+		start := 0
+		if lr.Params != nil && lr.Params.Cursor != "" {
+			n, err := strconv.Atoi(lr.Params.Cursor)
+			if err != nil || n < 0 {
+				return nil, errBadCursor
+			}
+			start = n
+		}
+		resources, err := h.workspaceResources(ctx)
+		if err != nil {
+			return nil, err
+		}
+		start = min(start, len(resources))
+		end := min(start+resourcesPageSize, len(resources))
+		result := &mcp.ListResourcesResult{Resources: resources[start:end]}
+		if end < len(resources) {
+			result.NextCursor = strconv.Itoa(end)
+		}
+		return result, nil
+	}
+}
+
+// workspaceResources returns the package and file outline resources of the
+// workspace packages of every view, ordered by import path.
+func (h *Handler) workspaceResources(ctx context.Context) ([]*mcp.Resource, error) {
+	if h.session == nil {
+		return nil, fmt.Errorf("no active session")
+	}
+	pkgs := make(map[metadata.PackagePath]*metadata.Package)
+	for _, view := range h.session.Views() {
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			continue
+		}
+		md, err := snapshot.LoadMetadataGraph(ctx)
+		if err != nil {
+			release()
+			return nil, fmt.Errorf("failed to load metadata graph: %w", err)
+		}
+		for id := range snapshot.WorkspacePackages().All() {
+			mp := md.Packages[id]
+			if mp == nil || mp.ForTest != "" || mp.IsIntermediateTestVariant() {
+				continue
+			}
+			if _, ok := pkgs[mp.PkgPath]; !ok {
+				pkgs[mp.PkgPath] = mp
+			}
+		}
+		release()
+	}
+
+	var resources []*mcp.Resource
+	for path, mp := range moremaps.Sorted(pkgs) {
+		resources = append(resources, &mcp.Resource{
+			Name:     string(path),
+			Title:    fmt.Sprintf("package %s", mp.Name),
+			URI:      packageResourcePrefix + string(path),
+			MIMEType: "text/markdown",
+		})
+		for _, f := range mp.GoFiles {
+			resources = append(resources, &mcp.Resource{
+				Name:     string(path) + "/" + f.Base(),
+				Title:    fmt.Sprintf("%s outline", f.Base()),
+				URI:      fileOutlineURI(f.Path()),
+				MIMEType: "text/markdown",
+			})
+		}
+	}
+	return resources, nil
+}
+
+// fileOutlineURI returns the outline resource URI of the file at path.
+func fileOutlineURI(path string) string {
+	return fileResourcePrefix + strings.TrimPrefix(filepath.ToSlash(path), "/") + outlineResourceSuffix
+}
+
+// resourceArg returns the part of uri after prefix, unescaped, or an error
+// for a URI that is not a resource of the template.
+func resourceArg(uri, prefix string) (string, error) {
+	rest, ok := strings.CutPrefix(uri, prefix)
+	if !ok || rest == "" {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	rest, err := url.PathUnescape(rest)
+	if err != nil {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	return rest, nil
+}
+
+// readPackageResource returns the exported API of the package of a
+// gopls://package/{importpath} URI, from the first view that has it loaded.
+func readPackageResource(ctx context.Context, h *Handler, uri string) (string, error) {
+	pkgPath, err := resourceArg(uri, packageResourcePrefix)
+	if err != nil {
+		return "", err
+	}
+	for _, view := range h.session.Views() {
+		snapshot, release, err := view.Snapshot()
+		if err != nil {
+			continue
+		}
+		md, err := snapshot.LoadMetadataGraph(ctx)
+		if err != nil {
+			release()
+			return "", fmt.Errorf("failed to load metadata graph: %w", err)
+		}
+		// The package itself, as listed by workspaceResources, not one of
+		// its test variants.
+		mps := md.ForPackagePath[metadata.PackagePath(pkgPath)]
+		if i := slices.IndexFunc(mps, func(mp *metadata.Package) bool {
+			return mp.ForTest == "" && !mp.IsIntermediateTestVariant()
+		}); i >= 0 {
+			summary := summarizePackageWithBodyLimit(ctx, snapshot, mps[i], false, 0)
+			release()
+			if summary == "" {
+				return "", fmt.Errorf("failed to summarize package %s", pkgPath)
+			}
+			return summary, nil
+		}
+		release()
+	}
+	return "", mcp.ResourceNotFoundError(uri)
+}
+
+// readSymbolResource returns the definition and doc comment of the symbol
+// of a gopls://symbol/{importpath}/{name} URI.
+func readSymbolResource(ctx context.Context, h *Handler, uri string) (string, error) {
+	arg, err := resourceArg(uri, symbolResourcePrefix)
+	if err != nil {
+		return "", err
+	}
+	i := strings.LastIndex(arg, "/")
+	if i <= 0 || i == len(arg)-1 {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	pkgPath, name := arg[:i], arg[i+1:]
+
+	locator, viewDir, err := h.resolveLocator(ctx, api.SymbolLocator{QualifiedName: pkgPath + "." + name})
+	if err != nil {
+		return "", err
+	}
+	snapshot, release, err := h.snapshotForDir(viewDir)
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	info, err := golang.ResolveSymbol(ctx, snapshot, locator, golang.ResolveOptions{
+		FindDefinitions:   true,
+		IncludeDefinition: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve symbol %s.%s: %w", pkgPath, name, err)
+	}
+	if info.Definition == nil || len(info.Locations) == 0 {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	def := info.Definition
+	loc := info.Locations[0]
+	return fmt.Sprintf("Defined at %s:%d", loc.URI.Path(), loc.Range.Start.Line+1) + golang.FormatSymbolSummary(&api.Symbol{
+		Name:        def.Symbol,
+		Kind:        api.SymbolKind(def.Kind),
+		PackagePath: pkgPath,
+		Signature:   def.Signature,
+		Doc:         def.DocComment,
+		Body:        def.Snippet,
+	}) + "\n", nil
+}
+
+// readFileOutlineResource returns the outline of the file of a
+// gopls://file/{path}/outline URI.
+func readFileOutlineResource(ctx context.Context, h *Handler, uri string) (string, error) {
+	arg, err := resourceArg(uri, fileResourcePrefix)
+	if err != nil {
+		return "", err
+	}
+	rel, ok := strings.CutSuffix(arg, outlineResourceSuffix)
+	if !ok || rel == "" {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	path := filepath.FromSlash("/" + rel)
+
+	snapshot, release, err := h.snapshotForDir(filepath.Dir(path))
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := fh.Content(); err != nil {
+		return "", mcp.ResourceNotFoundError(uri)
+	}
+	symbols, err := golang.DocumentSymbols(ctx, snapshot, fh)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "Outline of %s (%d declaration(s)):\n\n", path, len(symbols))
+	writeOutline(&buf, symbols, "")
+	return buf.String(), nil
+}
+
+// writeOutline writes one line per symbol, with their children indented
+// below them.
+func writeOutline(buf *strings.Builder, symbols []protocol.DocumentSymbol, indent string) {
+	for _, sym := range symbols {
+		fmt.Fprintf(buf, "%s- %s `%s`", indent, golang.ConvertLSPSymbolKind(sym.Kind), sym.Name)
+		if sym.Detail != "" {
+			fmt.Fprintf(buf, " %s", sym.Detail)
+		}
+		fmt.Fprintf(buf, " (line %d)\n", sym.Range.Start.Line+1)
+		writeOutline(buf, sym.Children, indent+"  ")
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/util/moremaps"
 )
 
 // applyResponseLimits checks if a response exceeds max bytes and truncates if needed.
@@ -87,7 +90,8 @@ func truncateMap(data map[string]any, maxChars int) map[string]any {
 	result := make(map[string]any)
 	currentSize := 0
 
-	for key, value := range data {
+	// Visit the keys in order, so that the same fields survive every time.
+	for key, value := range moremaps.Sorted(data) {
 		// Skip metadata fields
 		if strings.HasPrefix(key, "_") {
 			result[key] = value
@@ -228,3 +232,113 @@ func WrapWithResponseLimits[In, Out any](
 		return result, output, nil
 	}
 }
+
+// setSummary sets the Summary field of out, if it has one, to the text
+// content of result.
+func setSummary[Out any](out Out, result *mcp.CallToolResult) {
+	rv := reflect.ValueOf(out)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return
+	}
+	f := rv.Elem().FieldByName("Summary")
+	if !f.IsValid() || f.Kind() != reflect.String || !f.CanSet() {
+		return
+	}
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			f.SetString(text.Text)
+			return
+		}
+	}
+}
+
+// limitOutput shrinks the structured output of a tool to about maxBytes of
+// JSON, the budget of its text. Unlike truncateMap, it keeps every field,
+// so that the output still matches the tool's output schema: it shortens
+// arrays and strings instead, in field order, and sets the output's
+// Truncated field if it has one.
+//
+// The summary is kept whole, so that it stays the same as the text it was
+// made from: the text has been cut to maxBytes already (see setSummary),
+// and the other fields get what is left of the budget.
+func limitOutput[Out any](out Out, maxBytes int) Out {
+	data, err := json.Marshal(out)
+	if err != nil || len(data) <= maxBytes {
+		return out
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return out
+	}
+	budget := (maxBytes * 80) / 100
+	obj, _ := v.(map[string]any)
+	summary, hasSummary := obj[summaryKey]
+	if hasSummary {
+		delete(obj, summaryKey)
+		budget -= estimateValueSize(summary) + len(summaryKey) + 4 // "key":,
+	}
+	shrunkValue := shrinkValue(v, max(budget, 0))
+	if hasSummary {
+		shrunkValue.(map[string]any)[summaryKey] = summary
+	}
+	data, err = json.Marshal(shrunkValue)
+	if err != nil {
+		return out
+	}
+	var shrunk Out
+	if err := json.Unmarshal(data, &shrunk); err != nil {
+		return out
+	}
+	if rv := reflect.Indirect(reflect.ValueOf(&shrunk).Elem()); rv.Kind() == reflect.Struct {
+		if f := rv.FieldByName("Truncated"); f.IsValid() && f.Kind() == reflect.Bool && f.CanSet() {
+			f.SetBool(true)
+		}
+	}
+	return shrunk
+}
+
+// summaryKey is the JSON name of the Summary field of tool outputs.
+const summaryKey = "summary"
+
+// shrinkValue returns a copy of the JSON value v cut down to about budget
+// bytes. Objects keep all their keys: numbers and booleans are kept, then
+// the other fields, in key order, get what is left of the budget.
+func shrinkValue(v any, budget int) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		used := 2 // {}
+		for key, val := range moremaps.Sorted(v) {
+			used += len(key) + 4 // "key":,
+			switch val.(type) {
+			case map[string]any, []any, string:
+			default:
+				out[key] = val
+				used += estimateValueSize(val)
+			}
+		}
+		for key, val := range moremaps.Sorted(v) {
+			if _, ok := out[key]; ok {
+				continue
+			}
+			out[key] = shrinkValue(val, max(budget-used, 0))
+			used += estimateValueSize(out[key])
+		}
+		return out
+	case []any:
+		// Keep the longest prefix that fits.
+		n := sort.Search(len(v), func(n int) bool {
+			return estimateArraySize(v[:n+1]) > budget
+		})
+		return v[:n]
+	case string:
+		if len(v) > budget {
+			cut := budget
+			for cut > 0 && !utf8.RuneStart(v[cut]) {
+				cut--
+			}
+			return v[:cut] + "..."
+		}
+	}
+	return v
+}
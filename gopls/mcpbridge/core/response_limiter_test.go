@@ -3,6 +3,9 @@ package core
 import (
 	"strings"
 	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/mcpbridge/api"
 )
 
 func TestEstimateResultSize(t *testing.T) {
@@ -277,3 +280,62 @@ func TestEstimateMapSize(t *testing.T) {
 		t.Errorf("estimateMapSize({}) = %d, want >= 0", gotEmpty)
 	}
 }
+
+func TestLimitOutput(t *testing.T) {
+	out := &api.OSymbolReferencesResult{Summary: strings.Repeat("x", 3000)}
+	for i := range 500 {
+		out.References = append(out.References, api.SymbolReference{File: "/ws/main.go", Line: i + 1, Column: 2, Kind: "read"})
+	}
+	out.TotalCount = len(out.References)
+
+	got := limitOutput(out, 8000)
+	if size, _ := jsonSize(got); size > 8000 {
+		t.Errorf("limitOutput() left %d bytes, want at most 8000", size)
+	}
+	if !got.Truncated {
+		t.Error("limitOutput() did not set Truncated")
+	}
+	if got.TotalCount != 500 {
+		t.Errorf("TotalCount = %d, want 500 (numbers are kept)", got.TotalCount)
+	}
+	if n := len(got.References); n == 0 || n == 500 || got.References[n-1].Line != n {
+		t.Errorf("References has %d entries, want a non-empty proper prefix", n)
+	}
+	if out.Truncated || len(out.References) != 500 {
+		t.Error("limitOutput() modified its argument")
+	}
+
+	// A small output is returned as is.
+	small := &api.OSymbolReferencesResult{Summary: "ok"}
+	if got := limitOutput(small, 8000); got != small {
+		t.Errorf("limitOutput(small) = %+v, want the same output", got)
+	}
+}
+
+// TestLimitOutput_KeepsSummary verifies that when both the text and the
+// structured output of a tool are over budget, the structured summary is
+// the text that is sent, not cut short by the fields before it.
+func TestLimitOutput_KeepsSummary(t *testing.T) {
+	const maxBytes = 8000
+	text := strings.Repeat("reference line\n", 1000)
+	out := &api.OSymbolReferencesResult{Summary: text}
+	for i := range 500 {
+		out.References = append(out.References, api.SymbolReference{File: "/ws/main.go", Line: i + 1, Column: 2, Kind: "read"})
+	}
+
+	// As the tool wrapper does.
+	result := applyResponseLimits(&mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, maxBytes, "go_symbol_references")
+	setSummary(out, result)
+	got := limitOutput(out, maxBytes)
+
+	sent := result.Content[0].(*mcp.TextContent).Text
+	if len(sent) >= len(text) {
+		t.Fatalf("text was not truncated (%d bytes)", len(sent))
+	}
+	if got.Summary == "" || got.Summary != sent {
+		t.Errorf("Summary = %d bytes %q..., want the %d bytes of text sent", len(got.Summary), got.Summary[:min(len(got.Summary), 20)], len(sent))
+	}
+	if !got.Truncated || len(got.References) == 500 {
+		t.Errorf("References were not shortened (%d entries, Truncated=%v)", len(got.References), got.Truncated)
+	}
+}
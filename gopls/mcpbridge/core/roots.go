@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"log"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
+)
+
+// ===== Workspace roots =====
+//
+// The client's MCP roots are the workspace folders it has open. Each root
+// gets a gopls view and a file watcher of its own, next to the -workdir
+// folder, so that one gopls-mcp process can serve several repositories.
+// Roots are listed when a client finishes initializing and again whenever
+// it reports that they changed (notifications/roots/list_changed); folders
+// are then added or removed to match. When the client disconnects, its
+// roots are dropped. A root inside another root gets no folder of its own,
+// as the outer one already watches its files.
+
+// FolderFunc adds the workspace folder dir to session, with its own view and
+// file watcher, and returns a function that removes them again.
+type FolderFunc func(ctx context.Context, session *cache.Session, dir string) (remove func(), err error)
+
+// WithFolders sets how client roots are turned into workspace folders.
+// Without it, roots are ignored.
+func WithFolders(fn FolderFunc) HandlerOption {
+	return func(h *Handler) {
+		h.addFolder = fn
+	}
+}
+
+// ServerOptions returns the MCP server options that make the server follow
+// the roots of its clients and accept resource subscriptions.
+func (h *Handler) ServerOptions() *mcp.ServerOptions {
+	return &mcp.ServerOptions{
+		SubscribeHandler:   h.subscribeResource,
+		UnsubscribeHandler: h.unsubscribeResource,
+		// The server must not send requests from within a notification
+		// handler, so roots are listed asynchronously.
+		InitializedHandler: func(_ context.Context, req *mcp.InitializedRequest) {
+			go h.followRoots(req.Session)
+		},
+		RootsListChangedHandler: func(_ context.Context, req *mcp.RootsListChangedRequest) {
+			h.rootsMu.Lock()
+			defer h.rootsMu.Unlock()
+			if changed, ok := h.rootsChanged[req.Session.ID()]; ok {
+				select {
+				case changed <- struct{}{}:
+				default: // already pending
+				}
+			}
+		},
+	}
+}
+
+// followRoots applies the roots of the client of ss, lists them again
+// whenever they change, and drops them once the client disconnects. It is
+// the only one to set the roots of ss, so that a late answer cannot bring
+// back the roots of a client that is gone.
+func (h *Handler) followRoots(ss *mcp.ServerSession) {
+	if params := ss.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.RootsV2 == nil {
+		return // client does not support roots
+	}
+	id := ss.ID()
+	changed := make(chan struct{}, 1)
+	h.rootsMu.Lock()
+	h.rootsChanged[id] = changed
+	h.rootsMu.Unlock()
+	closed := make(chan struct{})
+	go func() {
+		ss.Wait()
+		close(closed)
+	}()
+
+	for {
+		h.listRoots(ss)
+		select {
+		case <-changed:
+		case <-closed:
+			h.rootsMu.Lock()
+			delete(h.rootsChanged, id)
+			h.rootsMu.Unlock()
+			h.setRoots(id, nil)
+			return
+		}
+	}
+}
+
+// listRoots fetches the roots of the client of ss and applies them.
+func (h *Handler) listRoots(ss *mcp.ServerSession) {
+	res, err := ss.ListRoots(context.Background(), &mcp.ListRootsParams{})
+	if err != nil {
+		log.Printf("[gopls-mcp] Failed to list roots: %v", err)
+		return
+	}
+	h.setRoots(ss.ID(), rootDirs(res.Roots))
+}
+
+// rootDirs returns the directories of the file:// roots.
+func rootDirs(roots []*mcp.Root) []string {
+	var dirs []string
+	for _, root := range roots {
+		uri, err := protocol.ParseDocumentURI(root.URI)
+		if err != nil {
+			log.Printf("[gopls-mcp] Ignoring root %q: %v", root.URI, err)
+			continue
+		}
+		dirs = append(dirs, filepath.Clean(uri.Path()))
+	}
+	return dirs
+}
+
+// setRoots records the root directories of one client, replacing those it
+// reported before, and brings the folders of an active session in line with
+// the roots of all clients. Without an active session, the roots are applied
+// when the next one is created.
+func (h *Handler) setRoots(clientID string, dirs []string) {
+	h.initMu.Lock()
+	defer h.initMu.Unlock()
+	if len(dirs) == 0 {
+		delete(h.roots, clientID)
+	} else {
+		h.roots[clientID] = dirs
+	}
+	if h.session != nil {
+		h.syncFolders(context.Background())
+	}
+}
+
+// syncFolders adds a folder for every root that has none and removes the
+// folders of roots that are gone or inside another root. h.initMu must be
+// held.
+func (h *Handler) syncFolders(ctx context.Context) {
+	if h.addFolder == nil {
+		return
+	}
+	roots := make(map[string]bool)
+	for _, dirs := range h.roots {
+		for _, dir := range dirs {
+			roots[dir] = true
+		}
+	}
+	want := make(map[string]bool)
+	for dir := range roots {
+		if !insideRoot(dir, roots) {
+			want[dir] = true
+		}
+	}
+	for dir, remove := range h.folders {
+		if !want[dir] {
+			remove()
+			delete(h.folders, dir)
+			log.Printf("[gopls-mcp] Removed workspace folder %s", dir)
+		}
+	}
+	for _, dir := range slices.Sorted(maps.Keys(want)) {
+		if _, ok := h.folders[dir]; ok {
+			continue
+		}
+		remove, err := h.addFolder(ctx, h.session, dir)
+		if err != nil {
+			log.Printf("[gopls-mcp] Failed to add workspace folder %s: %v", dir, err)
+			continue
+		}
+		h.folders[dir] = remove
+		log.Printf("[gopls-mcp] Added workspace folder %s", dir)
+	}
+}
+
+// removeFolders removes every folder added for a root, leaving the roots to
+// be applied to the next session. h.initMu must be held.
+func (h *Handler) removeFolders() {
+	for dir, remove := range h.folders {
+		remove()
+		delete(h.folders, dir)
+	}
+}
+
+// insideRoot reports whether dir is strictly inside one of roots.
+func insideRoot(dir string, roots map[string]bool) bool {
+	for root := range roots {
+		if root != dir && containsPath(root, dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -22,9 +22,24 @@ const (
 	ToolGoImplementation   = "go_implementation"
 	ToolGoSymbolReferences = "go_symbol_references"
 	ToolGetCallHierarchy   = "go_get_call_hierarchy"
+	ToolGoTypeHierarchy    = "go_type_hierarchy"
+	ToolGoCallPath         = "go_call_path"
 
 	// Refactoring tools
-	ToolGoDryrunRenameSymbol = "go_dryrun_rename_symbol"
+	ToolGoDryrunRenameSymbol       = "go_dryrun_rename_symbol"
+	ToolGoApplyRename              = "go_apply_rename"
+	ToolGoCodeActions              = "go_code_actions"
+	ToolGoDryrunCodeAction         = "go_dryrun_code_action"
+	ToolGoDryrunExtract            = "go_dryrun_extract"
+	ToolGoDryrunInline             = "go_dryrun_inline"
+	ToolGoDryrunChangeSignature    = "go_dryrun_change_signature"
+	ToolGoDryrunImplementInterface = "go_dryrun_implement_interface"
+	ToolGoDryrunAddTest            = "go_dryrun_add_test"
+	ToolGoDryrunModifyTags         = "go_dryrun_modify_tags"
+
+	// Edit validation
+	ToolGoCheckEdit   = "go_check_edit"
+	ToolGoDiagnostics = "go_diagnostics"
 
 	// Dependency analysis
 	ToolGetDependencyGraph = "go_get_dependency_graph"
@@ -38,36 +53,134 @@ const (
 var tools = []Tool{
 	GenericTool[api.ISymbolReferencesParams, *api.OSymbolReferencesResult]{
 		Name:        ToolGoSymbolReferences,
+		Title:       "Find References",
 		Description: "Find all usages of a symbol across the codebase using semantic location (symbol name, package, scope). Use this before refactoring to assess impact or to understand how a symbol is used. REPLACES: grep + manual file reading for finding references.",
 		Handler:     handleGoSymbolReferences,
 	},
 
 	GenericTool[api.IRenameSymbolParams, *api.ORenameSymbolResult]{
 		Name:        ToolGoDryrunRenameSymbol,
+		Title:       "Preview Rename",
 		Description: "Preview a symbol rename operation across all files (DRY RUN - no changes are applied). Use go_symbol_references first to assess impact, then use this to preview the exact changes that would be made. Returns a unified diff showing all proposed modifications.",
 		Handler:     handleGoRenameSymbol,
 	},
 
+	GenericTool[api.IApplyRenameParams, *api.OApplyRenameResult]{
+		Name:        ToolGoApplyRename,
+		Title:       "Apply Rename",
+		Description: "Rename a symbol across all files and WRITE the changes to disk. Refuses to write anything if a touched file no longer matches the content the rename was computed from, and returns a conflict report instead. Pass file_hashes from go_dryrun_rename_symbol as expected_hashes to apply exactly what was previewed.",
+		Handler:     handleGoApplyRename,
+		Destructive: true,
+	},
+
 	GenericTool[api.IImplementationParams, *api.OImplementationResult]{
 		Name:        ToolGoImplementation,
+		Title:       "Find Implementations",
 		Description: "Find all implementations of an interface or all interfaces implemented by a type using semantic location (symbol name, package, scope). Use this to understand type hierarchies, find all implementations of an interface, or discover design patterns in the codebase. REPLACES: grep + manual file reading for interface implementations.",
 		Handler:     handleGoImplementation,
 	},
 
 	GenericTool[api.IDefinitionParams, *api.ODefinitionResult]{
 		Name:        ToolGoDefinition,
+		Title:       "Go to Definition",
 		Description: "Jump to the definition of a symbol using semantic location (symbol name, package, scope). REPLACES: grep + manual file reading. Use this when you see a function call or type reference and need to find where it's defined. Faster and more accurate than text search - uses type information from gopls.",
 		Handler:     handleGoDefinition,
 	},
 
 	GenericTool[api.ICallHierarchyParams, *api.OCallHierarchyResult]{
 		Name:        ToolGetCallHierarchy,
+		Title:       "Call Hierarchy",
 		Description: "Get the call hierarchy for a function using semantic location (symbol name, package, scope). Returns both incoming calls (what functions call this one) and outgoing calls (what functions this one calls). Use this to understand code flow, debug call chains, and trace execution paths through the codebase. REPLACES: grep + manual file reading for call graph analysis.",
 		Handler:     handleGoCallHierarchy,
 	},
 
+	GenericTool[api.ICallPathParams, *api.OCallPathResult]{
+		Name:        ToolGoCallPath,
+		Title:       "Find Call Paths",
+		Description: "Find the shortest call paths from one function to another using semantic location (symbol name, package, scope) for both ends. Builds a whole-program call graph of the workspace packages (static, cha or vta), so paths go through interface method calls and function values as well as direct calls. Each hop lists the called function and the call site. Use this to answer \"can X ever reach Y, and how?\" instead of expanding the call hierarchy level by level.",
+		Handler:     handleGoCallPath,
+	},
+
+	GenericTool[api.ITypeHierarchyParams, *api.OTypeHierarchyResult]{
+		Name:        ToolGoTypeHierarchy,
+		Title:       "Type Hierarchy",
+		Description: "Get the type hierarchy for a type using semantic location (symbol name, package, scope). Returns a tree of supertypes (interfaces it implements, types it embeds) and subtypes (types that implement or embed it), expanded recursively up to max_depth, with the relation on each edge. Use this to understand embedding chains and interface layering beyond the single level reported by go_implementation.",
+		Handler:     handleGoTypeHierarchy,
+	},
+
+	GenericTool[api.ICodeActionsParams, *api.OCodeActionsResult]{
+		Name:        ToolGoCodeActions,
+		Title:       "List Code Actions",
+		Description: "List the quick fixes and refactorings gopls offers at a symbol (semantic locator, e.g. a call site) or on a range of lines: fixes for the diagnostics there (missing imports, undeclared names, missing methods, fill struct, ...), extract, inline, invert if, and more. Each action has a stable ID to pass to go_dryrun_code_action. Use this to discover compiler-checked edits instead of writing them by hand.",
+		Handler:     handleGoCodeActions,
+	},
+
+	GenericTool[api.IDryrunCodeActionParams, *api.ODryrunCodeActionResult]{
+		Name:        ToolGoDryrunCodeAction,
+		Title:       "Preview Code Action",
+		Description: "Preview the changes of a code action listed by go_code_actions (DRY RUN - no changes are applied). Takes the action's ID and returns a unified diff of every file it would modify or create.",
+		Handler:     handleGoDryrunCodeAction,
+	},
+
+	GenericTool[api.IExtractParams, *api.OExtractResult]{
+		Name:        ToolGoDryrunExtract,
+		Title:       "Preview Extract",
+		Description: "Preview extracting code to a new function, method, variable, constant, or file (DRY RUN - no changes are applied). Select whole lines with start_line/end_line, or exact text such as an expression with snippet. The compiler-checked refactoring works out parameters, results and imports; returns a unified diff and the free variables that became parameters. Use this instead of moving code by hand.",
+		Handler:     handleGoDryrunExtract,
+	},
+
+	GenericTool[api.IInlineParams, *api.OInlineResult]{
+		Name:        ToolGoDryrunInline,
+		Title:       "Preview Inline",
+		Description: "Preview inlining a function call (DRY RUN - no changes are applied). Locate the called function at a call site to inline that call, or locate its declaration to inline every call of it. The inliner keeps the behavior of each call (evaluation order, side effects, imports); returns a unified diff and the caveats, such as arguments it had to bind to variables. Use this instead of copying a function body by hand.",
+		Handler:     handleGoDryrunInline,
+	},
+
+	GenericTool[api.IChangeSignatureParams, *api.OChangeSignatureResult]{
+		Name:        ToolGoDryrunChangeSignature,
+		Title:       "Preview Change Signature",
+		Description: "Preview removing, reordering and adding parameters of a function or method and rewriting all its calls (DRY RUN - no changes are applied). params is the new parameter list: old parameters by old_index (leave one out to remove it), new ones by field (e.g. \"ctx context.Context\") and default (the argument existing calls pass). Calls are rewritten by the inliner, which keeps argument effects and order; returns a unified diff across all affected files and the calls it could not rewrite safely. Use this instead of editing every caller by hand.",
+		Handler:     handleGoDryrunChangeSignature,
+	},
+
+	GenericTool[api.IImplementInterfaceParams, *api.OImplementInterfaceResult]{
+		Name:        ToolGoDryrunImplementInterface,
+		Title:       "Preview Implement Interface",
+		Description: "Preview making a type implement an interface (DRY RUN - no changes are applied). With concrete, adds stubs of the methods the concrete type lacks, with the right signatures and imports. With fake_file, generates instead a fake struct of the interface in that file (created if missing) that records each call and delegates to an optional <Method>Func field. Returns a unified diff and the methods added. Use this to fix the implementations and fakes broken by a new interface method instead of writing the methods by hand.",
+		Handler:     handleGoDryrunImplementInterface,
+	},
+
+	GenericTool[api.IAddTestParams, *api.OAddTestResult]{
+		Name:        ToolGoDryrunAddTest,
+		Title:       "Preview Add Test",
+		Description: "Preview adding a test of a function or method (DRY RUN - no changes are applied). Generates a table-driven test skeleton in the right _test.go file (existing or new, in-package or _test package) with the imports, receiver construction and error handling worked out. If the function already has a test, adds a case to its table instead: test_case, or a skeleton case with every field. Returns a unified diff. Use this instead of writing test scaffolding by hand.",
+		Handler:     handleGoDryrunAddTest,
+	},
+
+	GenericTool[api.IModifyTagsParams, *api.OModifyTagsResult]{
+		Name:        ToolGoDryrunModifyTags,
+		Title:       "Preview Modify Tags",
+		Description: "Preview adding, removing or rewriting the struct tags of a struct type (DRY RUN - no changes are applied). Works on all fields or the named fields: add tag keys with values derived from the field names (transform: snakecase, camelcase, ...), remove keys, add or remove options such as json=omitempty, or clear them; overwrite rewrites existing values. Returns a unified diff and the fields whose tags change. Use this instead of editing backquoted tags by hand.",
+		Handler:     handleGoDryrunModifyTags,
+	},
+
+	GenericTool[api.ICheckEditParams, *api.OCheckEditResult]{
+		Name:        ToolGoCheckEdit,
+		Title:       "Check Edit",
+		Description: "Type-check a proposed edit to a Go file WITHOUT writing it to disk. Accepts either the complete new file content or a set of line replacements, applies it as an unsaved overlay, and returns the compiler and analyzer diagnostics for the file's package and its direct importers. The overlay is discarded afterwards. Use this to validate an edit semantically before writing it, instead of a full go build round trip.",
+		Handler:     handleGoCheckEdit,
+	},
+
+	GenericTool[api.IDiagnosticsParams, *api.ODiagnosticsResult]{
+		Name:        ToolGoDiagnostics,
+		Title:       "Diagnostics",
+		Description: "Report the current compile errors and analyzer (vet) findings for a file, a package (with its tests), or the whole workspace, grouped by package. Analyzers can be restricted by name (any gopls analyzer, e.g. printf, unusedresult, shadow) or skipped with type_errors_only, and results filtered by min_severity. Suggested fixes are shown as unified diffs but NOT applied. Use this after editing files on disk to find out what is broken, instead of running go build or go vet.",
+		Handler:     handleGoDiagnostics,
+	},
+
 	GenericTool[api.IDependencyGraphParams, *api.ODependencyGraphResult]{
 		Name:        ToolGetDependencyGraph,
+		Title:       "Package Dependency Graph",
 		Description: "Get the dependency graph for a package. Returns both dependencies (packages it imports) and dependents (packages that import it). Use this to understand architectural relationships, analyze coupling, and visualize the package's place in the codebase.",
 		Handler:     handleGetDependencyGraph,
 	},
@@ -79,6 +192,7 @@ func RegisterTools(server *mcp.Server, handler *Handler) int {
 	// Register the list_tools meta-tool first (special case to avoid init cycle)
 	GenericTool[api.IListToolsParams, *api.OListToolsResult]{
 		Name:        ToolListTools,
+		Title:       "List Tools",
 		Description: "List all available gopls-mcp tools with documentation and parameter schemas. These semantic analysis tools are type-aware (vs grep text matching) and operate on gopls's cached type information.",
 		Handler:     handleListTools,
 	}.Register(server, handler)
@@ -117,6 +231,18 @@ with its native tools. What it *cannot* do is resolve Go's type system, and that
 - **JSON Schema**: Each tool's input schema is available via the MCP protocol (not duplicated here)
 - **Tool Relationships**: Tools cross-reference each other - see "See also" sections
 
+### Resources
+
+Packages, symbols and file outlines are also available as MCP resources, to browse or
+attach as context. resources/list enumerates the workspace packages and their files.
+Package and file outline resources can be subscribed to: the client is notified when a
+file change on disk touches them. After each batch of changes, the type errors that
+appeared or were cleared are also sent as a log message (logger ` + "`gopls-mcp/diagnostics`" + `).
+
+- ` + "`gopls://package/{importpath}`" + `: exported API of a package, without function bodies
+- ` + "`gopls://symbol/{importpath}/{name}`" + `: definition and doc of a symbol (` + "`Name`" + ` or ` + "`Type.Method`" + `)
+- ` + "`gopls://file/{path}/outline`" + `: declarations of a file (absolute path without its leading slash)
+
 ---
 
 `)
@@ -151,10 +277,23 @@ func GenerateCLAUDEToolReference() (string, error) {
 	}{
 		{"Find interface implementations", "go_implementation"},
 		{"Trace call relationships", "go_get_call_hierarchy"},
+		{"Find call paths between functions", "go_call_path"},
+		{"Explore type hierarchies", "go_type_hierarchy"},
 		{"Find symbol references", "go_symbol_references"},
 		{"Jump to definition", "go_definition"},
 		{"Analyze dependencies", "go_get_dependency_graph"},
 		{"Preview renaming", "go_dryrun_rename_symbol"},
+		{"Apply a previewed rename", "go_apply_rename"},
+		{"List quick fixes and refactorings", "go_code_actions"},
+		{"Preview a code action", "go_dryrun_code_action"},
+		{"Preview extracting a function or variable", "go_dryrun_extract"},
+		{"Preview inlining a call", "go_dryrun_inline"},
+		{"Preview changing a function's parameters", "go_dryrun_change_signature"},
+		{"Preview implementing an interface or a fake", "go_dryrun_implement_interface"},
+		{"Preview adding a test or a test case", "go_dryrun_add_test"},
+		{"Preview struct tag changes", "go_dryrun_modify_tags"},
+		{"Validate an edit before writing it", "go_check_edit"},
+		{"Check compile errors and vet findings", "go_diagnostics"},
 	}
 
 	for _, entry := range entries {
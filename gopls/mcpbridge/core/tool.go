@@ -2,17 +2,27 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"reflect"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/mcpbridge/api"
 )
 
 // GenericTool is a type-safe wrapper for MCP tools that use our Handler pattern.
 // This bridges the MCP SDK to gopls's session/snapshot APIs via the Handler type.
 type GenericTool[In, Out any] struct {
-	Name        string
+	Name string
+	// Title is a short human-readable name for clients to display.
+	Title       string
 	Description string
+	// Destructive marks the tools that write files. All the others only
+	// query the workspace, and are annotated read-only so that clients can
+	// approve them automatically.
+	Destructive bool
 	// Handler takes a Handler with access to gopls session/snapshot
 	// Note: Out is typically a pointer type like *api.OGoInfo, so we return Out not *Out
 	Handler func(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error)
@@ -23,21 +33,27 @@ type Tool interface {
 	Docs() (string, error)
 	Register(server *mcp.Server, handler *Handler)
 	Details() (string, string)
+	Schemas() (input, output *jsonschema.Schema, err error)
 }
 
 const defaultMaxResponseBytes = 32 * 1024 // 32KB
 
+// maxResponseBytes returns the size limit of the responses of h's tools
+// and resources.
+func (h *Handler) maxResponseBytes() int {
+	if h.config == nil || h.config.MaxResponseBytes == 0 {
+		return defaultMaxResponseBytes
+	}
+	return h.config.MaxResponseBytes
+}
+
 // Register registers the tool with the MCP server using a Handler.
 // The Handler provides access to gopls's session and snapshot.
 // Automatically applies response size limits from handler config.
 func (t GenericTool[In, Out]) Register(server *mcp.Server, handler *Handler) {
-	// Get max bytes from config
-	maxBytes := handler.config.MaxResponseBytes
-	// set max bytes limit to prevent response consumes too many user tokens,
-	// as they are input tokens user need to pay for.
-	if maxBytes == 0 {
-		maxBytes = defaultMaxResponseBytes
-	}
+	// Limit the response size to prevent responses consuming too many user
+	// tokens, as they are input tokens users need to pay for.
+	maxBytes := handler.maxResponseBytes()
 
 	// Create a wrapper function that applies lazy init and response limits
 	wrapped := func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
@@ -46,20 +62,47 @@ func (t GenericTool[In, Out]) Register(server *mcp.Server, handler *Handler) {
 			return nil, zero, err
 		}
 		defer handler.resetIdleTimer()
+		if t.Name != ToolGoCheckEdit { // it takes the write lock itself
+			handler.overlayMu.RLock()
+			defer handler.overlayMu.RUnlock()
+		}
 
 		result, output, err := t.Handler(ctx, handler, req, input)
 		if err != nil {
+			// Symbol resolution failures carry candidates for a retry;
+			// render them instead of the bare error message.
+			var rerr *api.ResolutionError
+			if errors.As(err, &rerr) {
+				err = errors.New(formatResolutionError(err, rerr))
+			}
 			return result, output, err
 		}
 
-		// Apply response limits to ALL tools
+		// Apply response limits to ALL tools, to the text and to the
+		// structured output that the SDK sends along with it.
 		if result != nil {
+			size := estimateResultSize(result)
 			result = applyResponseLimits(result, maxBytes, t.Name)
+			if estimateResultSize(result) != size {
+				// The text was cut; cut the summary it came from alike.
+				setSummary(output, result)
+			}
 		}
+		output = limitOutput(output, maxBytes)
 
 		return result, output, nil
 	}
-	mcp.AddTool(server, &mcp.Tool{Name: t.Name, Description: t.Description}, wrapped)
+	_, outputSchema, err := t.Schemas()
+	if err != nil {
+		panic(fmt.Sprintf("tool %s: %v", t.Name, err)) // a bug in the api types
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:         t.Name,
+		Title:        t.Title,
+		Description:  t.Description,
+		OutputSchema: outputSchema,
+		Annotations:  t.annotations(),
+	}, wrapped)
 
 	log.Printf("[gopls-mcp] Registered tool %s: %s (max_bytes=%d)", t.Name, t.Description, maxBytes)
 }
@@ -69,6 +112,43 @@ func (t GenericTool[In, Out]) Details() (name, description string) {
 	return t.Name, t.Description
 }
 
+// Schemas returns the JSON schemas of the tool's input and output, derived
+// from the In and Out types as the SDK does.
+func (t GenericTool[In, Out]) Schemas() (input, output *jsonschema.Schema, err error) {
+	if input, err = schemaFor[In](); err != nil {
+		return nil, nil, err
+	}
+	if output, err = schemaFor[Out](); err != nil {
+		return nil, nil, err
+	}
+	return input, output, nil
+}
+
+// schemaFor returns the JSON schema of T, or of the type T points to.
+func schemaFor[T any]() (*jsonschema.Schema, error) {
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	return jsonschema.ForType(rt, &jsonschema.ForOptions{})
+}
+
+// annotations returns the MCP annotations of the tool. Every tool works on
+// the workspace only, a closed world.
+func (t GenericTool[In, Out]) annotations() *mcp.ToolAnnotations {
+	closed := false
+	annotations := &mcp.ToolAnnotations{
+		Title:         t.Title,
+		ReadOnlyHint:  !t.Destructive,
+		OpenWorldHint: &closed,
+	}
+	if t.Destructive {
+		destructive := true
+		annotations.DestructiveHint = &destructive
+	}
+	return annotations
+}
+
 func (t GenericTool[In, Out]) Docs() (string, error) {
 	doc, ok := docMap[t.Name]
 	if !ok {
@@ -0,0 +1,426 @@
+package core
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/parsego"
+	"golang.org/x/tools/gopls/internal/file"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== go_type_hierarchy =====
+// Origin: gopls/internal/golang/type_hierarchy.go PrepareTypeHierarchy(), Supertypes(), Subtypes()
+
+const (
+	// defaultTypeHierarchyDepth is the number of levels expanded when max_depth is unset.
+	defaultTypeHierarchyDepth = 3
+	// maxTypeHierarchyDepth caps max_depth to keep responses bounded.
+	maxTypeHierarchyDepth = 10
+)
+
+// typeHierarchyDirections are the accepted values of direction.
+var typeHierarchyDirections = []string{"supertypes", "subtypes", "both"}
+
+func handleGoTypeHierarchy(ctx context.Context, h *Handler, req *mcp.CallToolRequest, input api.ITypeHierarchyParams) (*mcp.CallToolResult, *api.OTypeHierarchyResult, error) {
+	direction := cmp.Or(input.Direction, "both")
+	if !slices.Contains(typeHierarchyDirections, direction) {
+		return nil, nil, fmt.Errorf("invalid direction %q: want one of %s", input.Direction, strings.Join(typeHierarchyDirections, ", "))
+	}
+
+	locator, viewDir, err := h.resolveLocator(ctx, input.Locator)
+	if err != nil {
+		return nil, nil, err
+	}
+	input.Locator = locator
+
+	snapshot, release, err := h.snapshotForDir(viewDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get snapshot for %s: %w", viewDir, err)
+	}
+	defer release()
+
+	uri := protocol.URIFromPath(input.Locator.ContextFile)
+	fh, err := snapshot.ReadFile(ctx, uri)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	nodeResult, err := golang.ResolveNode(ctx, snapshot, fh, input.Locator)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve symbol '%s': %w", input.Locator.SymbolName, err)
+	}
+
+	pkg, _, err := golang.NarrowestPackageForFile(ctx, snapshot, uri)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get package: %w", err)
+	}
+
+	posn := safetoken.StartPosition(pkg.FileSet(), nodeResult.Pos)
+	if !posn.IsValid() {
+		return nil, nil, fmt.Errorf("invalid position for symbol '%s'", input.Locator.SymbolName)
+	}
+
+	position := protocol.Position{
+		Line:      uint32(posn.Line - 1),
+		Character: uint32(posn.Column - 1),
+	}
+
+	items, err := golang.PrepareTypeHierarchy(ctx, snapshot, fh, protocol.Range{Start: position, End: position})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to prepare type hierarchy for '%s': %w", input.Locator.SymbolName, err)
+	}
+
+	if len(items) == 0 {
+		summary := fmt.Sprintf("No type found for symbol '%s' in %s",
+			input.Locator.SymbolName, input.Locator.ContextFile)
+		result := &api.OTypeHierarchyResult{Summary: summary}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: summary}}}, result, nil
+	}
+
+	maxDepth := input.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultTypeHierarchyDepth
+	}
+	maxDepth = min(maxDepth, maxTypeHierarchyDepth)
+
+	root := items[0]
+	w := &typeHierarchyWalker{ctx: ctx, snapshot: snapshot, maxDepth: maxDepth}
+
+	result := &api.OTypeHierarchyResult{
+		Symbol:   w.symbol(root),
+		Warnings: resolutionWarnings(nodeResult.Warning),
+	}
+
+	if direction == "supertypes" || direction == "both" {
+		result.Supertypes = w.walk(root, true)
+	}
+	if direction == "subtypes" || direction == "both" {
+		result.Subtypes = w.walk(root, false)
+	}
+
+	var summary strings.Builder
+	summary.WriteString(formatResolutionWarnings(result.Warnings))
+	fmt.Fprintf(&summary, "Type hierarchy for %s (%s) at %s:%d\n\n", result.Symbol.Name, result.Symbol.Kind, result.Symbol.FilePath, result.Symbol.Line)
+	if direction == "supertypes" || direction == "both" {
+		summary.WriteString(formatTypeHierarchySection("Supertypes", result.Supertypes))
+	}
+	if direction == "both" {
+		summary.WriteString("\n")
+	}
+	if direction == "subtypes" || direction == "both" {
+		summary.WriteString(formatTypeHierarchySection("Subtypes", result.Subtypes))
+	}
+
+	result.Summary = summary.String()
+
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: result.Summary}}}, result, nil
+}
+
+// typeHierarchyWalker expands type hierarchy items recursively in one direction.
+type typeHierarchyWalker struct {
+	ctx      context.Context
+	snapshot *cache.Snapshot
+	maxDepth int
+
+	// seen holds the items already placed in the tree for the current direction,
+	// so that cycles and diamonds are reported once and not expanded again.
+	seen map[string]bool
+}
+
+// typeEdge is a related type together with its relation to the type it was found from.
+type typeEdge struct {
+	item     protocol.TypeHierarchyItem
+	relation api.TypeRelation
+}
+
+// walk returns the supertypes (super=true) or subtypes of root, expanded up to
+// maxDepth levels and flattened in depth-first pre-order.
+func (w *typeHierarchyWalker) walk(root protocol.TypeHierarchyItem, super bool) []api.TypeHierarchyNode {
+	w.seen = map[string]bool{typeItemKey(root): true}
+	var nodes []api.TypeHierarchyNode
+	w.expand(&nodes, root, super, 0, 1)
+	return nodes
+}
+
+// expand appends the related types of item to nodes as children of parentID.
+func (w *typeHierarchyWalker) expand(nodes *[]api.TypeHierarchyNode, item protocol.TypeHierarchyItem, super bool, parentID, depth int) {
+	if depth > w.maxDepth {
+		return
+	}
+
+	for _, edge := range w.related(item, super) {
+		node := api.TypeHierarchyNode{
+			ID:       len(*nodes) + 1,
+			ParentID: parentID,
+			Depth:    depth,
+			Relation: edge.relation,
+			Symbol:   w.symbol(edge.item),
+		}
+		key := typeItemKey(edge.item)
+		node.Seen = w.seen[key]
+		w.seen[key] = true
+		*nodes = append(*nodes, node)
+		if !node.Seen {
+			w.expand(nodes, edge.item, super, node.ID, depth+1)
+		}
+	}
+}
+
+// related returns the direct supertypes or subtypes of item.
+// Embedding relations come first and win over the method-set relation
+// when the same type is related both ways (e.g. an embedded interface).
+func (w *typeHierarchyWalker) related(item protocol.TypeHierarchyItem, super bool) []typeEdge {
+	fh, err := w.snapshot.ReadFile(w.ctx, item.URI)
+	if err != nil {
+		return nil
+	}
+
+	var edges []typeEdge
+	if super {
+		for _, emb := range w.embeds(item) {
+			edges = append(edges, typeEdge{emb, api.TypeRelationEmbeds})
+		}
+		if supers, err := golang.Supertypes(w.ctx, w.snapshot, fh, item); err == nil {
+			for _, s := range supers {
+				edges = append(edges, typeEdge{s, api.TypeRelationImplements})
+			}
+		}
+	} else {
+		for _, emb := range w.embeddedBy(fh, item) {
+			edges = append(edges, typeEdge{emb, api.TypeRelationEmbeddedBy})
+		}
+		if subs, err := golang.Subtypes(w.ctx, w.snapshot, fh, item); err == nil {
+			for _, s := range subs {
+				edges = append(edges, typeEdge{s, api.TypeRelationImplementedBy})
+			}
+		}
+	}
+
+	deduped := make([]typeEdge, 0, len(edges))
+	keys := make(map[string]bool)
+	for _, edge := range edges {
+		key := typeItemKey(edge.item)
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		deduped = append(deduped, edge)
+	}
+	return deduped
+}
+
+// embeds returns the types embedded by the struct or interface declared at item.
+func (w *typeHierarchyWalker) embeds(item protocol.TypeHierarchyItem) []protocol.TypeHierarchyItem {
+	pkg, pgf, err := golang.NarrowestPackageForFile(w.ctx, w.snapshot, item.URI)
+	if err != nil {
+		return nil
+	}
+	pos, err := pgf.PositionPos(item.Range.Start)
+	if err != nil {
+		return nil
+	}
+	cur, ok := pgf.Cursor().FindByPos(pos, pos)
+	if !ok {
+		return nil
+	}
+	id, ok := cur.Node().(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	tname, ok := pkg.TypesInfo().ObjectOf(id).(*types.TypeName)
+	if !ok {
+		return nil
+	}
+
+	var embedded []types.Type
+	switch u := tname.Type().Underlying().(type) {
+	case *types.Struct:
+		for i := range u.NumFields() {
+			if f := u.Field(i); f.Embedded() {
+				embedded = append(embedded, f.Type())
+			}
+		}
+	case *types.Interface:
+		for i := range u.NumEmbeddeds() {
+			embedded = append(embedded, u.EmbeddedType(i))
+		}
+	}
+
+	var items []protocol.TypeHierarchyItem
+	for _, t := range embedded {
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		// Named types and aliases have a declaring object;
+		// unions and other constraint terms do not.
+		named, ok := t.(interface{ Obj() *types.TypeName })
+		if !ok {
+			continue
+		}
+		if _, ok := t.(*types.TypeParam); ok {
+			continue
+		}
+		obj := named.Obj()
+		loc, err := golang.ObjectLocation(w.ctx, pkg.FileSet(), w.snapshot, obj)
+		if err != nil {
+			continue
+		}
+		pkgpath := "builtin"
+		if obj.Pkg() != nil {
+			pkgpath = obj.Pkg().Path()
+		}
+		items = append(items, protocol.TypeHierarchyItem{
+			Name:           obj.Name(),
+			Kind:           typeHierarchyKind(types.IsInterface(obj.Type())),
+			Detail:         pkgpath,
+			URI:            loc.URI,
+			Range:          loc.Range,
+			SelectionRange: loc.Range,
+		})
+	}
+	return items
+}
+
+// embeddedBy returns the struct and interface types that embed the type declared at item.
+// It inspects every reference to the type and keeps those that are embedded fields.
+func (w *typeHierarchyWalker) embeddedBy(fh file.Handle, item protocol.TypeHierarchyItem) []protocol.TypeHierarchyItem {
+	refs, err := golang.References(w.ctx, w.snapshot, fh, item.Range, false)
+	if err != nil {
+		return nil
+	}
+
+	var items []protocol.TypeHierarchyItem
+	for _, ref := range refs {
+		refFh, err := w.snapshot.ReadFile(w.ctx, ref.URI)
+		if err != nil {
+			continue
+		}
+		pgf, err := w.snapshot.ParseGo(w.ctx, refFh, parsego.Full)
+		if err != nil {
+			continue
+		}
+		pos, err := pgf.PositionPos(ref.Range.Start)
+		if err != nil {
+			continue
+		}
+		cur, ok := pgf.Cursor().FindByPos(pos, pos)
+		if !ok {
+			continue
+		}
+		spec := embeddingTypeSpec(cur)
+		if spec == nil {
+			continue
+		}
+		loc, err := pgf.NodeLocation(spec.Name)
+		if err != nil {
+			continue
+		}
+		_, isInterface := spec.Type.(*ast.InterfaceType)
+		items = append(items, protocol.TypeHierarchyItem{
+			Name:           spec.Name.Name,
+			Kind:           typeHierarchyKind(isInterface),
+			Detail:         pkgPathForFile(w.ctx, w.snapshot, ref.URI),
+			URI:            loc.URI,
+			Range:          loc.Range,
+			SelectionRange: loc.Range,
+		})
+	}
+	return items
+}
+
+// symbol converts a type hierarchy item into a rich api.Symbol.
+func (w *typeHierarchyWalker) symbol(item protocol.TypeHierarchyItem) api.Symbol {
+	return buildRichSymbol(w.ctx, w.snapshot, item.Name, item.Kind, item.URI, item.Range, item.Detail)
+}
+
+// typeItemKey identifies a type hierarchy item by its declaring position.
+func typeItemKey(item protocol.TypeHierarchyItem) string {
+	return fmt.Sprintf("%s:%d:%d", item.URI, item.Range.Start.Line, item.Range.Start.Character)
+}
+
+// typeHierarchyKind returns the LSP symbol kind used for interface and concrete types.
+func typeHierarchyKind(isInterface bool) protocol.SymbolKind {
+	if isInterface {
+		return protocol.Interface
+	}
+	return protocol.Class
+}
+
+// embeddingTypeSpec reports the type declaration that embeds the type name at cur,
+// or nil if cur is not the type of an embedded field.
+//
+// It accepts qualified (pkg.T), pointer (*T) and instantiated (T[int]) forms.
+func embeddingTypeSpec(cur inspector.Cursor) *ast.TypeSpec {
+	child := cur.Node()
+	for parent := range cur.Enclosing() {
+		n := parent.Node()
+		if n == child {
+			continue
+		}
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if n.Sel != child {
+				return nil
+			}
+		case *ast.IndexExpr:
+			if n.X != child {
+				return nil
+			}
+		case *ast.IndexListExpr:
+			if n.X != child {
+				return nil
+			}
+		case *ast.StarExpr:
+		case *ast.Field:
+			if len(n.Names) > 0 {
+				return nil
+			}
+		case *ast.FieldList:
+		case *ast.StructType, *ast.InterfaceType:
+		case *ast.TypeSpec:
+			if n.Type != child {
+				return nil
+			}
+			return n
+		default:
+			return nil
+		}
+		child = n
+	}
+	return nil
+}
+
+// formatTypeHierarchySection formats one direction of the type hierarchy as an indented tree.
+func formatTypeHierarchySection(title string, nodes []api.TypeHierarchyNode) string {
+	if len(nodes) == 0 {
+		return title + ": None\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:\n", title)
+	for _, node := range nodes {
+		indent := strings.Repeat("  ", node.Depth)
+		fmt.Fprintf(&b, "%s- [%s] %s (%s)", indent, node.Relation, node.Symbol.Name, node.Symbol.Kind)
+		if node.Symbol.PackagePath != "" {
+			fmt.Fprintf(&b, " in %s", node.Symbol.PackagePath)
+		}
+		fmt.Fprintf(&b, " at %s:%d", node.Symbol.FilePath, node.Symbol.Line)
+		if node.Seen {
+			b.WriteString(" (already shown)")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
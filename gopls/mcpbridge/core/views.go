@@ -0,0 +1,166 @@
+package core
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"slices"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/cache/metadata"
+	"golang.org/x/tools/gopls/internal/golang"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/util/safetoken"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+// ===== Multi-view queries =====
+//
+// A workspace with several modules and no go.work has one view per module.
+// A locator is resolved in the view that owns its file (see resolveLocator),
+// but the symbol may also be used by the other modules, which only their own
+// views can see. Cross-module queries (references, implementations, rename)
+// therefore also run in every view whose module depends on the symbol's
+// package, and merge the results.
+
+// forEachDependentView calls fn for each view, other than snapshot's, that
+// loads the package declaring the symbol of locator from the same files,
+// passing a snapshot of that view and the locator anchored at the symbol's
+// declaration, which resolves there even if locator.ContextFile does not.
+//
+// The symbol's own view has already been queried by the caller, so errors
+// from the other views are logged and skipped rather than failing the tool.
+func (h *Handler) forEachDependentView(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator, fn func(*cache.Snapshot, api.SymbolLocator) error) {
+	if h.session == nil || len(h.session.Views()) < 2 {
+		return
+	}
+	decl, pkgPath, err := golang.DeclarationLocator(ctx, snapshot, locator)
+	if err != nil {
+		return // e.g. a local variable or a builtin: nothing to share
+	}
+	declURI := protocol.URIFromPath(decl.ContextFile)
+
+	for _, view := range h.session.Views() {
+		if view == snapshot.View() {
+			continue
+		}
+		other, release, err := view.Snapshot()
+		if err != nil {
+			continue
+		}
+		if loadsPackageFile(ctx, other, metadata.PackagePath(pkgPath), declURI) {
+			if err := fn(other, decl); err != nil {
+				log.Printf("[gopls-mcp] Skipping view %s for %q: %v", view.Root().Path(), locator.SymbolName, err)
+			}
+		}
+		release()
+	}
+}
+
+// loadsPackageFile reports whether snapshot's metadata graph has the package
+// pkgPath with uri among its files. Checking the file, not just the path,
+// keeps unrelated modules that reuse an import path out of the results.
+func loadsPackageFile(ctx context.Context, snapshot *cache.Snapshot, pkgPath metadata.PackagePath, uri protocol.DocumentURI) bool {
+	graph, err := snapshot.LoadMetadataGraph(ctx)
+	if err != nil {
+		return false
+	}
+	for _, mp := range graph.ForPackagePath[pkgPath] {
+		if slices.Contains(mp.CompiledGoFiles, uri) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeLocations sorts locations and removes duplicates, which are expected
+// when several views see the same file.
+func mergeLocations(locations []protocol.Location) []protocol.Location {
+	slices.SortFunc(locations, protocol.CompareLocation)
+	return slices.Compact(locations)
+}
+
+// mergeSourceContexts appends the entries of more that are not already in
+// contexts, identifying entries by symbol and declaration position.
+func mergeSourceContexts(contexts, more []golang.SourceContext) []golang.SourceContext {
+	for _, c := range more {
+		if !slices.ContainsFunc(contexts, func(x golang.SourceContext) bool {
+			return x.File == c.File && x.StartLine == c.StartLine && x.Symbol == c.Symbol
+		}) {
+			contexts = append(contexts, c)
+		}
+	}
+	return contexts
+}
+
+// mergeDocumentChanges adds the changes in more to changes. Text edits to a
+// file already in changes are folded into its edit, skipping edits it
+// already has, so that the result can still be applied file by file.
+func mergeDocumentChanges(changes, more []protocol.DocumentChange) []protocol.DocumentChange {
+	for _, c := range more {
+		if c.TextDocumentEdit == nil {
+			if !slices.ContainsFunc(changes, func(x protocol.DocumentChange) bool { return reflect.DeepEqual(x, c) }) {
+				changes = append(changes, c)
+			}
+			continue
+		}
+		i := slices.IndexFunc(changes, func(x protocol.DocumentChange) bool {
+			return x.TextDocumentEdit != nil && x.TextDocumentEdit.TextDocument.URI == c.TextDocumentEdit.TextDocument.URI
+		})
+		if i < 0 {
+			changes = append(changes, c)
+			continue
+		}
+		edit := *changes[i].TextDocumentEdit
+		edit.Edits = slices.Clone(edit.Edits)
+		for _, e := range c.TextDocumentEdit.Edits {
+			if !slices.ContainsFunc(edit.Edits, func(x protocol.Or_TextDocumentEdit_edits_Elem) bool { return reflect.DeepEqual(x, e) }) {
+				edit.Edits = append(edit.Edits, e)
+			}
+		}
+		changes[i].TextDocumentEdit = &edit
+	}
+	return changes
+}
+
+// renameChanges computes the document changes that rename the symbol of
+// locator to newName in snapshot's view and in every view that depends on
+// it. The warning is that of the resolution in snapshot.
+func (h *Handler) renameChanges(ctx context.Context, snapshot *cache.Snapshot, locator api.SymbolLocator, newName string) ([]protocol.DocumentChange, *api.ResolutionWarning, error) {
+	changes, warning, err := golang.LLMRenameChanges(ctx, snapshot, locator, newName)
+	if err != nil {
+		return nil, nil, err
+	}
+	h.forEachDependentView(ctx, snapshot, locator, func(other *cache.Snapshot, decl api.SymbolLocator) error {
+		more, _, err := golang.LLMRenameChanges(ctx, other, decl, newName)
+		if err != nil {
+			return err
+		}
+		changes = mergeDocumentChanges(changes, more)
+		return nil
+	})
+	return changes, warning, nil
+}
+
+// declarationReferences returns the references to the symbol declared at
+// decl, excluding the declaration itself, as seen by snapshot.
+func declarationReferences(ctx context.Context, snapshot *cache.Snapshot, decl api.SymbolLocator) ([]protocol.Location, error) {
+	fh, err := snapshot.ReadFile(ctx, protocol.URIFromPath(decl.ContextFile))
+	if err != nil {
+		return nil, err
+	}
+	nodeResult, err := golang.ResolveNode(ctx, snapshot, fh, decl)
+	if err != nil {
+		return nil, err
+	}
+	pkg, _, err := golang.NarrowestPackageForFile(ctx, snapshot, fh.URI())
+	if err != nil {
+		return nil, err
+	}
+	posn := safetoken.StartPosition(pkg.FileSet(), nodeResult.Pos)
+	position := protocol.Position{
+		Line:      uint32(posn.Line - 1),
+		Character: uint32(posn.Column - 1),
+	}
+	return golang.References(ctx, snapshot, fh, protocol.Range{Start: position, End: position}, false)
+}
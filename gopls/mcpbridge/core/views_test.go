@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/gopls/internal/cache"
+	"golang.org/x/tools/gopls/internal/protocol"
+	"golang.org/x/tools/gopls/internal/settings"
+	"golang.org/x/tools/gopls/mcpbridge/api"
+)
+
+func TestMergeDocumentChanges(t *testing.T) {
+	edit := func(line uint32, text string) protocol.TextEdit {
+		pos := protocol.Position{Line: line, Character: 5}
+		return protocol.TextEdit{Range: protocol.Range{Start: pos, End: pos}, NewText: text}
+	}
+	a := protocol.URIFromPath("/ws/liba/lib.go")
+	b := protocol.URIFromPath("/ws/app/main.go")
+
+	// The view of liba sees the declaration; the view of app sees the
+	// declaration again and its own use.
+	changes := []protocol.DocumentChange{
+		protocol.DocumentChangeEdit(fileHandle(a), []protocol.TextEdit{edit(2, "Sum")}),
+	}
+	more := []protocol.DocumentChange{
+		protocol.DocumentChangeEdit(fileHandle(a), []protocol.TextEdit{edit(2, "Sum")}),
+		protocol.DocumentChangeEdit(fileHandle(b), []protocol.TextEdit{edit(6, "Sum")}),
+	}
+	got := mergeDocumentChanges(changes, more)
+
+	if len(got) != 2 {
+		t.Fatalf("mergeDocumentChanges() returned %d changes, want 2: %+v", len(got), got)
+	}
+	for i, want := range []struct {
+		uri   protocol.DocumentURI
+		edits int
+	}{{a, 1}, {b, 1}} {
+		tde := got[i].TextDocumentEdit
+		if tde == nil || tde.TextDocument.URI != want.uri || len(tde.Edits) != want.edits {
+			t.Errorf("change %d = %+v, want %d edit(s) to %s", i, got[i], want.edits, want.uri)
+		}
+	}
+
+	// A new edit to a known file is folded into its existing change.
+	got = mergeDocumentChanges(got, []protocol.DocumentChange{
+		protocol.DocumentChangeEdit(fileHandle(a), []protocol.TextEdit{edit(9, "Sum")}),
+	})
+	if len(got) != 2 || len(got[0].TextDocumentEdit.Edits) != 2 {
+		t.Errorf("after folding: got %+v, want 2 changes with 2 edits to %s", got, a)
+	}
+}
+
+func TestMergeLocations(t *testing.T) {
+	loc := func(path string, line uint32) protocol.Location {
+		pos := protocol.Position{Line: line}
+		return protocol.Location{URI: protocol.URIFromPath(path), Range: protocol.Range{Start: pos, End: pos}}
+	}
+	got := mergeLocations([]protocol.Location{
+		loc("/ws/liba/lib.go", 7),
+		loc("/ws/app/main.go", 3),
+		loc("/ws/liba/lib.go", 7),
+	})
+	want := []protocol.Location{loc("/ws/app/main.go", 3), loc("/ws/liba/lib.go", 7)}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("mergeLocations() = %v, want %v", got, want)
+	}
+}
+
+// fileHandle is a minimal versioned file identity for DocumentChangeEdit.
+type fileHandle protocol.DocumentURI
+
+func (f fileHandle) URI() protocol.DocumentURI { return protocol.DocumentURI(f) }
+func (fileHandle) Version() int32              { return 0 }
+
+// TestResolveLocator_QualifiedNameAcrossViews verifies that a qualified
+// name without a context_file is resolved in the view of the module that
+// declares it, whichever view was created first and even if another view
+// also loads the package, as a dependency.
+func TestResolveLocator_QualifiedNameAcrossViews(t *testing.T) {
+	for _, order := range [][]string{{"app", "lib"}, {"lib", "app"}} {
+		t.Run(order[0]+"First", func(t *testing.T) {
+			root := t.TempDir()
+			writeTestFiles(t, root, map[string]string{
+				"lib/go.mod":  "module example.com/lib\n\ngo 1.21\n",
+				"lib/lib.go":  "package lib\n\nfunc F() {}\n",
+				"app/go.mod":  "module example.com/app\n\ngo 1.21\n\nrequire example.com/lib v0.0.0\n\nreplace example.com/lib => ../lib\n",
+				"app/main.go": "package main\n\nimport \"example.com/lib\"\n\nfunc main() { lib.F() }\n",
+			})
+			ctx := context.Background()
+			h := NewHandler(nil, WithOptions(settings.DefaultOptions()))
+			h.session = cache.NewSession(ctx, cache.New(nil))
+			t.Cleanup(func() { h.session.Shutdown(ctx) })
+			for _, dir := range order {
+				addTestFolder(t, h, filepath.Join(root, dir))
+			}
+
+			locator, viewDir, err := h.resolveLocator(ctx, api.SymbolLocator{QualifiedName: "example.com/lib.F"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := filepath.Join(root, "lib"); viewDir != want {
+				t.Errorf("viewDir = %s, want %s", viewDir, want)
+			}
+			if want := filepath.Join(root, "lib", "lib.go"); locator.ContextFile != want {
+				t.Errorf("ContextFile = %s, want %s", locator.ContextFile, want)
+			}
+		})
+	}
+}
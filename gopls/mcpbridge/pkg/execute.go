@@ -3,6 +3,7 @@ package pkg
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -72,9 +73,8 @@ const (
 	// allowDynamicViewsEnv is the environment variable name for enabling dynamic view creation.
 	// TEST-ONLY: This allows the handler to create new gopls views on-demand when a Cwd parameter
 	// doesn't match any existing view.
-	// WARNING: This is intended for e2e testing only. Normal users should not need this,
-	// as they typically work with a single project.
-	// Production usage: Run one gopls-mcp instance per project directory.
+	// WARNING: This is intended for e2e testing only. Normal users should not need this:
+	// the -workdir folder and the client's MCP roots each get a view.
 	allowDynamicViewsEnv = "GOPMCS_ALLOW_DYNAMIC_VIEWS"
 )
 
@@ -191,13 +191,8 @@ func Execute() {
 		log.Printf("[gopls-mcp] Warning: Failed to apply some gopls options: %v", err)
 	}
 
-	// Build directory skip function from directoryFilters so the file
-	// watcher excludes the same directories that gopls analysis ignores
-	// (e.g. node_modules). See https://github.com/xieyuschen/gopls-mcp/issues/10.
-	var watcherOpts []filewatcher.Option
-	if filters := options.DirectoryFilters; len(filters) > 0 {
-		watcherOpts = append(watcherOpts, makeDirectoryFilterSkipFunc(filters, projectDir))
-	}
+	// coreHandler is created below; the file watchers report changes to it.
+	var coreHandler *core.Handler
 
 	// initFn creates the gopls session, file watcher, and LSP server on first
 	// tool call (lazy init). Resources are released by shutdownResources after
@@ -206,26 +201,13 @@ func Execute() {
 		goplsCache := cache.New(nil)
 		session := cache.NewSession(ctx, goplsCache)
 
-		dirURI := protocol.URIFromPath(projectDir)
-		goEnv, err := cache.FetchGoEnv(ctx, dirURI, options)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to load Go env: %w", err)
-		}
-
-		folder := &cache.Folder{
-			Dir:     dirURI,
-			Options: options,
-			Env:     *goEnv,
+		if err := newView(ctx, session, projectDir, options); err != nil {
+			return nil, nil, nil, err
 		}
-		_, _, releaseView, err := session.NewView(ctx, folder)
-		if err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to create view for %s: %w", projectDir, err)
-		}
-		releaseView()
 
-		lspServer := &minimalServer{session: session}
+		lspServer := &minimalServer{session: session, changed: coreHandler.FilesChanged}
 
-		fw, err := watcher.New(lspServer, projectDir, watcherOpts...)
+		fw, err := newWatcher(lspServer, projectDir, options)
 		if err != nil {
 			log.Printf("[gopls-mcp] Failed to start file watcher: %v (file changes won't be detected)", err)
 			return session, lspServer, nil, nil
@@ -234,19 +216,42 @@ func Execute() {
 		return session, lspServer, fw, nil
 	}
 
+	// folderFn gives each root reported by the client a view and a file
+	// watcher of its own, next to those of projectDir.
+	folderFn := func(ctx context.Context, session *cache.Session, dir string) (func(), error) {
+		if err := newView(ctx, session, dir, options); err != nil {
+			if errors.Is(err, cache.ErrViewExists) {
+				return func() {}, nil // e.g. the root is projectDir
+			}
+			return nil, err
+		}
+		fw, err := newWatcher(&minimalServer{session: session, changed: coreHandler.FilesChanged}, dir, options)
+		if err != nil {
+			log.Printf("[gopls-mcp] Failed to start file watcher for %s: %v (file changes won't be detected)", dir, err)
+		}
+		return func() {
+			if fw != nil {
+				fw.Close()
+			}
+			session.RemoveView(context.Background(), protocol.URIFromPath(dir))
+		}, nil
+	}
+
 	// Create gopls-mcp handler with lazy init and idle timeout.
 	var handlerOpts []core.HandlerOption
 	handlerOpts = append(handlerOpts, core.WithConfig(config))
 	handlerOpts = append(handlerOpts, core.WithOptions(options))
+	handlerOpts = append(handlerOpts, core.WithFolders(folderFn))
 	if os.Getenv(allowDynamicViewsEnv) == "true" || os.Getenv(allowDynamicViewsEnv) == "1" {
 		log.Printf("[gopls-mcp] Dynamic views enabled via %s (TEST-ONLY)", allowDynamicViewsEnv)
 		handlerOpts = append(handlerOpts, core.WithDynamicViews(true))
 	}
-	coreHandler := core.NewHandler(initFn, handlerOpts...)
+	coreHandler = core.NewHandler(initFn, handlerOpts...)
 
 	// Create MCP server and register all gopls-mcp tools
-	server := mcp.NewServer(&mcp.Implementation{Name: mcpName, Version: version}, nil)
+	server := mcp.NewServer(&mcp.Implementation{Name: mcpName, Version: version}, coreHandler.ServerOptions())
 	log.Printf("[gopls-mcp] Registered %d MCP tools for Go analysis", core.RegisterTools(server, coreHandler))
+	core.RegisterResources(server, coreHandler)
 	log.Printf("[gopls-mcp] Working directory: %s", projectDir)
 
 	if *addr != "" {
@@ -291,6 +296,39 @@ func Execute() {
 	// Always exit cleanly - stdio mode ends when client closes connection
 }
 
+// newView adds a view of dir to session.
+func newView(ctx context.Context, session *cache.Session, dir string, options *settings.Options) error {
+	dirURI := protocol.URIFromPath(dir)
+	goEnv, err := cache.FetchGoEnv(ctx, dirURI, options)
+	if err != nil {
+		return fmt.Errorf("failed to load Go env: %w", err)
+	}
+
+	folder := &cache.Folder{
+		Dir:     dirURI,
+		Options: options,
+		Env:     *goEnv,
+	}
+	_, _, releaseView, err := session.NewView(ctx, folder)
+	if err != nil {
+		return fmt.Errorf("failed to create view for %s: %w", dir, err)
+	}
+	releaseView()
+	return nil
+}
+
+// newWatcher watches dir for changes on behalf of server.
+func newWatcher(server watcher.ChangeWatchedFiles, dir string, options *settings.Options) (*watcher.Watcher, error) {
+	// Build directory skip function from directoryFilters so the file
+	// watcher excludes the same directories that gopls analysis ignores
+	// (e.g. node_modules). See https://github.com/xieyuschen/gopls-mcp/issues/10.
+	var watcherOpts []filewatcher.Option
+	if filters := options.DirectoryFilters; len(filters) > 0 {
+		watcherOpts = append(watcherOpts, makeDirectoryFilterSkipFunc(filters, dir))
+	}
+	return watcher.New(server, dir, watcherOpts...)
+}
+
 func makeDirectoryFilterSkipFunc(filters []string, root string) filewatcher.Option {
 	pathIncluded := cache.PathIncludeFunc(filters)
 	cleanRoot := filepath.Clean(root)
@@ -14,6 +14,9 @@ import (
 // minimalServer is a wrapper around cache.Session that implements for DidChangeWatchedFiles.
 type minimalServer struct {
 	session *cache.Session
+	// changed, if set, is told about the files of each batch of changes
+	// once the session has processed them.
+	changed func(context.Context, []protocol.DocumentURI)
 }
 
 // Symbol implements workspace symbol search using gopls's internal golang package.
@@ -87,6 +90,14 @@ func (s *minimalServer) DidChangeWatchedFiles(ctx context.Context, params *proto
 		return fmt.Errorf("failed to process file changes: %w", err)
 	}
 
+	if s.changed != nil {
+		uris := make([]protocol.DocumentURI, len(params.Changes))
+		for i, change := range params.Changes {
+			uris[i] = change.URI
+		}
+		s.changed(ctx, uris)
+	}
+
 	return nil
 }
 
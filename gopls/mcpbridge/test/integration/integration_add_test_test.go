@@ -0,0 +1,117 @@
+package integration
+
+// End-to-end tests for go_dryrun_add_test functionality.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const addTestShout = `package lib
+
+import "strings"
+
+func Shout(s string) (string, error) {
+	return strings.ToUpper(s) + "!", nil
+}
+`
+
+const addTestCount = `package lib
+
+import "strings"
+
+func count(s string, sep rune) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, string(sep)) + 1
+}
+`
+
+const addTestCountTest = `package lib
+
+import "testing"
+
+func Test_count(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		sep  rune
+		want int
+	}{
+		{name: "empty", s: "", sep: ',', want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := count(tt.s, tt.sep); got != tt.want {
+				t.Errorf("count() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+`
+
+const addTestMain = `package main
+
+import "example.com/addtest/lib"
+
+func main() { lib.Shout("hi") }
+`
+
+// TestGoDryrunAddTest is the single table-driven test for all add test scenarios.
+func TestGoDryrunAddTest(t *testing.T) {
+	dir := chSetup(t, "addtest", map[string]string{"main.go": addTestMain})
+	libDir := filepath.Join(dir, "lib")
+	if err := os.Mkdir(libDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeChFile(t, filepath.Join(libDir, "shout.go"), addTestShout)
+	writeChFile(t, filepath.Join(libDir, "count.go"), addTestCount)
+	writeChFile(t, filepath.Join(libDir, "count_test.go"), addTestCountTest)
+	shout := map[string]any{"symbol_name": "Shout", "context_file": filepath.Join(libDir, "shout.go")}
+	count := map[string]any{"symbol_name": "count", "context_file": filepath.Join(libDir, "count.go")}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"NewTestInNewFile": {
+			args: map[string]any{"locator": shout},
+			tool: "go_dryrun_add_test",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Add TestShout Preview ==="),
+				assertContains("+++ " + filepath.ToSlash(filepath.Join(libDir, "shout_test.go"))),
+				assertContains("+package lib_test"),
+				assertContains("\"example.com/addtest/lib\""),
+				assertContains("+func TestShout(t *testing.T) {"),
+				assertContains("got, gotErr := lib.Shout(tt.s)"),
+				assertContains("NO FILES HAVE BEEN MODIFIED"),
+			},
+		},
+		"SkeletonCase": {
+			args: map[string]any{"locator": count},
+			tool: "go_dryrun_add_test",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Add Case to Test_count Preview ==="),
+				assertContains("Test_count already exists in " + filepath.Join(libDir, "count_test.go")),
+				assertContains(" \t\t{name: \"empty\", s: \"\", sep: ',', want: 0},\n+\t\t{name: \"TODO\", s: \"\", sep: 0, want: 0},\n \t}\n"),
+				assertNotContains("+func Test_count"),
+			},
+		},
+		"GivenCase": {
+			args: map[string]any{"locator": count, "test_case": `{name: "two", s: "a,b", sep: ',', want: 2},`},
+			tool: "go_dryrun_add_test",
+			assertions: []assertion{
+				assertContains("+\t\t{name: \"two\", s: \"a,b\", sep: ',', want: 2},\n"),
+			},
+		},
+		"InvalidCase": {
+			args:       map[string]any{"locator": count, "test_case": `{name: "two",`},
+			tool:       "go_dryrun_add_test",
+			assertions: []assertion{assertContains("is not a valid table element")},
+		},
+		"CaseWithoutTest": {
+			args:       map[string]any{"locator": shout, "test_case": `{name: "x"}`},
+			tool:       "go_dryrun_add_test",
+			assertions: []assertion{assertContains("there is no TestShout to add the test case to")},
+		},
+	})
+}
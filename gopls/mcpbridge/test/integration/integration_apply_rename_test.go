@@ -0,0 +1,182 @@
+package integration
+
+// End-to-end tests for go_apply_rename functionality.
+// Verifies that renames are written to disk, that the session sees them
+// immediately, and that stale files block the write.
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/mcpbridge/test/testutil"
+)
+
+const applyRenameSource = `package main
+
+import "fmt"
+
+func OldName() string {
+	return "hello"
+}
+
+func main() {
+	fmt.Println(OldName())
+}
+`
+
+// callTool invokes the named tool and returns its text content.
+func callTool(t *testing.T, name string, args map[string]any) string {
+	t.Helper()
+	res, err := globalSession.CallTool(globalCtx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("Failed to call tool %s: %v", name, err)
+	}
+	return testutil.ResultText(t, res, "")
+}
+
+// previewHashes runs go_dryrun_rename_symbol and extracts the file hashes it reports.
+func previewHashes(t *testing.T, args map[string]any) map[string]any {
+	t.Helper()
+	content := callTool(t, "go_dryrun_rename_symbol", args)
+	hashes := map[string]any{}
+	for _, m := range regexp.MustCompile(`(?m)^  (\S+\.go): ([0-9a-f]{64})$`).FindAllStringSubmatch(content, -1) {
+		hashes[m[1]] = m[2]
+	}
+	if len(hashes) == 0 {
+		t.Fatalf("no file hashes in preview:\n%s", content)
+	}
+	return hashes
+}
+
+func TestGoApplyRename(t *testing.T) {
+	t.Run("WritesFilesAndUpdatesSession", func(t *testing.T) {
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource})
+		mainFile := filepath.Join(dir, "main.go")
+
+		content := callTool(t, "go_apply_rename", renameArgs("OldName", mainFile, 5, "NewName"))
+		if !strings.Contains(content, `Renamed "OldName" to "NewName" in 1 file(s)`) {
+			t.Fatalf("unexpected apply result:\n%s", content)
+		}
+
+		data, err := os.ReadFile(mainFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "OldName") || strings.Count(string(data), "NewName") != 2 {
+			t.Errorf("expected both occurrences renamed on disk, got:\n%s", data)
+		}
+
+		// The session must see the new name right away, without waiting
+		// for the file watcher.
+		def := callTool(t, "go_definition", map[string]any{
+			"locator": map[string]any{
+				"symbol_name":  "NewName",
+				"context_file": mainFile,
+				"kind":         "function",
+				"line_hint":    5,
+			},
+		})
+		if !strings.Contains(def, "NewName") || !strings.Contains(def, "main.go") {
+			t.Errorf("expected go_definition to resolve NewName after apply, got:\n%s", def)
+		}
+	})
+
+	t.Run("AppliesPreviewWithExpectedHashes", func(t *testing.T) {
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource})
+		mainFile := filepath.Join(dir, "main.go")
+
+		args := renameArgs("OldName", mainFile, 5, "NewName")
+		args["expected_hashes"] = previewHashes(t, args)
+
+		content := callTool(t, "go_apply_rename", args)
+		if !strings.Contains(content, "Renamed") {
+			t.Fatalf("expected rename to be applied, got:\n%s", content)
+		}
+	})
+
+	t.Run("FileChangedSincePreview", func(t *testing.T) {
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource})
+		mainFile := filepath.Join(dir, "main.go")
+
+		args := renameArgs("OldName", mainFile, 5, "NewName")
+		args["expected_hashes"] = previewHashes(t, args)
+
+		// Someone edits the file after the preview.
+		edited := strings.Replace(applyRenameSource, `"hello"`, `"hello, world"`, 1)
+		writeChFile(t, mainFile, edited)
+
+		content := callTool(t, "go_apply_rename", args)
+		if !strings.Contains(content, "NOT applied") || !strings.Contains(content, mainFile) {
+			t.Fatalf("expected a conflict report for %s, got:\n%s", mainFile, content)
+		}
+		assertFileUnchanged(t, mainFile, edited)
+	})
+
+	t.Run("MismatchedExpectedHash", func(t *testing.T) {
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource})
+		mainFile := filepath.Join(dir, "main.go")
+
+		args := renameArgs("OldName", mainFile, 5, "NewName")
+		args["expected_hashes"] = map[string]any{mainFile: strings.Repeat("0", 64)}
+
+		content := callTool(t, "go_apply_rename", args)
+		if !strings.Contains(content, "file changed since the preview") {
+			t.Fatalf("expected a stale-preview conflict, got:\n%s", content)
+		}
+		assertFileUnchanged(t, mainFile, applyRenameSource)
+	})
+
+	t.Run("ExpectedHashWithUncleanPath", func(t *testing.T) {
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource})
+		mainFile := filepath.Join(dir, "main.go")
+
+		args := renameArgs("OldName", mainFile, 5, "NewName")
+		args["expected_hashes"] = map[string]any{dir + "/./main.go": strings.Repeat("0", 64)}
+
+		content := callTool(t, "go_apply_rename", args)
+		if !strings.Contains(content, "file changed since the preview") {
+			t.Fatalf("expected a stale-preview conflict, got:\n%s", content)
+		}
+		assertFileUnchanged(t, mainFile, applyRenameSource)
+	})
+
+	t.Run("EditedFileMissingFromExpectedHashes", func(t *testing.T) {
+		const otherSource = "package main\n\nvar greeting = OldName()\n"
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource, "other.go": otherSource})
+		mainFile := filepath.Join(dir, "main.go")
+		otherFile := filepath.Join(dir, "other.go")
+
+		args := renameArgs("OldName", mainFile, 5, "NewName")
+		hashes := previewHashes(t, args)
+		delete(hashes, otherFile)
+		args["expected_hashes"] = hashes
+
+		content := callTool(t, "go_apply_rename", args)
+		if !strings.Contains(content, "not in the preview") || !strings.Contains(content, otherFile) {
+			t.Fatalf("expected a conflict for %s, got:\n%s", otherFile, content)
+		}
+		assertFileUnchanged(t, mainFile, applyRenameSource)
+		assertFileUnchanged(t, otherFile, otherSource)
+	})
+
+	t.Run("ExpectedFileNoLongerEdited", func(t *testing.T) {
+		dir := chSetup(t, "applyrename", map[string]string{"main.go": applyRenameSource})
+		mainFile := filepath.Join(dir, "main.go")
+		goneFile := filepath.Join(dir, "gone.go")
+
+		args := renameArgs("OldName", mainFile, 5, "NewName")
+		hashes := previewHashes(t, args)
+		hashes[goneFile] = strings.Repeat("0", 64)
+		args["expected_hashes"] = hashes
+
+		content := callTool(t, "go_apply_rename", args)
+		if !strings.Contains(content, "no longer edited") || !strings.Contains(content, goneFile) {
+			t.Fatalf("expected a conflict for %s, got:\n%s", goneFile, content)
+		}
+		assertFileUnchanged(t, mainFile, applyRenameSource)
+	})
+}
@@ -187,6 +187,72 @@ func TestGoCallHierarchy(t *testing.T) {
 		})
 	})
 
+	t.Run("Recursive", func(t *testing.T) {
+		// deepArgs builds args for a recursive expansion of symbol in recursiveSource.
+		deepArgs := func(t *testing.T, symbol string, lineHint int, direction string, maxDepth, maxNodes int) map[string]any {
+			dir := chSetup(t, "deepcalls", map[string]string{"main.go": recursiveSource})
+			args := chArgs(dir, symbol, lineHint, direction)
+			args["max_depth"] = maxDepth
+			if maxNodes > 0 {
+				args["max_nodes"] = maxNodes
+			}
+			return args
+		}
+		runTableDrivenTests(t, map[string]testCase{
+			"IncomingTree": {
+				setup: func(t *testing.T) map[string]any { return deepArgs(t, "db", 25, "incoming", 5, 0) },
+				tool:  "go_get_call_hierarchy",
+				assertions: []assertion{
+					assertContains("Incoming Calls (7 nodes):"),
+					assertContains("  - #1 repo in example.com/deepcalls"),
+					assertContains("  - #2 db "),
+					assertContains("(recursion into the root)"),
+					assertContains("    - #3 service "),
+					assertContains("    - #4 service2 "),
+					assertContains("      - #5 handler "),
+					assertContains("      - #6 service "),
+					assertContains("(already shown as #3)"),
+					assertContains("        - #7 main "),
+				},
+			},
+			"OutgoingMutualRecursion": {
+				setup: func(t *testing.T) map[string]any { return deepArgs(t, "even", 31, "outgoing", 3, 0) },
+				tool:  "go_get_call_hierarchy",
+				assertions: []assertion{
+					assertContains("  - #1 odd "),
+					assertContains("    - #2 even "),
+					assertContains("(recursion into the root)"),
+				},
+			},
+			"MaxDepthLimitsLevels": {
+				setup: func(t *testing.T) map[string]any { return deepArgs(t, "db", 25, "incoming", 2, 0) },
+				tool:  "go_get_call_hierarchy",
+				assertions: []assertion{
+					assertContains("Incoming Calls (4 nodes):"),
+					assertNotContains("handler"),
+				},
+			},
+			"MaxNodesTruncates": {
+				setup: func(t *testing.T) map[string]any { return deepArgs(t, "db", 25, "incoming", 5, 3) },
+				tool:  "go_get_call_hierarchy",
+				assertions: []assertion{
+					assertContains("Incoming Calls (3 nodes):"),
+					assertContains("Stopped at max_nodes=3"),
+				},
+			},
+			"DefaultDepthListsDirectCalls": {
+				setup: func(t *testing.T) map[string]any {
+					return chArgs(chSetup(t, "deepcalls", map[string]string{"main.go": recursiveSource}), "db", 25, "incoming")
+				},
+				tool: "go_get_call_hierarchy",
+				assertions: []assertion{
+					assertContains("Incoming Calls (2):"),
+					assertNotContains("#1"),
+				},
+			},
+		})
+	})
+
 	t.Run("ErrorHandling", func(t *testing.T) {
 		runTableDrivenTests(t, map[string]testCase{
 			"InvalidPosition": {
@@ -218,6 +284,53 @@ func TestGoCallHierarchy(t *testing.T) {
 	})
 }
 
+// recursiveSource has a call chain into db, which recurses into itself, a
+// function (service) reached along two paths, and mutual recursion.
+const recursiveSource = `package main
+
+func main() {
+	handler()
+	even(4)
+}
+
+func handler() {
+	service()
+}
+
+func service() {
+	repo()
+	service2()
+}
+
+func service2() {
+	repo()
+}
+
+func repo() {
+	db(3)
+}
+
+func db(n int) {
+	if n > 0 {
+		db(n - 1)
+	}
+}
+
+func even(n int) bool {
+	if n == 0 {
+		return true
+	}
+	return odd(n - 1)
+}
+
+func odd(n int) bool {
+	if n == 0 {
+		return false
+	}
+	return even(n - 1)
+}
+`
+
 // ===== Shared helpers =====
 
 // chSetup creates a temp Go project with the given module name and source files.
@@ -0,0 +1,122 @@
+package integration
+
+// End-to-end tests for go_call_path.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const callPathSource = `package main
+
+import "fmt"
+
+type Store interface {
+	Save(key string) error
+}
+
+type diskStore struct{}
+
+func (diskStore) Save(key string) error { return write(key) }
+
+type memStore struct{}
+
+func (memStore) Save(key string) error { return nil }
+
+func write(key string) error {
+	fmt.Println(key)
+	return nil
+}
+
+func handler(s Store) error {
+	return service(s)
+}
+
+func service(s Store) error {
+	return s.Save("k")
+}
+
+func direct() error {
+	return write("x")
+}
+
+func viaFuncValue() error {
+	return apply(write)
+}
+
+func apply(f func(string) error) error {
+	return f("y")
+}
+
+func unrelated() {}
+
+func main() {
+	handler(diskStore{})
+	_ = memStore{}
+	direct()
+	viaFuncValue()
+	unrelated()
+}
+`
+
+func TestGoCallPath(t *testing.T) {
+	// args builds go_call_path arguments for two functions of callPathSource.
+	args := func(t *testing.T, from, to string, extra map[string]any) map[string]any {
+		file := filepath.Join(chSetup(t, "callpath", map[string]string{"main.go": callPathSource}), "main.go")
+		args := map[string]any{
+			"from": map[string]any{"symbol_name": from, "context_file": file},
+			"to":   map[string]any{"symbol_name": to, "context_file": file},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"ThroughInterfaceDispatch": {
+			setup: func(t *testing.T) map[string]any { return args(t, "handler", "write", nil) },
+			tool:  "go_call_path",
+			assertions: []assertion{
+				assertContains("Found 1 call path(s) from handler to write (algorithm: vta)"),
+				assertContains("Path 1 (3 calls)"),
+				assertContains("-> service at "),
+				assertContains("-> Save at "),
+				assertContains("interface call"),
+				assertContains("-> write at "),
+				assertContains("main.go:27"),
+			},
+		},
+		"StaticAlgorithmStopsAtInterface": {
+			setup: func(t *testing.T) map[string]any {
+				return args(t, "handler", "write", map[string]any{"algorithm": "static"})
+			},
+			tool:       "go_call_path",
+			assertions: []assertion{assertContains("No call path from handler to write (algorithm: static)")},
+		},
+		"KShortestPaths": {
+			setup: func(t *testing.T) map[string]any { return args(t, "main", "write", map[string]any{"max_paths": 3}) },
+			tool:  "go_call_path",
+			assertions: []assertion{
+				assertContains("Found 3 call path(s) from main to write"),
+				assertContains("Path 1 (2 calls)"),
+				assertContains("Path 2 (3 calls)"),
+				assertContains("Path 3 (4 calls)"),
+				assertContains("-> direct at "),
+				assertContains("-> viaFuncValue at "),
+				assertContains("-> apply at "),
+				assertContains("dynamic call"),
+			},
+		},
+		"Unreachable": {
+			setup:      func(t *testing.T) map[string]any { return args(t, "unrelated", "write", nil) },
+			tool:       "go_call_path",
+			assertions: []assertion{assertContains("No call path from unrelated to write")},
+		},
+		"NotAFunction": {
+			setup:      func(t *testing.T) map[string]any { return args(t, "handler", "Store", nil) },
+			tool:       "go_call_path",
+			assertions: []assertion{assertContains("'Store' is not a function or method")},
+		},
+	})
+}
@@ -0,0 +1,171 @@
+package integration
+
+// End-to-end tests for go_dryrun_change_signature functionality.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const changeSignatureLib = `package lib
+
+import (
+	"context"
+	"strings"
+)
+
+var DefaultTimes = 2
+
+func Greet(name string, times int, loud bool) string {
+	return strings.Repeat("hello "+name+" ", times)
+}
+
+func Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+`
+
+const changeSignatureMain = `package main
+
+import (
+	"fmt"
+
+	"example.com/changesig/lib"
+)
+
+func name() string { return "gopher" }
+
+func main() {
+	fmt.Println(lib.Greet("a", 1, false))
+	fmt.Println(lib.Greet(name(), 3, true))
+	greet := lib.Greet
+	fmt.Println(greet("b", 1, false))
+}
+`
+
+// changeSignatureShout is a file of lib that imports nothing.
+const changeSignatureShout = `package lib
+
+func Shout(s string) string { return s + "!" }
+`
+
+const changeSignatureShoutCall = `package main
+
+import "example.com/changesig/lib"
+
+var shouted = lib.Shout("hey")
+`
+
+const changeSignatureBroken = `package main
+
+import "example.com/changesig/lib"
+
+func broken() string {
+	return lib.Greet("c", "two", false)
+}
+`
+
+// TestGoDryrunChangeSignature is the single table-driven test for all change signature scenarios.
+func TestGoDryrunChangeSignature(t *testing.T) {
+	dir := chSetup(t, "changesig", map[string]string{"main.go": changeSignatureMain, "broken.go": changeSignatureBroken, "shout.go": changeSignatureShoutCall})
+	if err := os.Mkdir(filepath.Join(dir, "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeChFile(t, filepath.Join(dir, "lib", "lib.go"), changeSignatureLib)
+	writeChFile(t, filepath.Join(dir, "lib", "shout.go"), changeSignatureShout)
+	libFile := filepath.Join(dir, "lib", "lib.go")
+	mainFile := filepath.Join(dir, "main.go")
+	greet := map[string]any{"symbol_name": "Greet", "context_file": libFile}
+	skipped := []assertion{
+		assertContains("Calls not rewritten (2), fix them by hand:"),
+		assertContains(filepath.Join(dir, "broken.go") + ":6: the call has type errors"),
+		assertContains(mainFile + ":14: not a call: the function is used as a value"),
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"AddReorderRemove": {
+			args: map[string]any{"locator": greet, "params": []any{
+				map[string]any{"field": "ctx context.Context", "default": "context.TODO()"},
+				map[string]any{"old_index": 1},
+				map[string]any{"old_index": 0},
+			}},
+			tool: "go_dryrun_change_signature",
+			assertions: append([]assertion{
+				assertContains("=== DRY RUN: Change Signature of Greet Preview ==="),
+				assertContains("+func Greet(ctx context.Context, times int, name string) string {"),
+				assertContains("+	fmt.Println(lib.Greet(context.TODO(), 1, \"a\"))"),
+				// name() is evaluated before 3 in the original call.
+				assertContains("+		var name string = name()\n+		return lib.Greet(context.TODO(), 3, name)"),
+				assertContains("+	\"context\""),
+				assertContains("NO FILES HAVE BEEN MODIFIED"),
+			}, skipped...),
+		},
+		"AddFromCallSite": {
+			args: map[string]any{
+				"locator": map[string]any{"symbol_name": "Greet", "context_file": mainFile, "line_hint": 12},
+				"params": []any{
+					map[string]any{"old_index": 0},
+					map[string]any{"old_index": 1},
+					map[string]any{"old_index": 2},
+					map[string]any{"field": "count int", "default": "DefaultTimes"},
+				},
+			},
+			tool: "go_dryrun_change_signature",
+			assertions: []assertion{
+				assertContains("+func Greet(name string, times int, loud bool, count int) string {"),
+				assertContains("+	fmt.Println(lib.Greet(name(), 3, true, lib.DefaultTimes))"),
+			},
+		},
+		"AddImportToDeclaringFile": {
+			args: map[string]any{
+				"locator": map[string]any{"symbol_name": "Shout", "context_file": filepath.Join(dir, "lib", "shout.go")},
+				"params": []any{
+					map[string]any{"field": "ctx context.Context", "default": "context.Background()"},
+					map[string]any{"old_index": 0},
+				},
+			},
+			tool: "go_dryrun_change_signature",
+			assertions: []assertion{
+				assertContains("+import \"context\""),
+				assertContains("+func Shout(ctx context.Context, s string) string { return s + \"!\" }"),
+				assertContains("+var shouted = lib.Shout(context.Background(), \"hey\")"),
+			},
+		},
+		"UnknownPackage": {
+			args:       map[string]any{"locator": greet, "params": []any{map[string]any{"field": "k nosuchpkg.T", "default": "nil"}}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains("new parameter k: undefined: nosuchpkg")},
+		},
+		"NoDefault": {
+			args:       map[string]any{"locator": greet, "params": []any{map[string]any{"field": "k int"}}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains("new parameter k needs a default argument for existing calls")},
+		},
+		"DefaultOfWrongType": {
+			args:       map[string]any{"locator": greet, "params": []any{map[string]any{"field": "k int", "default": `"x"`}}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains(`default "\"x\"" of new parameter k is not a value of type int`)},
+		},
+		"UnknownType": {
+			args:       map[string]any{"locator": greet, "params": []any{map[string]any{"field": "k Foo", "default": "nil"}}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains("invalid type of new parameter k: undefined: Foo")},
+		},
+		"NameInUse": {
+			args:       map[string]any{"locator": greet, "params": []any{map[string]any{"field": "name string", "default": `""`}}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains("new parameter name name is already used in Greet")},
+		},
+		"OldIndexOutOfRange": {
+			args:       map[string]any{"locator": greet, "params": []any{map[string]any{"old_index": 5}}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains("no parameter #5: Greet has 3 parameters")},
+		},
+		"NotAFunction": {
+			args:       map[string]any{"locator": map[string]any{"symbol_name": "DefaultTimes", "context_file": libFile}, "params": []any{}},
+			tool:       "go_dryrun_change_signature",
+			assertions: []assertion{assertContains("DefaultTimes is not a function or method")},
+		},
+	})
+}
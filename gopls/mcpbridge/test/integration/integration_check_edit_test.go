@@ -0,0 +1,217 @@
+package integration
+
+// End-to-end tests for go_check_edit functionality.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const checkEditSource = `package main
+
+import "fmt"
+
+func Greet(name string) string {
+	return "hello " + name
+}
+
+func main() {
+	fmt.Println(Greet("world"))
+}
+`
+
+// TestGoCheckEdit is the single table-driven test for all edit check scenarios.
+func TestGoCheckEdit(t *testing.T) {
+	t.Run("FullContent", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"ValidEdit": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path":   filepath.Join(dir, "main.go"),
+						"new_content": checkEditSource + "\nfunc Farewell(name string) string { return Greet(name) + \"!\" }\n",
+					}
+				},
+				tool: "go_check_edit",
+				assertions: []assertion{
+					assertContains("type-checks cleanly"),
+					assertContains("No changes were written to disk"),
+				},
+			},
+			"TypeError": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path": filepath.Join(dir, "main.go"),
+						"new_content": `package main
+
+func Greet(name string) string {
+	return 42
+}
+
+func main() {}
+`,
+					}
+				},
+				tool: "go_check_edit",
+				assertions: []assertion{
+					assertContains("1 error(s)"),
+					assertContains("main.go:4:9: [error]"),
+				},
+			},
+			"NewFileInPackage": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path":   filepath.Join(dir, "helper.go"),
+						"new_content": "package main\n\nfunc helper() string { return Greet(undefinedThing) }\n",
+					}
+				},
+				tool:       "go_check_edit",
+				assertions: []assertion{assertContains("undefined: undefinedThing")},
+			},
+		})
+	})
+
+	t.Run("LineEdits", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"ReplaceIntroducesError": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path": filepath.Join(dir, "main.go"),
+						"edits": []map[string]any{
+							{"start_line": 6, "end_line": 6, "new_text": "\treturn len(name)"},
+						},
+					}
+				},
+				tool: "go_check_edit",
+				assertions: []assertion{
+					assertContains("error(s)"),
+					assertContains("main.go:6:"),
+				},
+			},
+			"InsertBeforeLine": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path": filepath.Join(dir, "main.go"),
+						"edits": []map[string]any{
+							{"start_line": 9, "end_line": 8, "new_text": "func shout(s string) string { return s + \"!\" }\n"},
+						},
+					}
+				},
+				tool:       "go_check_edit",
+				assertions: []assertion{assertContains("type-checks cleanly")},
+			},
+			"OverlappingEditsRejected": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path": filepath.Join(dir, "main.go"),
+						"edits": []map[string]any{
+							{"start_line": 5, "end_line": 7, "new_text": ""},
+							{"start_line": 6, "end_line": 6, "new_text": "\treturn name"},
+						},
+					}
+				},
+				tool:       "go_check_edit",
+				assertions: []assertion{assertContains("overlaps a previous edit")},
+			},
+		})
+	})
+
+	t.Run("AffectedPackages", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"ImporterIsChecked": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{
+						"main.go": `package main
+
+import "example.com/checkedit/lib"
+
+func main() {
+	var n int = lib.Value()
+	_ = n
+}
+`,
+					})
+					if err := os.Mkdir(filepath.Join(dir, "lib"), 0755); err != nil {
+						t.Fatal(err)
+					}
+					writeChFile(t, filepath.Join(dir, "lib", "lib.go"), "package lib\n\nfunc Value() int { return 1 }\n")
+					return map[string]any{
+						"file_path":   filepath.Join(dir, "lib", "lib.go"),
+						"new_content": "package lib\n\nfunc Value() string { return \"1\" }\n",
+					}
+				},
+				tool: "go_check_edit",
+				assertions: []assertion{
+					assertContains("example.com/checkedit/lib"),
+					assertContains("main.go:6:"),
+				},
+			},
+		})
+	})
+
+	t.Run("OverlayIsReverted", func(t *testing.T) {
+		dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+		mainFile := filepath.Join(dir, "main.go")
+
+		// Propose a broken edit first; it must not leak into later queries.
+		if _, err := globalSession.CallTool(globalCtx, &mcp.CallToolParams{
+			Name: "go_check_edit",
+			Arguments: map[string]any{
+				"file_path":   mainFile,
+				"new_content": "package main\n\nfunc main() { undefinedCall() }\n",
+			},
+		}); err != nil {
+			t.Fatalf("Failed to call tool go_check_edit: %v", err)
+		}
+
+		runTableDrivenTests(t, map[string]testCase{
+			"LineEditsSeeDiskContent": {
+				args: map[string]any{
+					"file_path": mainFile,
+					"edits": []map[string]any{
+						{"start_line": 11, "end_line": 11, "new_text": "}"},
+					},
+				},
+				tool: "go_check_edit",
+				assertions: []assertion{
+					assertContains("type-checks cleanly"),
+					assertCustom(
+						"file on disk is unchanged",
+						func(string) bool {
+							data, err := os.ReadFile(mainFile)
+							return err == nil && string(data) == checkEditSource
+						},
+						"expected main.go on disk to keep its original content",
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"BothContentAndEdits": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "checkedit", map[string]string{"main.go": checkEditSource})
+					return map[string]any{
+						"file_path":   filepath.Join(dir, "main.go"),
+						"new_content": checkEditSource,
+						"edits": []map[string]any{
+							{"start_line": 1, "end_line": 1, "new_text": "package main"},
+						},
+					}
+				},
+				tool:       "go_check_edit",
+				assertions: []assertion{assertContains("mutually exclusive")},
+			},
+		})
+	})
+}
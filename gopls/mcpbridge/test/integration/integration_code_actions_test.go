@@ -0,0 +1,129 @@
+package integration
+
+// End-to-end tests for go_code_actions and go_dryrun_code_action.
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const codeActionsSource = `package main
+
+import "fmt"
+
+func double(n int) int {
+	return n * 2
+}
+
+func main() {
+	x := 1
+	y := x + 2
+	fmt.Println(double(y))
+	fmt.Println(strings.ToUpper("hi"))
+	fmt.Println(missing(x))
+}
+`
+
+// codeActionID returns the ID of the action titled title in the output of
+// go_code_actions.
+func codeActionID(t *testing.T, output, title string) string {
+	t.Helper()
+	m := regexp.MustCompile(regexp.QuoteMeta(title) + ` \([^)]*\)\n  id: (\S+)`).FindStringSubmatch(output)
+	if m == nil {
+		t.Fatalf("no action %q in:\n%s", title, output)
+	}
+	return m[1]
+}
+
+// TestGoCodeActions is the single table-driven test for all code action scenarios.
+func TestGoCodeActions(t *testing.T) {
+	dir := chSetup(t, "codeactions", map[string]string{"main.go": codeActionsSource})
+	mainFile := filepath.Join(dir, "main.go")
+
+	t.Run("List", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"MissingImport": {
+				args: map[string]any{"file_path": mainFile, "start_line": 13, "kinds": []string{"quickfix"}},
+				tool: "go_code_actions",
+				assertions: []assertion{
+					assertContains(`Add import:  "strings"`),
+					assertContains("fixes: undefined: strings"),
+					assertContains("id: quickfix@" + mainFile + ":13:1-13:36#"),
+				},
+			},
+			"UndeclaredName": {
+				args:       map[string]any{"file_path": mainFile, "start_line": 14, "kinds": []string{"quickfix"}},
+				tool:       "go_code_actions",
+				assertions: []assertion{assertContains("Create function missing")},
+			},
+			"ExtractLines": {
+				args: map[string]any{"file_path": mainFile, "start_line": 10, "end_line": 11, "kinds": []string{"refactor.extract"}},
+				tool: "go_code_actions",
+				assertions: []assertion{
+					assertContains("Extract function (refactor.extract.function)"),
+					assertNotContains("quickfix"),
+				},
+			},
+			"Locator": {
+				args: map[string]any{
+					"locator": map[string]any{"symbol_name": "double", "context_file": mainFile, "line_hint": 12},
+					"kinds":   []string{"refactor.inline"},
+				},
+				tool:       "go_code_actions",
+				assertions: []assertion{assertContains("Inline call to double (refactor.inline.call)")},
+			},
+			"NeitherLocatorNorFile": {
+				args:       map[string]any{},
+				tool:       "go_code_actions",
+				assertions: []assertion{assertContains("either locator, or file_path and start_line, is required")},
+			},
+		})
+	})
+
+	t.Run("Dryrun", func(t *testing.T) {
+		list := func(args map[string]any) string {
+			res, err := globalSession.CallTool(globalCtx, &mcp.CallToolParams{Name: "go_code_actions", Arguments: args})
+			if err != nil {
+				t.Fatalf("Failed to call tool go_code_actions: %v", err)
+			}
+			return res.Content[0].(*mcp.TextContent).Text
+		}
+		importID := codeActionID(t, list(map[string]any{"file_path": mainFile, "start_line": 13}), `Add import:  "strings"`)
+		extractID := codeActionID(t, list(map[string]any{"file_path": mainFile, "start_line": 10, "end_line": 11}), "Extract function")
+
+		runTableDrivenTests(t, map[string]testCase{
+			"EditAction": {
+				args: map[string]any{"id": importID},
+				tool: "go_dryrun_code_action",
+				assertions: []assertion{
+					assertContains("=== DRY RUN: Code Action Preview ==="),
+					assertContains(`+import (`),
+					assertContains(`+	"strings"`),
+					assertContains("NO FILES HAVE BEEN MODIFIED"),
+				},
+			},
+			"CommandAction": {
+				args: map[string]any{"id": extractID},
+				tool: "go_dryrun_code_action",
+				assertions: []assertion{
+					assertContains(`"Extract function" (refactor.extract.function)`),
+					assertContains("+	x, y := newFunction()"),
+					assertContains("+func newFunction() (int, int) {"),
+				},
+			},
+			"StaleID": {
+				args:       map[string]any{"id": "quickfix@" + mainFile + ":13:1-13:36#00000000"},
+				tool:       "go_dryrun_code_action",
+				assertions: []assertion{assertContains("is no longer available")},
+			},
+			"InvalidID": {
+				args:       map[string]any{"id": "not-an-id"},
+				tool:       "go_dryrun_code_action",
+				assertions: []assertion{assertContains(`invalid code action ID "not-an-id"`)},
+			},
+		})
+	})
+}
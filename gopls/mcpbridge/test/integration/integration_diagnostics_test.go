@@ -0,0 +1,128 @@
+package integration
+
+// End-to-end tests for go_diagnostics functionality.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const diagnosticsSource = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Printf("%d\n", "not a number")
+	n := 65
+	fmt.Println(string(n))
+}
+`
+
+const diagnosticsBrokenSource = `package broken
+
+func Value() int {
+	return "one"
+}
+`
+
+// diagnosticsSetup creates a module with analyzer findings in its main
+// package and a type error in the broken package.
+func diagnosticsSetup(t *testing.T) string {
+	dir := chSetup(t, "diagnostics", map[string]string{"main.go": diagnosticsSource})
+	if err := os.Mkdir(filepath.Join(dir, "broken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeChFile(t, filepath.Join(dir, "broken", "broken.go"), diagnosticsBrokenSource)
+	return dir
+}
+
+// TestGoDiagnostics is the single table-driven test for all diagnostics scenarios.
+func TestGoDiagnostics(t *testing.T) {
+	runTableDrivenTests(t, map[string]testCase{
+		"Workspace": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t)}
+			},
+			tool: "go_diagnostics",
+			assertions: []assertion{
+				assertContains("(1 error(s), 2 warning(s)) in 2 package(s) checked"),
+				assertContains("example.com/diagnostics (2):"),
+				assertContains("example.com/diagnostics/broken (1):"),
+				assertContains("broken.go:4:9: [error]"),
+				assertContains("main.go:6:14: [warning] fmt.Printf format %d has arg \"not a number\" of wrong type string (printf)"),
+				assertContains("(stringintconv)"),
+				assertContains("No fixes were applied."),
+			},
+		},
+		"SuggestedFixDiff": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"file_path": filepath.Join(diagnosticsSetup(t), "main.go")}
+			},
+			tool: "go_diagnostics",
+			assertions: []assertion{
+				assertContains("Suggested fix: Convert a single rune to a string"),
+				assertContains("-\tfmt.Println(string(n))"),
+				assertContains("+\tfmt.Println(string(rune(n)))"),
+				assertNotContains("broken.go"),
+			},
+		},
+		"AnalyzerFilter": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t), "analyzers": []string{"printf"}}
+			},
+			tool: "go_diagnostics",
+			assertions: []assertion{
+				assertContains("with analyzers printf"),
+				assertContains("(printf)"),
+				assertContains("broken.go:4:9: [error]"),
+				assertNotContains("stringintconv"),
+			},
+		},
+		"TypeErrorsOnly": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t), "type_errors_only": true}
+			},
+			tool: "go_diagnostics",
+			assertions: []assertion{
+				assertContains("Found 1 diagnostic(s) (1 error(s), 0 warning(s))"),
+				assertNotContains("(printf)"),
+			},
+		},
+		"MinSeverityError": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t), "min_severity": "error"}
+			},
+			tool: "go_diagnostics",
+			assertions: []assertion{
+				assertContains("Found 1 diagnostic(s)"),
+				assertNotContains("[warning]"),
+			},
+		},
+		"PackageScope": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t), "package_path": "example.com/diagnostics/broken"}
+			},
+			tool: "go_diagnostics",
+			assertions: []assertion{
+				assertContains("in 1 package(s) checked"),
+				assertContains("broken.go:4:9: [error]"),
+				assertNotContains("main.go"),
+			},
+		},
+		"UnknownAnalyzer": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t), "analyzers": []string{"nosuchanalyzer"}}
+			},
+			tool:       "go_diagnostics",
+			assertions: []assertion{assertContains(`unknown analyzer "nosuchanalyzer"`)},
+		},
+		"UnknownPackage": {
+			setup: func(t *testing.T) map[string]any {
+				return map[string]any{"Cwd": diagnosticsSetup(t), "package_path": "example.com/diagnostics/nosuch"}
+			},
+			tool:       "go_diagnostics",
+			assertions: []assertion{assertContains("package not found: example.com/diagnostics/nosuch")},
+		},
+	})
+}
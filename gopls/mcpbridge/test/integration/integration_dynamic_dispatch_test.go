@@ -0,0 +1,137 @@
+package integration
+
+// End-to-end tests for include_dynamic on go_symbol_references and
+// go_get_call_hierarchy: calls of a concrete method through an interface
+// declared in another package are reported either way, and tagged with it.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const dispatchStore = `package store
+
+type Store interface {
+	Save(key string) error
+}
+`
+
+const dispatchFile = `package file
+
+type FileStore struct{}
+
+func (*FileStore) Save(key string) error { return nil }
+`
+
+const dispatchMain = `package main
+
+import (
+	"example.com/dispatch/file"
+	"example.com/dispatch/store"
+)
+
+func persist(s store.Store) error {
+	return s.Save("a")
+}
+
+func main() {
+	fs := &file.FileStore{}
+	fs.Save("b")
+	persist(fs)
+}
+`
+
+// dispatchSetup writes the module and returns the file declaring FileStore
+// and the main file.
+func dispatchSetup(t *testing.T) (fileGo, mainGo string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, sub := range []string{"store", "file"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeChFile(t, filepath.Join(dir, "go.mod"), "module example.com/dispatch\n\ngo 1.21\n")
+	writeChFile(t, filepath.Join(dir, "store", "store.go"), dispatchStore)
+	writeChFile(t, filepath.Join(dir, "file", "file.go"), dispatchFile)
+	mainGo = filepath.Join(dir, "main.go")
+	writeChFile(t, mainGo, dispatchMain)
+	return filepath.Join(dir, "file", "file.go"), mainGo
+}
+
+func TestIncludeDynamic(t *testing.T) {
+	// refArgs and callArgs build the arguments for queries about (*FileStore).Save.
+	refArgs := func(t *testing.T, includeDynamic bool) map[string]any {
+		fileGo, _ := dispatchSetup(t)
+		return map[string]any{
+			"locator": map[string]any{
+				"symbol_name":  "Save",
+				"context_file": fileGo,
+				"parent_scope": "FileStore",
+			},
+			"include_dynamic": includeDynamic,
+		}
+	}
+	callArgs := func(t *testing.T, includeDynamic bool) map[string]any {
+		args := refArgs(t, includeDynamic)
+		args["direction"] = "incoming"
+		return args
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"ReferencesUntagged": {
+			setup: func(t *testing.T) map[string]any { return refArgs(t, false) },
+			tool:  "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 2 reference(s) to \"Save\":"),
+				assertContains("main.go:9:11 [call]"),
+				assertContains("main.go:14:5 [call]"),
+				assertNotContains("static"),
+			},
+		},
+		"ReferencesWithDynamic": {
+			setup: func(t *testing.T) map[string]any { return refArgs(t, true) },
+			tool:  "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 2 reference(s) to \"Save\" (1 static, 1 via interface)"),
+				assertContains("main.go:9:11 [call, via store.Store]"),
+				assertContains("main.go:14:5 [call, static]"),
+			},
+		},
+		"IncomingCallsUntagged": {
+			setup: func(t *testing.T) map[string]any { return callArgs(t, false) },
+			tool:  "go_get_call_hierarchy",
+			assertions: []assertion{
+				assertContains("Incoming Calls (2):"),
+				assertContains("persist at "),
+				assertContains("main at "),
+				assertNotContains("[static]"),
+				assertNotContains("[via "),
+			},
+		},
+		"IncomingCallsWithDynamic": {
+			setup: func(t *testing.T) map[string]any { return callArgs(t, true) },
+			tool:  "go_get_call_hierarchy",
+			assertions: []assertion{
+				assertContains("Incoming Calls (2):"),
+				assertContains("persist at "),
+				assertContains("main.go:8 [via store.Store]"),
+				assertContains("main.go:12 [static]"),
+			},
+		},
+		"IncomingTreeWithDynamic": {
+			setup: func(t *testing.T) map[string]any {
+				a := callArgs(t, true)
+				a["max_depth"] = 2
+				return a
+			},
+			tool: "go_get_call_hierarchy",
+			assertions: []assertion{
+				assertContains("- #1 persist "),
+				assertContains("[via store.Store]"),
+				assertContains("    - #3 main "),
+			},
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package integration
+
+// End-to-end tests for go_dryrun_extract functionality.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const extractSource = `package main
+
+import "fmt"
+
+type Counter struct {
+	n int
+}
+
+func (c *Counter) Add(step int) {
+	total := c.n + step
+	c.n = total
+}
+
+type Point struct{ X, Y int }
+
+func (p Point) String() string {
+	return fmt.Sprintf("(%d, %d)", p.X, p.Y)
+}
+
+func main() {
+	base := 10
+	scale := 3
+	sum := base * scale
+	fmt.Println(sum + 1)
+	fmt.Println(60 * 60)
+}
+`
+
+// TestGoDryrunExtract is the single table-driven test for all extract scenarios.
+func TestGoDryrunExtract(t *testing.T) {
+	dir := chSetup(t, "extract", map[string]string{"main.go": extractSource})
+	mainFile := filepath.Join(dir, "main.go")
+
+	runTableDrivenTests(t, map[string]testCase{
+		"Function": {
+			args: map[string]any{"file_path": mainFile, "kind": "function", "start_line": 23, "end_line": 24},
+			tool: "go_dryrun_extract",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Extract function Preview ==="),
+				assertContains("Free variables passed as parameters:\n  base int\n  scale int\n"),
+				assertContains("+	newFunction(base, scale)"),
+				assertContains("+func newFunction(base int, scale int) {"),
+				assertContains("NO FILES HAVE BEEN MODIFIED"),
+			},
+		},
+		"MethodExcludesReceiver": {
+			args: map[string]any{"file_path": mainFile, "kind": "method", "start_line": 10, "end_line": 11},
+			tool: "go_dryrun_extract",
+			assertions: []assertion{
+				assertContains("  step int\n"),
+				assertNotContains("  c *Counter\n"),
+				assertContains("+func (c *Counter) newMethod(step int) {"),
+			},
+		},
+		"Variable": {
+			args: map[string]any{"file_path": mainFile, "kind": "variable", "snippet": "sum + 1"},
+			tool: "go_dryrun_extract",
+			assertions: []assertion{
+				assertContains("+	newVar := sum + 1"),
+				assertContains("+	fmt.Println(newVar)"),
+				assertNotContains("Free variables"),
+			},
+		},
+		"Constant": {
+			args: map[string]any{"file_path": mainFile, "kind": "constant", "snippet": "60 * 60"},
+			tool: "go_dryrun_extract",
+			assertions: []assertion{
+				assertContains("+	const newConst = 60 * 60"),
+			},
+		},
+		"ToNewFile": {
+			args: map[string]any{"file_path": mainFile, "kind": "to-new-file", "start_line": 14, "end_line": 18},
+			tool: "go_dryrun_extract",
+			assertions: []assertion{
+				assertContains("+++ " + filepath.ToSlash(filepath.Join(dir, "point.go"))),
+				assertContains("+func (p Point) String() string {"),
+				assertContains("+import (\n+\t\"fmt\"\n+)"),
+				assertContains("-type Point struct{ X, Y int }"),
+			},
+		},
+		"NotAConstant": {
+			args:       map[string]any{"file_path": mainFile, "kind": "constant", "snippet": "sum + 1"},
+			tool:       "go_dryrun_extract",
+			assertions: []assertion{assertContains("the expression is not constant")},
+		},
+		"MethodOutsideMethod": {
+			args:       map[string]any{"file_path": mainFile, "kind": "method", "start_line": 23, "end_line": 24},
+			tool:       "go_dryrun_extract",
+			assertions: []assertion{assertContains("the selection is not in a method")},
+		},
+		"AmbiguousSnippet": {
+			args:       map[string]any{"file_path": mainFile, "kind": "variable", "snippet": "fmt.Println"},
+			tool:       "go_dryrun_extract",
+			assertions: []assertion{assertContains("snippet occurs 2 times")},
+		},
+		"UnknownKind": {
+			args:       map[string]any{"file_path": mainFile, "kind": "interface", "start_line": 23},
+			tool:       "go_dryrun_extract",
+			assertions: []assertion{assertContains(`unknown extraction kind "interface"`)},
+		},
+	})
+}
@@ -0,0 +1,118 @@
+package integration
+
+// End-to-end tests for go_dryrun_implement_interface functionality.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const implementInterfaceStore = `package store
+
+import "context"
+
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Put(key string, vals ...[]byte)
+	Len() int
+}
+
+type counter interface {
+	Len() int
+}
+`
+
+const implementInterfaceMain = `package main
+
+import "example.com/implement/store"
+
+type memStore struct{}
+
+func (m *memStore) Len() int { return 0 }
+
+var _ store.Store = (*memStore)(nil)
+
+func main() {}
+`
+
+// TestGoDryrunImplementInterface is the single table-driven test for all implement interface scenarios.
+func TestGoDryrunImplementInterface(t *testing.T) {
+	dir := chSetup(t, "implement", map[string]string{"main.go": implementInterfaceMain})
+	if err := os.Mkdir(filepath.Join(dir, "store"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeChFile(t, filepath.Join(dir, "store", "store.go"), implementInterfaceStore)
+	mainFile := filepath.Join(dir, "main.go")
+	iface := map[string]any{"symbol_name": "Store", "context_file": filepath.Join(dir, "store", "store.go")}
+	memStore := map[string]any{"symbol_name": "memStore", "context_file": mainFile}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"Stubs": {
+			args: map[string]any{"interface": iface, "concrete": memStore},
+			tool: "go_dryrun_implement_interface",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Implement store.Store Preview ==="),
+				assertContains("Methods added to memStore: Get, Put\n"),
+				assertContains("+func (m *memStore) Get(ctx context.Context, key string) (string, error) {"),
+				assertContains("+func (m *memStore) Put(key string, vals ...[]byte) {"),
+				assertContains("+\t\"context\"\n"),
+				assertNotContains("Len() int {\n+"),
+				assertContains("NO FILES HAVE BEEN MODIFIED"),
+			},
+		},
+		"FakeInNewFile": {
+			args: map[string]any{"interface": iface, "fake_file": filepath.Join(dir, "fake_store.go")},
+			tool: "go_dryrun_implement_interface",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Fake store.Store Preview ==="),
+				assertContains("Fake: FakeStore, recording calls of Get, Len, Put\n"),
+				assertContains("+++ " + filepath.ToSlash(filepath.Join(dir, "fake_store.go"))),
+				assertContains("+package main\n"),
+				assertContains("+\t\"example.com/implement/store\"\n"),
+				assertContains("+\t\"sync\"\n"),
+				assertContains("+\tGetFunc  func(ctx context.Context, key string) (string, error)\n"),
+				assertContains("+\tGetCalls []FakeStoreGetCall\n"),
+				assertContains("+var _ store.Store = (*FakeStore)(nil)\n"),
+				assertContains("+func (f *FakeStore) Put(key string, vals ...[]byte) {"),
+				assertContains("+\tf.PutCalls = append(f.PutCalls, FakeStorePutCall{Key: key, Vals: vals})\n"),
+				assertContains("+\t\tfn(key, vals...)\n"),
+				assertContains("+\t\treturn fn(ctx, key)\n+\t}\n+\treturn \"\", nil\n"),
+			},
+		},
+		"FakeInExistingFile": {
+			args: map[string]any{"interface": iface, "fake_file": mainFile, "fake_name": "spyStore"},
+			tool: "go_dryrun_implement_interface",
+			assertions: []assertion{
+				assertContains("Fake: spyStore"),
+				assertContains(" \"example.com/implement/store\"\n"),
+				assertContains("+type spyStore struct {"),
+				assertContains("+\tLenCalls []spyStoreLenCall\n"),
+				assertContains("+\treturn 0\n"),
+			},
+		},
+		"FakeNameTaken": {
+			args:       map[string]any{"interface": iface, "fake_file": mainFile, "fake_name": "memStore"},
+			tool:       "go_dryrun_implement_interface",
+			assertions: []assertion{assertContains("memStore is already declared in package main")},
+		},
+		"FakeOfUnexportedInterface": {
+			args: map[string]any{
+				"interface": map[string]any{"symbol_name": "counter", "context_file": filepath.Join(dir, "store", "store.go")},
+				"fake_file": mainFile,
+			},
+			tool:       "go_dryrun_implement_interface",
+			assertions: []assertion{assertContains("cannot fake store.counter outside package store: the interface is unexported")},
+		},
+		"NotAnInterface": {
+			args:       map[string]any{"interface": memStore, "fake_file": mainFile},
+			tool:       "go_dryrun_implement_interface",
+			assertions: []assertion{assertContains("memStore is not an interface type")},
+		},
+		"NeitherMode": {
+			args:       map[string]any{"interface": iface},
+			tool:       "go_dryrun_implement_interface",
+			assertions: []assertion{assertContains("either concrete")},
+		},
+	})
+}
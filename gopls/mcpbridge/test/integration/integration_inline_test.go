@@ -0,0 +1,105 @@
+package integration
+
+// End-to-end tests for go_dryrun_inline functionality.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const inlineSource = `package main
+
+import "fmt"
+
+func sub(a, b int) int {
+	return b - a
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func next() int {
+	fmt.Println("next")
+	return 1
+}
+
+func twice(s string) string {
+	return s + s
+}
+
+func main() {
+	fmt.Println(sub(next(), next()))
+	fmt.Println(abs(-3))
+	fmt.Println(twice("a"))
+	fmt.Println(twice("b"))
+	fmt.Println(sub(1, 2))
+}
+`
+
+const inlineOtherSource = `package main
+
+func shout() string {
+	return twice("!")
+}
+`
+
+// TestGoDryrunInline is the single table-driven test for all inline scenarios.
+func TestGoDryrunInline(t *testing.T) {
+	dir := chSetup(t, "inline", map[string]string{"main.go": inlineSource, "other.go": inlineOtherSource})
+	mainFile := filepath.Join(dir, "main.go")
+
+	runTableDrivenTests(t, map[string]testCase{
+		"CallKeepsEvaluationOrder": {
+			args: map[string]any{"locator": map[string]any{"symbol_name": "sub", "context_file": mainFile, "line_hint": 26}},
+			tool: "go_dryrun_inline",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Inline Call to sub Preview ==="),
+				assertContains("arguments bound to variables (var params = args) to keep their evaluation order"),
+				assertContains("+	var a int = next()\n+	return next() - a\n"),
+				assertContains("NO FILES HAVE BEEN MODIFIED"),
+			},
+		},
+		"CallReducedToExpression": {
+			args: map[string]any{"locator": map[string]any{"symbol_name": "sub", "context_file": mainFile, "line_hint": 30}},
+			tool: "go_dryrun_inline",
+			assertions: []assertion{
+				assertContains("+	fmt.Println(2 - 1)"),
+				assertNotContains("Caveats:"),
+			},
+		},
+		"CallLiteralized": {
+			args: map[string]any{"locator": map[string]any{"symbol_name": "abs", "context_file": mainFile, "line_hint": 27}},
+			tool: "go_dryrun_inline",
+			assertions: []assertion{
+				assertContains("call replaced by a call of a function literal"),
+				assertContains("+	fmt.Println(func() int {"),
+			},
+		},
+		"AllCalls": {
+			args: map[string]any{"locator": map[string]any{"symbol_name": "twice", "context_file": mainFile, "line_hint": 21}},
+			tool: "go_dryrun_inline",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Inline All Calls to twice Preview ==="),
+				assertContains("Files: 2 (the declaration of twice is kept)"),
+				assertContains(`kept param "s": argument is not duplicable`),
+				assertContains(`+		var s string = "b"`),
+				assertContains("+++ " + filepath.ToSlash(filepath.Join(dir, "other.go"))),
+				assertContains(`-	return twice("!")`),
+			},
+		},
+		"AllCallsByQualifiedName": {
+			args:       map[string]any{"locator": map[string]any{"qualified_name": "example.com/inline.twice", "context_file": mainFile}},
+			tool:       "go_dryrun_inline",
+			assertions: []assertion{assertContains("=== DRY RUN: Inline All Calls to twice Preview ===")},
+		},
+		"NotACall": {
+			args:       map[string]any{"locator": map[string]any{"symbol_name": "x", "context_file": mainFile, "line_hint": 10}},
+			tool:       "go_dryrun_inline",
+			assertions: []assertion{assertContains("cannot inline: no enclosing call")},
+		},
+	})
+}
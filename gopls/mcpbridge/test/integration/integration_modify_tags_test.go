@@ -0,0 +1,76 @@
+package integration
+
+// End-to-end tests for go_dryrun_modify_tags functionality.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const modifyTagsSource = "package main\n\n" +
+	"type User struct {\n" +
+	"\tUserID    int    `json:\"user_id\"`\n" +
+	"\tFirstName string `json:\"first_name,omitempty\"`\n" +
+	"\tEmail     string\n" +
+	"\tpassword  string\n" +
+	"}\n\n" +
+	"type ID int\n\n" +
+	"func main() {}\n"
+
+// TestGoDryrunModifyTags is the single table-driven test for all modify tags scenarios.
+func TestGoDryrunModifyTags(t *testing.T) {
+	dir := chSetup(t, "modifytags", map[string]string{"main.go": modifyTagsSource})
+	user := map[string]any{"symbol_name": "User", "context_file": filepath.Join(dir, "main.go")}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"AddSnakeCase": {
+			args: map[string]any{"locator": user, "add": []any{"db"}, "skip_unexported": true},
+			tool: "go_dryrun_modify_tags",
+			assertions: []assertion{
+				assertContains("=== DRY RUN: Modify Tags of User Preview ==="),
+				assertContains("Fields: UserID; FirstName; Email\n"),
+				assertContains("+\tUserID    int    `json:\"user_id\" db:\"user_id\"`"),
+				assertContains("+\tEmail     string `db:\"email\"`"),
+				assertNotContains("+\tpassword"),
+				assertContains("NO FILES HAVE BEEN MODIFIED"),
+			},
+		},
+		"CamelCaseOverwriteSubset": {
+			args: map[string]any{"locator": user, "fields": []any{"UserID"}, "add": []any{"json"}, "transform": "camelCase", "overwrite": true},
+			tool: "go_dryrun_modify_tags",
+			assertions: []assertion{
+				assertContains("Fields: UserID\n"),
+				assertContains("+\tUserID    int    `json:\"userID\"`"),
+				assertNotContains("+\tFirstName"),
+			},
+		},
+		"NoChange": {
+			args:       map[string]any{"locator": user, "add_options": []any{"json=omitempty"}, "remove_options": []any{"json=omitempty"}, "fields": []any{"FirstName"}},
+			tool:       "go_dryrun_modify_tags",
+			assertions: []assertion{assertContains("no tags of User change")},
+		},
+		"RemoveOmitempty": {
+			args: map[string]any{"locator": user, "remove_options": []any{"json=omitempty"}},
+			tool: "go_dryrun_modify_tags",
+			assertions: []assertion{
+				assertContains("Fields: FirstName\n"),
+				assertContains("+\tFirstName string `json:\"first_name\"`"),
+			},
+		},
+		"UnknownField": {
+			args:       map[string]any{"locator": user, "fields": []any{"Age"}, "add": []any{"json"}},
+			tool:       "go_dryrun_modify_tags",
+			assertions: []assertion{assertContains("User has no field Age")},
+		},
+		"NotAStruct": {
+			args:       map[string]any{"locator": map[string]any{"symbol_name": "ID", "context_file": filepath.Join(dir, "main.go")}, "add": []any{"json"}},
+			tool:       "go_dryrun_modify_tags",
+			assertions: []assertion{assertContains("ID is not a struct type")},
+		},
+		"NothingToDo": {
+			args:       map[string]any{"locator": user},
+			tool:       "go_dryrun_modify_tags",
+			assertions: []assertion{assertContains("nothing to do")},
+		},
+	})
+}
@@ -0,0 +1,108 @@
+package integration
+
+// End-to-end tests for workspaces with one view per module.
+// A library module and an app module that depends on it through a replace
+// directive each get their own view; queries about the library must also
+// report what only the app's view can see.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const multiViewLib = `package liba
+
+// Shape is implemented in the app module.
+type Shape interface {
+	Area() float64
+}
+
+func Sum(a, b int) int {
+	return a + b
+}
+`
+
+const multiViewApp = `package main
+
+import "example.com/liba"
+
+type Square struct{ side float64 }
+
+func (s Square) Area() float64 { return s.side * s.side }
+
+var _ liba.Shape = Square{}
+
+func main() {
+	println(liba.Sum(1, 2))
+}
+`
+
+// multiViewSetup writes the two modules and makes sure the app has a view of
+// its own, as it would when opened as a separate workspace folder.
+func multiViewSetup(t *testing.T) (libFile, appFile string) {
+	t.Helper()
+	root := t.TempDir()
+	for _, dir := range []string{"liba", "app"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	libFile = filepath.Join(root, "liba", "lib.go")
+	appFile = filepath.Join(root, "app", "main.go")
+	writeChFile(t, filepath.Join(root, "liba", "go.mod"), "module example.com/liba\n\ngo 1.21\n")
+	writeChFile(t, libFile, multiViewLib)
+	writeChFile(t, filepath.Join(root, "app", "go.mod"), `module example.com/multiapp
+
+go 1.21
+
+require example.com/liba v0.0.0
+
+replace example.com/liba => ../liba
+`)
+	writeChFile(t, appFile, multiViewApp)
+
+	callTool(t, "go_definition", map[string]any{
+		"locator": map[string]any{"symbol_name": "main", "context_file": appFile},
+	})
+	return libFile, appFile
+}
+
+func TestMultiViewQueries(t *testing.T) {
+	libFile, appFile := multiViewSetup(t)
+	locator := func(name, file string) map[string]any {
+		return map[string]any{"symbol_name": name, "context_file": file}
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"ReferencesFromDependentModule": {
+			args: map[string]any{"locator": locator("Sum", libFile)},
+			tool: "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 1 reference(s)"),
+				assertContains(appFile + ":12:"),
+			},
+		},
+		"ReferencesFromAppFile": {
+			args:       map[string]any{"locator": locator("Sum", appFile)},
+			tool:       "go_symbol_references",
+			assertions: []assertion{assertContains(appFile + ":12:")},
+		},
+		"ImplementationsInDependentModule": {
+			args: map[string]any{"locator": locator("Shape", libFile)},
+			tool: "go_implementation",
+			assertions: []assertion{
+				assertContains("Square"),
+				assertContains(appFile),
+			},
+		},
+		"RenameAcrossModules": {
+			args: map[string]any{"locator": locator("Sum", libFile), "new_name": "Total"},
+			tool: "go_dryrun_rename_symbol",
+			assertions: []assertion{
+				assertContains("+func Total(a, b int) int {"),
+				assertContains("liba.Total(1, 2)"),
+			},
+		},
+	})
+}
@@ -0,0 +1,114 @@
+package integration
+
+// End-to-end test for the notifications sent after on-disk changes:
+// resources/updated for subscribed resources, and type error summaries.
+// It starts a server of its own, since only -workdir and the client's
+// roots are watched.
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/mcpbridge/test/testutil"
+)
+
+func TestChangeNotifications(t *testing.T) {
+	projectDir := testutil.CopyProjectTo(t, "simple")
+	mainGo := filepath.Join(projectDir, "main.go")
+	outlineURI := "gopls://file/" + strings.TrimPrefix(filepath.ToSlash(mainGo), "/") + "/outline"
+	packageURI := "gopls://package/example.com/simple"
+
+	updates := make(chan string, 100)
+	logs := make(chan string, 100)
+	client := mcp.NewClient(&mcp.Implementation{Name: "notifications-client", Version: "v0.0.1"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updates <- req.Params.URI
+		},
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			if req.Params.Logger == "gopls-mcp/diagnostics" {
+				text, _ := req.Params.Data.(string)
+				logs <- string(req.Params.Level) + ": " + text
+			}
+		},
+	})
+	cmd := exec.Command(globalGoplsMcpPath, "-workdir", projectDir)
+	session, err := client.Connect(context.Background(), &mcp.CommandTransport{Command: cmd}, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to gopls-mcp: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	ctx := context.Background()
+
+	if err := session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatalf("SetLoggingLevel failed: %v", err)
+	}
+	// Subscribing starts the session, and with it the file watcher.
+	for _, uri := range []string{outlineURI, packageURI} {
+		if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			t.Fatalf("Subscribe(%s) failed: %v", uri, err)
+		}
+	}
+	if err := session.Subscribe(ctx, &mcp.SubscribeParams{URI: "gopls://symbol/example.com/simple/Hello"}); err == nil {
+		t.Errorf("Subscribe to a symbol resource succeeded, want an error")
+	}
+	// Load the package, so that the change can be attributed to it.
+	if _, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: packageURI}); err != nil {
+		t.Fatalf("ReadResource(%s) failed: %v", packageURI, err)
+	}
+	time.Sleep(500 * time.Millisecond) // let fsnotify settle
+
+	original, err := os.ReadFile(mainGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	broken := string(original) + "\nfunc Broken() int { return undefinedName }\n"
+	if err := os.WriteFile(mainGo, []byte(broken), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// waitFor returns the first message of ch that contains want.
+	waitFor := func(ch <-chan string, what, want string) string {
+		t.Helper()
+		timeout := time.After(30 * time.Second)
+		for {
+			select {
+			case msg := <-ch:
+				if strings.Contains(msg, want) {
+					return msg
+				}
+			case <-timeout:
+				t.Fatalf("no %s containing %q", what, want)
+				return ""
+			}
+		}
+	}
+
+	got := map[string]bool{}
+	for len(got) < 2 {
+		got[waitFor(updates, "resource update", "gopls://")] = true
+	}
+	if !got[outlineURI] || !got[packageURI] {
+		t.Errorf("updated resources %v, want %s and %s", got, outlineURI, packageURI)
+	}
+	msg := waitFor(logs, "type error summary", "undefinedName")
+	for _, want := range []string{"error: Type errors after file changes: 1 new, 0 cleared", "main.go:33:28: undefined: undefinedName"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("summary does not contain %q:\n%s", want, msg)
+		}
+	}
+
+	// Fixing the file clears the error.
+	if err := os.WriteFile(mainGo, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+	msg = waitFor(logs, "type error summary", "cleared")
+	if !strings.Contains(msg, "info: Type errors after file changes: 0 new, 1 cleared") {
+		t.Errorf("unexpected summary after the fix:\n%s", msg)
+	}
+}
@@ -0,0 +1,158 @@
+package integration
+
+// End-to-end tests for the cursor and page_size parameters of the tools
+// with paged results.
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const pagingSource = `package main
+
+func ping() {}
+
+func a() { ping() }
+func b() { ping() }
+func c() { ping() }
+func d() { ping() }
+func e() { ping() }
+
+func main() {
+	a()
+	b()
+	c()
+	d()
+	e()
+}
+`
+
+// cursorRE extracts the next cursor from a summary.
+var cursorRE = regexp.MustCompile(`Pass cursor "([^"]+)" for the next page`)
+
+// nextCursor returns the cursor of the next page in summary, or "".
+func nextCursor(summary string) string {
+	if m := cursorRE.FindStringSubmatch(summary); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func TestPagination(t *testing.T) {
+	file := filepath.Join(chSetup(t, "paging", map[string]string{"main.go": pagingSource}), "main.go")
+	locator := map[string]any{"symbol_name": "ping", "context_file": file}
+
+	t.Run("References", func(t *testing.T) {
+		var pages []string
+		args := map[string]any{"locator": locator, "page_size": 2}
+		for {
+			page := callTool(t, "go_symbol_references", args)
+			pages = append(pages, page)
+			cursor := nextCursor(page)
+			if cursor == "" {
+				break
+			}
+			args = map[string]any{"locator": locator, "cursor": cursor}
+		}
+		if len(pages) != 3 {
+			t.Fatalf("got %d pages, want 3:\n%s", len(pages), strings.Join(pages, "\n---\n"))
+		}
+		for i, want := range []string{"(Showing 1-2 of 5.", "(Showing 3-4 of 5.", "(Showing 5-5 of 5.)"} {
+			if !strings.Contains(pages[i], want) {
+				t.Errorf("page %d does not contain %q:\n%s", i+1, want, pages[i])
+			}
+			if !strings.Contains(pages[i], "Found 5 reference(s)") {
+				t.Errorf("page %d lacks the header:\n%s", i+1, pages[i])
+			}
+		}
+		// The references come in order, each exactly once.
+		all := strings.Join(pages, "\n")
+		last := -1
+		for i, line := range []string{"main.go:5:12", "main.go:6:12", "main.go:7:12", "main.go:8:12", "main.go:9:12"} {
+			if n := strings.Count(all, line); n != 1 {
+				t.Errorf("reference %s appears %d times, want 1", line, n)
+			}
+			at := strings.Index(all, fmt.Sprintf("%d. %s%s", i+1, filepath.Dir(file), "/"+line))
+			if at < 0 || at < last {
+				t.Errorf("reference #%d (%s) is missing or out of order", i+1, line)
+			}
+			last = at
+		}
+	})
+
+	t.Run("CallHierarchy", func(t *testing.T) {
+		page := callTool(t, "go_get_call_hierarchy", map[string]any{"locator": locator, "direction": "incoming", "page_size": 3})
+		for _, want := range []string{"Incoming Calls (5):", "1. a at ", "3. c at ", "(Showing 1-3 of 5."} {
+			if !strings.Contains(page, want) {
+				t.Errorf("first page does not contain %q:\n%s", want, page)
+			}
+		}
+		page = callTool(t, "go_get_call_hierarchy", map[string]any{"locator": locator, "cursor": nextCursor(page)})
+		for _, want := range []string{"Incoming Calls (5):", "4. d at ", "5. e at ", "(Showing 4-5 of 5.)"} {
+			if !strings.Contains(page, want) {
+				t.Errorf("second page does not contain %q:\n%s", want, page)
+			}
+		}
+	})
+
+	t.Run("BadCursor", func(t *testing.T) {
+		res, err := globalSession.CallTool(globalCtx, &mcp.CallToolParams{
+			Name:      "go_symbol_references",
+			Arguments: map[string]any{"locator": locator, "cursor": "bogus"},
+		})
+		if err == nil && !res.IsError {
+			t.Fatalf("expected an error for a bogus cursor")
+		}
+	})
+}
+
+// TestPaginationOversizedPage verifies that a page whose items would not
+// fit in one response is cut short at an item boundary, with a cursor
+// that leads to the rest, instead of being truncated by the response
+// limiter.
+func TestPaginationOversizedPage(t *testing.T) {
+	const n = 50 // a default page of implementations
+	var src strings.Builder
+	src.WriteString("package main\n\ntype Shape interface{ Area() float64 }\n\nfunc main() {}\n")
+	var fields strings.Builder
+	for i := range 30 {
+		fmt.Fprintf(&fields, "\tMeasurementWithALongName%02d float64\n", i)
+	}
+	for i := range n {
+		fmt.Fprintf(&src, "\ntype Shape%02d struct {\n%s}\n\nfunc (Shape%02d) Area() float64 { return 0 }\n", i, fields.String(), i)
+	}
+	file := filepath.Join(chSetup(t, "bigpage", map[string]string{"main.go": src.String()}), "main.go")
+	locator := map[string]any{"symbol_name": "Shape", "context_file": file, "kind": "interface"}
+
+	var all []string
+	args := map[string]any{"locator": locator}
+	for pages := 1; ; pages++ {
+		page := callTool(t, "go_implementation", args)
+		if strings.Contains(page, "truncated") {
+			t.Errorf("page %d was truncated:\n%s", pages, page)
+		}
+		all = append(all, page)
+		cursor := nextCursor(page)
+		if cursor == "" {
+			if pages == 1 {
+				t.Fatalf("expected %d large implementations to take more than one page", n)
+			}
+			break
+		}
+		if !strings.HasPrefix(page, "(Showing ") {
+			t.Errorf("page %d does not start with its page note:\n%.200s", pages, page)
+		}
+		args = map[string]any{"locator": locator, "cursor": cursor}
+	}
+	joined := strings.Join(all, "\n")
+	for i := range n {
+		if got := strings.Count(joined, fmt.Sprintf(". Shape%02d at ", i)); got != 1 {
+			t.Errorf("Shape%02d appears %d times in the pages, want 1", i, got)
+		}
+	}
+}
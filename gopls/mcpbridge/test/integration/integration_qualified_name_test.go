@@ -0,0 +1,100 @@
+package integration
+
+// End-to-end tests for locators given by qualified_name, without a context_file.
+// They resolve against the shared "simple" project (module example.com/simple).
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func qualifiedArgs(name string) map[string]any {
+	return map[string]any{"locator": map[string]any{"qualified_name": name}}
+}
+
+func TestQualifiedNameLocator(t *testing.T) {
+	renameArgs := qualifiedArgs("example.com/simple.Add")
+	renameArgs["new_name"] = "Sum"
+
+	runTableDrivenTests(t, map[string]testCase{
+		"DefinitionOfMethod": {
+			args: qualifiedArgs("example.com/simple.(*Person).Greeting"),
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("Greeting"),
+				assertContains("main.go:22"),
+			},
+		},
+		"DefinitionFromStackTraceFrame": {
+			args:       qualifiedArgs("example.com/simple.(*Person).Greeting(0xc000010000)"),
+			tool:       "go_definition",
+			assertions: []assertion{assertContains("main.go:22")},
+		},
+		"DefinitionInStandardLibrary": {
+			args: qualifiedArgs("fmt.Println"),
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("Println"),
+				assertContains("print.go"),
+			},
+		},
+		"References": {
+			args:       qualifiedArgs("example.com/simple.Hello"),
+			tool:       "go_symbol_references",
+			assertions: []assertion{assertContains("main.go:27:14")},
+		},
+		"CallHierarchy": {
+			args: qualifiedArgs("example.com/simple.Hello"),
+			tool: "go_get_call_hierarchy",
+			assertions: []assertion{
+				assertContains("Incoming Calls (1)"),
+				assertContains("main at "),
+			},
+		},
+		"RenamePreview": {
+			args: renameArgs,
+			tool: "go_dryrun_rename_symbol",
+			assertions: []assertion{
+				assertContains("-func Add(a, b int) int {"),
+				assertContains("+func Sum(a, b int) int {"),
+			},
+		},
+		"MisspelledName": {
+			args: qualifiedArgs("example.com/simple.Helo"),
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("NOT_FOUND"),
+				assertContains("function Hello"),
+			},
+		},
+		"UnknownPackage": {
+			args:       qualifiedArgs("example.com/nosuch/pkg.Func"),
+			tool:       "go_definition",
+			assertions: []assertion{assertContains("PACKAGE_NOT_LOADED")},
+		},
+		"ImplementationWithContextFile": {
+			setup: func(t *testing.T) map[string]any {
+				dir := chSetup(t, "qualimpl", map[string]string{"main.go": `package main
+
+type Shape interface {
+	Area() float64
+}
+
+type Square struct{ side float64 }
+
+func (s Square) Area() float64 { return s.side * s.side }
+
+func main() {}
+`})
+				return map[string]any{
+					"locator": map[string]any{
+						"qualified_name": "example.com/qualimpl.Shape",
+						"context_file":   filepath.Join(dir, "main.go"),
+					},
+				}
+			},
+			tool:       "go_implementation",
+			assertions: []assertion{assertContains("Square")},
+		},
+	})
+}
@@ -0,0 +1,143 @@
+package integration
+
+// End-to-end tests for the reference kinds and filters of
+// go_symbol_references.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const refKindsMain = `package main
+
+type Base struct{}
+
+type Counter struct {
+	Base
+	n int
+}
+
+func (c *Counter) Inc() { c.n++ }
+
+func (c *Counter) Get() int { return c.n }
+
+func reset(c *Counter) {
+	c.n = 0
+	p := &c.n
+	_ = p
+}
+
+func main() {
+	c := Counter{n: 1}
+	c.Inc()
+	var x any = Counter{}
+	_ = x
+	reset(&c)
+	println(c.Get())
+}
+`
+
+const refKindsGenerated = `// Code generated by hand. DO NOT EDIT.
+
+package main
+
+func generated(c *Counter) int { return c.n }
+`
+
+const refKindsTest = `package main
+
+import "testing"
+
+func TestCounter(t *testing.T) {
+	c := &Counter{}
+	c.n = 5
+	c.Inc()
+}
+`
+
+func TestReferenceKinds(t *testing.T) {
+	// args builds go_symbol_references arguments for a symbol declared in
+	// refKindsMain.
+	args := func(t *testing.T, name, scope string, extra map[string]any) map[string]any {
+		dir := chSetup(t, "refkinds", map[string]string{
+			"main.go":      refKindsMain,
+			"gen.go":       refKindsGenerated,
+			"main_test.go": refKindsTest,
+		})
+		locator := map[string]any{"symbol_name": name, "context_file": filepath.Join(dir, "main.go")}
+		if scope != "" {
+			locator["parent_scope"] = scope
+		}
+		args := map[string]any{"locator": locator}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"FieldReadsAndWrites": {
+			setup: func(t *testing.T) map[string]any { return args(t, "n", "Counter", nil) },
+			tool:  "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 7 reference(s) to \"n\":"),
+				assertContains("main.go:10:29 [write]"), // c.n++
+				assertContains("main.go:12:40 [read]"),
+				assertContains("main.go:15:4 [write]"),  // c.n = 0
+				assertContains("main.go:16:10 [write]"), // &c.n
+				assertContains("main.go:21:15 [write]"), // Counter{n: 1}
+				assertContains("gen.go:5:43 [read]"),
+				assertContains("main_test.go:7:4 [write]"),
+			},
+		},
+		"OnlyWrites": {
+			setup: func(t *testing.T) map[string]any {
+				return args(t, "n", "Counter", map[string]any{"only_writes": true})
+			},
+			tool: "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 5 reference(s) to \"n\" (2 excluded by filters):"),
+				assertNotContains("[read]"),
+			},
+		},
+		"ExcludeTestsAndGenerated": {
+			setup: func(t *testing.T) map[string]any {
+				return args(t, "n", "Counter", map[string]any{"exclude_tests": true, "exclude_generated": true})
+			},
+			tool: "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 5 reference(s) to \"n\" (2 excluded by filters):"),
+				assertNotContains("gen.go"),
+				assertNotContains("main_test.go"),
+			},
+		},
+		"OnlyCalls": {
+			setup: func(t *testing.T) map[string]any {
+				return args(t, "Inc", "Counter", map[string]any{"only_calls": true})
+			},
+			tool: "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 2 reference(s) to \"Inc\":"),
+				assertContains("main.go:22:4 [call]"),
+				assertContains("main_test.go:8:4 [call]"),
+			},
+		},
+		"TypeUses": {
+			setup: func(t *testing.T) map[string]any { return args(t, "Counter", "", nil) },
+			tool:  "go_symbol_references",
+			assertions: []assertion{
+				assertContains("main.go:14:15 [type]"),
+				assertContains("main.go:21:7 [type]"),
+				assertNotContains("[read]"),
+			},
+		},
+		"Embedding": {
+			setup: func(t *testing.T) map[string]any { return args(t, "Base", "", nil) },
+			tool:  "go_symbol_references",
+			assertions: []assertion{
+				assertContains("Found 1 reference(s) to \"Base\":"),
+				assertContains("main.go:6:2 [embed]"),
+			},
+		},
+	})
+}
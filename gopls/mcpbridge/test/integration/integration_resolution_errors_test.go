@@ -0,0 +1,70 @@
+package integration
+
+// End-to-end tests for structured symbol resolution errors.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const resolutionErrorsSource = `package main
+
+type Server struct{}
+
+func (s *Server) Start() {}
+
+type Client struct{}
+
+func (c *Client) Start() {}
+
+func main() {}
+`
+
+func TestResolutionErrors(t *testing.T) {
+	locatorArgs := func(t *testing.T, locator map[string]any) map[string]any {
+		dir := chSetup(t, "resolveerr", map[string]string{
+			"main.go": resolutionErrorsSource,
+			"util.go": "package main\n\nfunc Helper() {}\n",
+		})
+		locator["context_file"] = filepath.Join(dir, "main.go")
+		return map[string]any{"locator": locator}
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"Ambiguous": {
+			setup: func(t *testing.T) map[string]any {
+				return locatorArgs(t, map[string]any{"symbol_name": "Start"})
+			},
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("AMBIGUOUS"),
+				assertContains("Candidates:"),
+				assertContains("method Start (parent_scope: *Server) at "),
+				assertContains("method Start (parent_scope: *Client) at "),
+				assertContains(`"code":"AMBIGUOUS"`),
+			},
+		},
+		"NotFoundSuggestsSpelling": {
+			setup: func(t *testing.T) map[string]any {
+				return locatorArgs(t, map[string]any{"symbol_name": "Strat"})
+			},
+			tool: "go_symbol_references",
+			assertions: []assertion{
+				assertContains("NOT_FOUND"),
+				assertContains("Did you mean:"),
+				assertContains("method Start (parent_scope: Server)"),
+			},
+		},
+		"NotFoundSuggestsOtherFile": {
+			setup: func(t *testing.T) map[string]any {
+				return locatorArgs(t, map[string]any{"symbol_name": "Helper"})
+			},
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("NOT_FOUND"),
+				assertContains("util.go:3"),
+				assertContains(`"suggestions":[`),
+			},
+		},
+	})
+}
@@ -0,0 +1,160 @@
+package integration
+
+// End-to-end tests for the gopls:// resources.
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const resourcesSource = `package shapes
+
+// Shape is a closed figure.
+type Shape interface {
+	Area() float64
+}
+
+// Square is a square with sides of length Side.
+type Square struct {
+	Side float64
+}
+
+// Area returns the area of the square.
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+// scale is unexported.
+func scale(s Square, f float64) Square { return Square{s.Side * f} }
+`
+
+// readResource reads the resource at uri and returns its text.
+func readResource(t *testing.T, uri string) string {
+	t.Helper()
+	res, err := globalSession.ReadResource(globalCtx, &mcp.ReadResourceParams{URI: uri})
+	if err != nil {
+		t.Fatalf("ReadResource(%s) failed: %v", uri, err)
+	}
+	if len(res.Contents) != 1 {
+		t.Fatalf("ReadResource(%s) returned %d contents, want 1", uri, len(res.Contents))
+	}
+	return res.Contents[0].Text
+}
+
+func TestResources(t *testing.T) {
+	dir := chSetup(t, "resources", map[string]string{
+		"shapes.go": resourcesSource,
+		// Test files, in the package and in an external test package,
+		// whose declarations are not part of the package's resource.
+		"export_test.go": "package shapes\n\nfunc ScaleForTest(s Square) Square { return scale(s, 2) }\n",
+		"shapes_test.go": "package shapes_test\n\nfunc Helper() {}\n",
+	})
+	file := filepath.Join(dir, "shapes.go")
+	outlineURI := "gopls://file/" + strings.TrimPrefix(filepath.ToSlash(file), "/") + "/outline"
+
+	t.Run("Templates", func(t *testing.T) {
+		res, err := globalSession.ListResourceTemplates(globalCtx, &mcp.ListResourceTemplatesParams{})
+		if err != nil {
+			t.Fatalf("ListResourceTemplates failed: %v", err)
+		}
+		var got []string
+		for _, rt := range res.ResourceTemplates {
+			got = append(got, rt.URITemplate)
+		}
+		for _, want := range []string{
+			"gopls://package/{+importpath}",
+			"gopls://symbol/{+importpath}/{name}",
+			"gopls://file/{+path}/outline",
+		} {
+			if !strings.Contains(strings.Join(got, "\n"), want) {
+				t.Errorf("templates %v lack %s", got, want)
+			}
+		}
+	})
+
+	// Reading the outline first also loads the module into the server.
+	t.Run("FileOutline", func(t *testing.T) {
+		text := readResource(t, outlineURI)
+		for _, want := range []string{
+			"Outline of " + file,
+			"- interface `Shape`",
+			"- struct `Square`",
+			"  - field `Side` float64 (line 10)",
+			"`(Square).Area` func() float64 (line 14)",
+			"`scale`",
+		} {
+			if !strings.Contains(text, want) {
+				t.Errorf("outline does not contain %q:\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		var uris []string
+		params := &mcp.ListResourcesParams{}
+		for {
+			res, err := globalSession.ListResources(globalCtx, params)
+			if err != nil {
+				t.Fatalf("ListResources failed: %v", err)
+			}
+			for _, r := range res.Resources {
+				uris = append(uris, r.URI)
+			}
+			if res.NextCursor == "" {
+				break
+			}
+			params = &mcp.ListResourcesParams{Cursor: res.NextCursor}
+		}
+		all := strings.Join(uris, "\n")
+		for _, want := range []string{"gopls://package/example.com/resources\n", outlineURI} {
+			if !strings.Contains(all+"\n", want) {
+				t.Errorf("resources lack %q", strings.TrimSpace(want))
+			}
+		}
+	})
+
+	t.Run("Package", func(t *testing.T) {
+		text := readResource(t, "gopls://package/example.com/resources")
+		for _, want := range []string{
+			`"example.com/resources" (package shapes)`,
+			"// Square is a square with sides of length Side.",
+			"func (s Square) Area() float64",
+		} {
+			if !strings.Contains(text, want) {
+				t.Errorf("package summary does not contain %q:\n%s", want, text)
+			}
+		}
+		if strings.Contains(text, "func scale") || strings.Contains(text, "s.Side * s.Side") {
+			t.Errorf("package summary shows unexported declarations or bodies:\n%s", text)
+		}
+		if strings.Contains(text, "ScaleForTest") {
+			t.Errorf("package summary shows declarations of _test.go files:\n%s", text)
+		}
+	})
+
+	t.Run("Symbol", func(t *testing.T) {
+		text := readResource(t, "gopls://symbol/example.com/resources/Square.Area")
+		for _, want := range []string{
+			"Defined at " + file + ":14",
+			"**Package**: `example.com/resources`",
+			"Area returns the area of the square.",
+		} {
+			if !strings.Contains(text, want) {
+				t.Errorf("symbol does not contain %q:\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		for _, uri := range []string{
+			"gopls://package/example.com/nosuchpackage",
+			"gopls://package/example.com/resources_test", // a test package, not listed
+			"gopls://symbol/example.com/resources/NoSuchSymbol",
+		} {
+			if _, err := globalSession.ReadResource(globalCtx, &mcp.ReadResourceParams{URI: uri}); err == nil {
+				t.Errorf("ReadResource(%s) succeeded, want an error", uri)
+			}
+		}
+	})
+}
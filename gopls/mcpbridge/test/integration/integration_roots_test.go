@@ -0,0 +1,106 @@
+package integration
+
+// End-to-end tests for MCP roots.
+// These start a server of their own, without dynamic views, so that a
+// directory outside -workdir is only served if it is one of the client's roots.
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/tools/gopls/mcpbridge/test/testutil"
+)
+
+const rootsSource = `package main
+
+func Beta() string {
+	return "beta"
+}
+
+func main() {
+	println(Beta())
+}
+`
+
+// startRootsServer starts a gopls-mcp process for workdir whose client
+// reports roots, and returns the client so that tests can change them.
+func startRootsServer(t *testing.T, workdir string, roots ...string) (*mcp.Client, *mcp.ClientSession) {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "roots-client", Version: "v0.0.1"}, nil)
+	for _, dir := range roots {
+		client.AddRoots(&mcp.Root{URI: fileURI(dir)})
+	}
+
+	cmd := exec.Command(globalGoplsMcpPath, "-workdir", workdir)
+	// Without dynamic views, only -workdir and the roots have views.
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "GOPMCS_ALLOW_DYNAMIC_VIEWS=") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	session, err := client.Connect(context.Background(), &mcp.CommandTransport{Command: cmd}, nil)
+	if err != nil {
+		t.Fatalf("Failed to connect to gopls-mcp: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return client, session
+}
+
+func fileURI(dir string) string {
+	return "file://" + filepath.ToSlash(dir)
+}
+
+// waitForDefinition calls go_definition for Beta in file until the result
+// reports whether a definition was found as want, since roots are applied
+// asynchronously.
+func waitForDefinition(t *testing.T, session *mcp.ClientSession, file string, want bool) string {
+	t.Helper()
+	args := map[string]any{"locator": map[string]any{"symbol_name": "Beta", "context_file": file}}
+	var content string
+	for deadline := time.Now().Add(30 * time.Second); time.Now().Before(deadline); time.Sleep(200 * time.Millisecond) {
+		res, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "go_definition", Arguments: args})
+		if err != nil {
+			t.Fatalf("Failed to call go_definition: %v", err)
+		}
+		content = testutil.ResultText(t, res, "")
+		if found := !res.IsError && strings.Contains(content, "Definition found"); found == want {
+			return content
+		}
+	}
+	t.Fatalf("go_definition for %s: want found=%v, last result:\n%s", file, want, content)
+	return ""
+}
+
+func TestMCPRoots(t *testing.T) {
+	workdir := chSetup(t, "rootsworkdir", map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	repo := chSetup(t, "rootsrepo", map[string]string{"main.go": rootsSource})
+	file := filepath.Join(repo, "main.go")
+
+	client, session := startRootsServer(t, workdir, repo)
+
+	t.Run("RootIsServed", func(t *testing.T) {
+		content := waitForDefinition(t, session, file, true)
+		if !strings.Contains(content, file+":3") {
+			t.Errorf("expected definition at %s:3, got:\n%s", file, content)
+		}
+	})
+
+	t.Run("RemovedRootIsReleased", func(t *testing.T) {
+		client.RemoveRoots(fileURI(repo))
+		content := waitForDefinition(t, session, file, false)
+		if !strings.Contains(content, "no view found") {
+			t.Errorf("expected a missing view error, got:\n%s", content)
+		}
+	})
+
+	t.Run("AddedRootIsServed", func(t *testing.T) {
+		client.AddRoots(&mcp.Root{URI: fileURI(repo)})
+		waitForDefinition(t, session, file, true)
+	})
+}
@@ -25,6 +25,10 @@ import (
 var globalSession *mcp.ClientSession
 var globalCtx context.Context
 
+// globalGoplsMcpPath is the path to the gopls-mcp binary, for tests that
+// need a server process of their own.
+var globalGoplsMcpPath string
+
 // globalGoplsMcpDir is the path to the mcpbridge directory (gopls/mcpbridge).
 // Used by tests that query the real gopls-mcp codebase rather than temp projects.
 var globalGoplsMcpDir string
@@ -57,6 +61,8 @@ func TestMain(m *testing.M) {
 		os.Exit(1)
 	}
 
+	globalGoplsMcpPath = goplsMcpPath
+
 	// Initialize globalGoplsMcpDir to the mcpbridge directory (for real-codebase tests)
 	globalGoplsMcpDir, _ = filepath.Abs("../..")
 
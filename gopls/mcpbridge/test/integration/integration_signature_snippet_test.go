@@ -0,0 +1,79 @@
+package integration
+
+// End-to-end tests for SignatureSnippet verification in the symbol locator.
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const signatureSnippetSource = `package main
+
+import "context"
+
+type Server struct{}
+
+func (s *Server) Start(ctx context.Context) error {
+	return nil
+}
+
+type Client struct{}
+
+func (c *Client) Start() {}
+
+func main() {
+	_ = (&Server{}).Start(context.Background())
+	(&Client{}).Start()
+}
+`
+
+func snippetArgs(dir, snippet string, lineHint int) map[string]any {
+	return map[string]any{
+		"locator": map[string]any{
+			"symbol_name":       "Start",
+			"context_file":      filepath.Join(dir, "main.go"),
+			"line_hint":         lineHint,
+			"signature_snippet": snippet,
+		},
+	}
+}
+
+func TestSignatureSnippet(t *testing.T) {
+	runTableDrivenTests(t, map[string]testCase{
+		"MatchingSnippetHasNoWarning": {
+			setup: func(t *testing.T) map[string]any {
+				dir := chSetup(t, "snippet", map[string]string{"main.go": signatureSnippetSource})
+				return snippetArgs(dir, "func (s *Server) Start(ctx context.Context) error", 7)
+			},
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("main.go:7"),
+				assertNotContains("signature_mismatch"),
+			},
+		},
+		"SnippetOutranksLineHint": {
+			setup: func(t *testing.T) map[string]any {
+				dir := chSetup(t, "snippet", map[string]string{"main.go": signatureSnippetSource})
+				// The line hint points at Server.Start, but the snippet
+				// clearly describes Client.Start.
+				return snippetArgs(dir, "func (c *Client) Start()", 7)
+			},
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("main.go:13"),
+				assertNotContains("signature_mismatch"),
+			},
+		},
+		"MismatchIsReported": {
+			setup: func(t *testing.T) map[string]any {
+				dir := chSetup(t, "snippet", map[string]string{"main.go": signatureSnippetSource})
+				return snippetArgs(dir, "func Start(addr string, port int) (*Listener, error)", 7)
+			},
+			tool: "go_definition",
+			assertions: []assertion{
+				assertContains("WARNING (signature_mismatch)"),
+				assertContains("func (*Server).Start(ctx context.Context) error"),
+			},
+		},
+	})
+}
@@ -0,0 +1,114 @@
+package integration
+
+// End-to-end tests for go_implementation on interfaces declared outside the
+// workspace: the standard library's io.Reader, error and fmt.Stringer.
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const stdlibImplSource = `package main
+
+import (
+	"fmt"
+	"io"
+)
+
+type Src struct{}
+
+func (Src) Read(p []byte) (int, error) { return 0, io.EOF }
+
+func (Src) String() string { return "src" }
+
+func (Src) Error() string { return "src failed" }
+
+func main() {
+	var r io.Reader = Src{}
+	fmt.Println(r)
+}
+`
+
+func TestStdlibImplementation(t *testing.T) {
+	// args builds go_implementation arguments for a symbol of stdlibImplSource.
+	args := func(t *testing.T, name string, extra map[string]any) map[string]any {
+		file := filepath.Join(chSetup(t, "stdimpl", map[string]string{"main.go": stdlibImplSource}), "main.go")
+		args := map[string]any{
+			"locator": map[string]any{"symbol_name": name, "context_file": file},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+
+	runTableDrivenTests(t, map[string]testCase{
+		"StdlibInterfaceWorkspaceOnly": {
+			setup: func(t *testing.T) map[string]any { return args(t, "Reader", nil) },
+			tool:  "go_implementation",
+			assertions: []assertion{
+				assertContains("implementation(s) for symbol 'Reader'"),
+				assertContains(". Src at "),
+				assertNotContains("**Package**: `os`"),
+			},
+		},
+		"StdlibInterfaceWithDependencies": {
+			setup: func(t *testing.T) map[string]any {
+				return args(t, "Reader", map[string]any{"include_dependencies": true})
+			},
+			tool: "go_implementation",
+			assertions: []assertion{
+				assertContains(". Src at "),
+				assertContains("**Package**: `os`"),
+				assertNotContains(". ss at "), // unexported fmt.ss
+			},
+		},
+		"InterfacesOfWorkspaceType": {
+			setup: func(t *testing.T) map[string]any { return args(t, "Src", nil) },
+			tool:  "go_implementation",
+			assertions: []assertion{
+				assertContains("Reader at "),
+				assertContains("**Package**: `io`"),
+				assertContains("Stringer at "),
+				assertContains("error at "),
+				assertContains("type error interface"),
+			},
+		},
+		"ExcludeTrivial": {
+			setup: func(t *testing.T) map[string]any {
+				return args(t, "Src", map[string]any{"exclude_trivial": true})
+			},
+			tool: "go_implementation",
+			assertions: []assertion{
+				assertContains("Reader at "),
+				assertNotContains("Stringer at "),
+				assertNotContains("error at "),
+			},
+		},
+		"WorkspaceFileNamedLikeBuiltin": {
+			// A workspace package at src/builtin is not the builtin
+			// package of predeclared identifiers.
+			setup: func(t *testing.T) map[string]any {
+				dir := chSetup(t, "lookalike", map[string]string{
+					"main.go": "package main\n\ntype Box struct{}\n\nfunc (Box) Size() int { return 0 }\n\nfunc main() {}\n",
+				})
+				pkgDir := filepath.Join(dir, "src", "builtin")
+				if err := os.MkdirAll(pkgDir, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(pkgDir, "builtin.go"), []byte("package builtin\n\ntype Sizer interface{ Size() int }\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return map[string]any{
+					"locator": map[string]any{"symbol_name": "Box", "context_file": filepath.Join(dir, "main.go")},
+				}
+			},
+			tool: "go_implementation",
+			assertions: []assertion{
+				assertContains("Sizer at "),
+				assertContains("**Package**: `example.com/lookalike/src/builtin`"),
+			},
+		},
+	})
+}
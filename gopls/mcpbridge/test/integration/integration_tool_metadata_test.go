@@ -0,0 +1,79 @@
+package integration
+
+// End-to-end tests for what tools/list publishes about each tool (title,
+// annotations, output schema) and for the structured content of results.
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolMetadata(t *testing.T) {
+	res, err := globalSession.ListTools(globalCtx, &mcp.ListToolsParams{})
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(res.Tools) == 0 {
+		t.Fatal("no tools listed")
+	}
+	for _, tool := range res.Tools {
+		if tool.Title == "" {
+			t.Errorf("%s has no title", tool.Name)
+		}
+		if tool.OutputSchema == nil {
+			t.Errorf("%s has no output schema", tool.Name)
+		}
+		a := tool.Annotations
+		if a == nil {
+			t.Errorf("%s has no annotations", tool.Name)
+			continue
+		}
+		if a.OpenWorldHint == nil || *a.OpenWorldHint {
+			t.Errorf("%s: openWorldHint = %v, want false", tool.Name, a.OpenWorldHint)
+		}
+		if tool.Name == "go_apply_rename" {
+			if a.ReadOnlyHint || a.DestructiveHint == nil || !*a.DestructiveHint {
+				t.Errorf("%s: annotations %+v, want destructive and not read-only", tool.Name, a)
+			}
+		} else if !a.ReadOnlyHint {
+			t.Errorf("%s: readOnlyHint = false, want true", tool.Name)
+		}
+	}
+}
+
+func TestStructuredContent(t *testing.T) {
+	file := filepath.Join(chSetup(t, "structured", map[string]string{"main.go": pagingSource}), "main.go")
+	res, err := globalSession.CallTool(globalCtx, &mcp.CallToolParams{
+		Name: "go_symbol_references",
+		Arguments: map[string]any{
+			"locator":   map[string]any{"symbol_name": "ping", "context_file": file},
+			"page_size": 2,
+		},
+	})
+	if err != nil || res.IsError {
+		t.Fatalf("go_symbol_references failed: %v %+v", err, res)
+	}
+	data, err := json.Marshal(res.StructuredContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Summary    string            `json:"summary"`
+		References []json.RawMessage `json:"references"`
+		TotalCount int               `json:"total_count"`
+		NextCursor string            `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("structured content %s: %v", data, err)
+	}
+	if len(out.References) != 2 || out.TotalCount != 5 || out.NextCursor == "" {
+		t.Errorf("structured content has %d references of %d, next cursor %q; want 2 of 5 and a cursor",
+			len(out.References), out.TotalCount, out.NextCursor)
+	}
+	if text := res.Content[0].(*mcp.TextContent).Text; text != out.Summary {
+		t.Errorf("text content differs from the structured summary:\n%s\n---\n%s", text, out.Summary)
+	}
+}
@@ -0,0 +1,165 @@
+package integration
+
+// End-to-end tests for go_type_hierarchy functionality.
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const typeHierarchySource = `package main
+
+import "io"
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+
+type ReadCloser interface {
+	Reader
+	Close() error
+}
+
+type File struct{}
+
+func (f *File) Read(p []byte) (int, error) { return 0, nil }
+func (f *File) Close() error                { return nil }
+
+type LoggedFile struct {
+	*File
+	prefix string
+}
+
+var _ io.Reader = (*File)(nil)
+
+type Base struct{}
+type Mid struct{ Base }
+type Top struct{ Mid }
+
+func main() {}
+`
+
+// TestGoTypeHierarchy is the single table-driven test for all type hierarchy scenarios.
+func TestGoTypeHierarchy(t *testing.T) {
+	t.Run("Subtypes", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"InterfaceEmbeddedAndImplemented": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "Reader", "interface", 5, "subtypes", 0)
+				},
+				tool: "go_type_hierarchy",
+				assertions: []assertion{
+					assertContains("Subtypes:"),
+					assertContains("[embedded-by] ReadCloser"),
+					assertContains("[implemented-by] File"),
+				},
+			},
+			"EmbeddingChainIsRecursive": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "Reader", "interface", 5, "subtypes", 3)
+				},
+				tool: "go_type_hierarchy",
+				assertions: []assertion{
+					// Reader <- File (implements) <- LoggedFile (embeds *File)
+					assertContains("[embedded-by] LoggedFile"),
+				},
+			},
+		})
+	})
+
+	t.Run("Supertypes", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"StructEmbedsAndImplements": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "LoggedFile", "struct", 19, "supertypes", 0)
+				},
+				tool: "go_type_hierarchy",
+				assertions: []assertion{
+					assertContains("Supertypes:"),
+					assertContains("[embeds] File"),
+					assertContains("[implements] ReadCloser"),
+				},
+			},
+			"InterfaceEmbedsInterface": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "ReadCloser", "interface", 9, "supertypes", 0)
+				},
+				tool: "go_type_hierarchy",
+				assertions: []assertion{
+					assertContains("[embeds] Reader"),
+				},
+			},
+			"EmbeddingChain": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "Top", "struct", 28, "supertypes", 0)
+				},
+				tool:       "go_type_hierarchy",
+				assertions: []assertion{assertContainsAll("[embeds] Mid", "[embeds] Base")},
+			},
+			"DepthLimitStopsExpansion": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "Top", "struct", 28, "supertypes", 1)
+				},
+				tool: "go_type_hierarchy",
+				assertions: []assertion{
+					assertContains("[embeds] Mid"),
+					assertCustom(
+						"does not expand beyond depth 1",
+						func(content string) bool { return !strings.Contains(content, "Base") },
+						"expected Base to be omitted with max_depth=1",
+					),
+				},
+			},
+		})
+	})
+
+	t.Run("NotAType", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"FunctionIsRejected": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "main", "function", 30, "both", 0)
+				},
+				tool:       "go_type_hierarchy",
+				assertions: []assertion{assertContainsAny("not a type", "failed")},
+			},
+		})
+	})
+
+	t.Run("InvalidDirection", func(t *testing.T) {
+		runTableDrivenTests(t, map[string]testCase{
+			"TypoIsRejected": {
+				setup: func(t *testing.T) map[string]any {
+					dir := chSetup(t, "typehier", map[string]string{"main.go": typeHierarchySource})
+					return thArgs(dir, "Reader", "interface", 5, "super", 0)
+				},
+				tool:       "go_type_hierarchy",
+				assertions: []assertion{assertContains(`invalid direction "super"`)},
+			},
+		})
+	})
+}
+
+// thArgs builds the locator args for a type hierarchy query.
+func thArgs(dir, symbol, kind string, lineHint int, direction string, maxDepth int) map[string]any {
+	args := map[string]any{
+		"locator": map[string]any{
+			"symbol_name":  symbol,
+			"context_file": filepath.Join(dir, "main.go"),
+			"kind":         kind,
+			"line_hint":    lineHint,
+		},
+		"direction": direction,
+	}
+	if maxDepth > 0 {
+		args["max_depth"] = maxDepth
+	}
+	return args
+}
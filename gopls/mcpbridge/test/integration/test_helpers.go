@@ -58,6 +58,15 @@ func assertContains(substring string) assertion {
 	}
 }
 
+// assertNotContains checks that content does not contain a substring
+func assertNotContains(substring string) assertion {
+	return assertion{
+		description: fmt.Sprintf("does not contain %q", substring),
+		check:       func(content string) bool { return !strings.Contains(content, substring) },
+		errorMsg:    fmt.Sprintf("expected content not to contain %q", substring),
+	}
+}
+
 // assertContainsAny checks that content contains at least one of the substrings
 func assertContainsAny(substrings ...string) assertion {
 	return assertion{